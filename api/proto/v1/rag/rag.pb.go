@@ -21,13 +21,111 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How a query is expanded into several queries before retrieval.
+type QueryExpansion int32
+
+const (
+	QueryExpansion_QUERY_EXPANSION_NONE        QueryExpansion = 0 // Retrieve with the query only.
+	QueryExpansion_QUERY_EXPANSION_MULTI_QUERY QueryExpansion = 1 // Also retrieve with LLM-generated paraphrases of the query.
+	QueryExpansion_QUERY_EXPANSION_HYDE        QueryExpansion = 2 // Also search vectors with an LLM-written hypothetical answer (HyDE).
+)
+
+// Enum value maps for QueryExpansion.
+var (
+	QueryExpansion_name = map[int32]string{
+		0: "QUERY_EXPANSION_NONE",
+		1: "QUERY_EXPANSION_MULTI_QUERY",
+		2: "QUERY_EXPANSION_HYDE",
+	}
+	QueryExpansion_value = map[string]int32{
+		"QUERY_EXPANSION_NONE":        0,
+		"QUERY_EXPANSION_MULTI_QUERY": 1,
+		"QUERY_EXPANSION_HYDE":        2,
+	}
+)
+
+func (x QueryExpansion) Enum() *QueryExpansion {
+	p := new(QueryExpansion)
+	*p = x
+	return p
+}
+
+func (x QueryExpansion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QueryExpansion) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_rag_rag_proto_enumTypes[0].Descriptor()
+}
+
+func (QueryExpansion) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_rag_rag_proto_enumTypes[0]
+}
+
+func (x QueryExpansion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QueryExpansion.Descriptor instead.
+func (QueryExpansion) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{0}
+}
+
+// Where a retrieved source comes from.
+type SourceType int32
+
+const (
+	SourceType_SOURCE_TYPE_DOCUMENT        SourceType = 0 // A chunk of an indexed document.
+	SourceType_SOURCE_TYPE_KNOWLEDGE_GRAPH SourceType = 1 // Facts from the user's knowledge graph; has no file or page.
+)
+
+// Enum value maps for SourceType.
+var (
+	SourceType_name = map[int32]string{
+		0: "SOURCE_TYPE_DOCUMENT",
+		1: "SOURCE_TYPE_KNOWLEDGE_GRAPH",
+	}
+	SourceType_value = map[string]int32{
+		"SOURCE_TYPE_DOCUMENT":        0,
+		"SOURCE_TYPE_KNOWLEDGE_GRAPH": 1,
+	}
+)
+
+func (x SourceType) Enum() *SourceType {
+	p := new(SourceType)
+	*p = x
+	return p
+}
+
+func (x SourceType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SourceType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_v1_rag_rag_proto_enumTypes[1].Descriptor()
+}
+
+func (SourceType) Type() protoreflect.EnumType {
+	return &file_api_proto_v1_rag_rag_proto_enumTypes[1]
+}
+
+func (x SourceType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SourceType.Descriptor instead.
+func (SourceType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{1}
+}
+
 // IndexRequest contains the information for the documents to be indexed.
 type IndexRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // The ID of the user performing the action.
 	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // The ID of the folder to index into.
 	Paths         []string               `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`                       // A list of local file paths or remote URLs.
-	Reindex       bool                   `protobuf:"varint,4,opt,name=reindex,proto3" json:"reindex,omitempty"`                  // If true, forces re-indexing.
+	Reindex       bool                   `protobuf:"varint,4,opt,name=reindex,proto3" json:"reindex,omitempty"`                  // If true, forces re-indexing even if a path's content is unchanged.
+	Crawl         *CrawlOptions          `protobuf:"bytes,5,opt,name=crawl,proto3" json:"crawl,omitempty"`                       // If set, each URL is crawled as a website instead of loaded as a single page.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,18 +188,96 @@ func (x *IndexRequest) GetReindex() bool {
 	return false
 }
 
+func (x *IndexRequest) GetCrawl() *CrawlOptions {
+	if x != nil {
+		return x.Crawl
+	}
+	return nil
+}
+
+// CrawlOptions limits which pages of a website are crawled from a start URL. Unset fields use the crawler defaults.
+type CrawlOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxDepth      int32                  `protobuf:"varint,1,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`            // Number of links followed from the start page; 0 only loads the start page.
+	MaxPages      int32                  `protobuf:"varint,2,opt,name=max_pages,json=maxPages,proto3" json:"max_pages,omitempty"`            // Maximum number of pages indexed.
+	PathPrefix    string                 `protobuf:"bytes,3,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`       // Only follow links whose path starts with this prefix. Defaults to the start URL's directory.
+	AllowedHosts  []string               `protobuf:"bytes,4,rep,name=allowed_hosts,json=allowedHosts,proto3" json:"allowed_hosts,omitempty"` // Hosts links may lead to. Defaults to the start URL's host.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlOptions) Reset() {
+	*x = CrawlOptions{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlOptions) ProtoMessage() {}
+
+func (x *CrawlOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlOptions.ProtoReflect.Descriptor instead.
+func (*CrawlOptions) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CrawlOptions) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *CrawlOptions) GetMaxPages() int32 {
+	if x != nil {
+		return x.MaxPages
+	}
+	return 0
+}
+
+func (x *CrawlOptions) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *CrawlOptions) GetAllowedHosts() []string {
+	if x != nil {
+		return x.AllowedHosts
+	}
+	return nil
+}
+
 // IndexResponse streams the progress of the indexing job.
 type IndexResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	Progress      int32                  `protobuf:"varint,2,opt,name=progress,proto3" json:"progress,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`   // The path this update is about.
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // Set if indexing the path failed; indexing continues with the next path.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *IndexResponse) Reset() {
 	*x = IndexResponse{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[1]
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -113,7 +289,7 @@ func (x *IndexResponse) String() string {
 func (*IndexResponse) ProtoMessage() {}
 
 func (x *IndexResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[1]
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -126,7 +302,7 @@ func (x *IndexResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IndexResponse.ProtoReflect.Descriptor instead.
 func (*IndexResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *IndexResponse) GetMessage() string {
@@ -143,32 +319,1866 @@ func (x *IndexResponse) GetProgress() int32 {
 	return 0
 }
 
-// QueryRequest contains the user's query.
-type QueryRequest struct {
-	state  protoimpl.MessageState `protogen:"open.v1"`
-	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // The ID of the user performing the query.
-	Query  string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`                 // The question to be answered.
-	// A list of folder IDs to search within. If empty, searches all folders for the user.
-	FolderIds     []string `protobuf:"bytes,3,rep,name=folder_ids,json=folderIds,proto3" json:"folder_ids,omitempty"`
+func (x *IndexResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *IndexResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Request for the status of an indexing job.
+type GetIndexJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIndexJobRequest) Reset() {
+	*x = GetIndexJobRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIndexJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIndexJobRequest) ProtoMessage() {}
+
+func (x *GetIndexJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIndexJobRequest.ProtoReflect.Descriptor instead.
+func (*GetIndexJobRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetIndexJobRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetIndexJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// An asynchronous indexing job.
+type IndexJob struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	JobId    string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	FolderId string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// "queued", "running", "completed" or "failed". A job fails if it could not be processed or every file failed;
+	// a completed job may still contain failed files.
+	Status        string          `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string          `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // Why the job failed, if it did not get to process its files.
+	TotalFiles    int32           `protobuf:"varint,5,opt,name=total_files,json=totalFiles,proto3" json:"total_files,omitempty"`
+	IndexedFiles  int32           `protobuf:"varint,6,opt,name=indexed_files,json=indexedFiles,proto3" json:"indexed_files,omitempty"`
+	SkippedFiles  int32           `protobuf:"varint,7,opt,name=skipped_files,json=skippedFiles,proto3" json:"skipped_files,omitempty"` // Files whose content was unchanged.
+	FailedFiles   int32           `protobuf:"varint,8,opt,name=failed_files,json=failedFiles,proto3" json:"failed_files,omitempty"`
+	Files         []*IndexJobFile `protobuf:"bytes,9,rep,name=files,proto3" json:"files,omitempty"`
+	CreatedAt     string          `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt     string          `protobuf:"bytes,11,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    string          `protobuf:"bytes,12,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexJob) Reset() {
+	*x = IndexJob{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexJob) ProtoMessage() {}
+
+func (x *IndexJob) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexJob.ProtoReflect.Descriptor instead.
+func (*IndexJob) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IndexJob) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *IndexJob) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *IndexJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *IndexJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *IndexJob) GetTotalFiles() int32 {
+	if x != nil {
+		return x.TotalFiles
+	}
+	return 0
+}
+
+func (x *IndexJob) GetIndexedFiles() int32 {
+	if x != nil {
+		return x.IndexedFiles
+	}
+	return 0
+}
+
+func (x *IndexJob) GetSkippedFiles() int32 {
+	if x != nil {
+		return x.SkippedFiles
+	}
+	return 0
+}
+
+func (x *IndexJob) GetFailedFiles() int32 {
+	if x != nil {
+		return x.FailedFiles
+	}
+	return 0
+}
+
+func (x *IndexJob) GetFiles() []*IndexJobFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *IndexJob) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *IndexJob) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *IndexJob) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+// The result of one file of an indexing job.
+type IndexJobFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "pending", "indexing", "indexed", "skipped" or "failed".
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	ChunkCount    int32                  `protobuf:"varint,4,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IndexJobFile) Reset() {
+	*x = IndexJobFile{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexJobFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexJobFile) ProtoMessage() {}
+
+func (x *IndexJobFile) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexJobFile.ProtoReflect.Descriptor instead.
+func (*IndexJobFile) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IndexJobFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *IndexJobFile) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *IndexJobFile) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *IndexJobFile) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+// QueryRequest contains the user's query.
+type QueryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // The ID of the user performing the query.
+	Query  string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`                 // The question to be answered.
+	// A list of folder IDs to search within. If empty, searches all folders for the user.
+	FolderIds []string `protobuf:"bytes,3,rep,name=folder_ids,json=folderIds,proto3" json:"folder_ids,omitempty"`
+	// Weights of vector search and BM25 keyword search in reciprocal-rank fusion.
+	// A weight of 0 disables that retriever; if both are 0, they are weighted equally.
+	VectorWeight  float32 `protobuf:"fixed32,4,opt,name=vector_weight,json=vectorWeight,proto3" json:"vector_weight,omitempty"`
+	KeywordWeight float32 `protobuf:"fixed32,5,opt,name=keyword_weight,json=keywordWeight,proto3" json:"keyword_weight,omitempty"`
+	// Metadata filters that every retrieved chunk must match. Filters are combined with AND.
+	Filters []*MetadataFilter `protobuf:"bytes,6,rep,name=filters,proto3" json:"filters,omitempty"`
+	// Earlier turns of the conversation, oldest first. If set, the query is treated as a follow-up and rewritten
+	// into a standalone question before retrieval.
+	History []*ChatTurn `protobuf:"bytes,7,rep,name=history,proto3" json:"history,omitempty"`
+	// Additional queries to retrieve with; their results are fused with those of the query itself.
+	Expansion QueryExpansion `protobuf:"varint,8,opt,name=expansion,proto3,enum=v1.rag.QueryExpansion" json:"expansion,omitempty"`
+	// The number of paraphrases generated for QUERY_EXPANSION_MULTI_QUERY. Defaults to 3, at most 5.
+	NumQueryVariants int32 `protobuf:"varint,9,opt,name=num_query_variants,json=numQueryVariants,proto3" json:"num_query_variants,omitempty"`
+	// Also looks up the entities of the query in the user's knowledge graph, built by the memory service, and adds
+	// the facts found within a few hops as an extra source of type SOURCE_TYPE_KNOWLEDGE_GRAPH.
+	// Requires graph retrieval to be enabled on the server.
+	GraphRetrieval bool `protobuf:"varint,10,opt,name=graph_retrieval,json=graphRetrieval,proto3" json:"graph_retrieval,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRequest) ProtoMessage() {}
+
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetFolderIds() []string {
+	if x != nil {
+		return x.FolderIds
+	}
+	return nil
+}
+
+func (x *QueryRequest) GetVectorWeight() float32 {
+	if x != nil {
+		return x.VectorWeight
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetKeywordWeight() float32 {
+	if x != nil {
+		return x.KeywordWeight
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetFilters() []*MetadataFilter {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *QueryRequest) GetHistory() []*ChatTurn {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *QueryRequest) GetExpansion() QueryExpansion {
+	if x != nil {
+		return x.Expansion
+	}
+	return QueryExpansion_QUERY_EXPANSION_NONE
+}
+
+func (x *QueryRequest) GetNumQueryVariants() int32 {
+	if x != nil {
+		return x.NumQueryVariants
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetGraphRetrieval() bool {
+	if x != nil {
+		return x.GraphRetrieval
+	}
+	return false
+}
+
+// A previous message in a conversation.
+type ChatTurn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"` // "user" or "assistant".
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatTurn) Reset() {
+	*x = ChatTurn{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatTurn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatTurn) ProtoMessage() {}
+
+func (x *ChatTurn) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatTurn.ProtoReflect.Descriptor instead.
+func (*ChatTurn) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ChatTurn) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ChatTurn) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// A predicate over a scalar field stored with each chunk.
+type MetadataFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The field to filter on: one of "doc_type", "file_name", "page_label", "folder_id", "page_number" or
+	// "indexed_at". Values of "page_number" and "indexed_at" (Unix seconds) must be integers.
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Types that are valid to be assigned to Predicate:
+	//
+	//	*MetadataFilter_Equals
+	//	*MetadataFilter_In
+	//	*MetadataFilter_Range
+	Predicate     isMetadataFilter_Predicate `protobuf_oneof:"predicate"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataFilter) Reset() {
+	*x = MetadataFilter{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataFilter) ProtoMessage() {}
+
+func (x *MetadataFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataFilter.ProtoReflect.Descriptor instead.
+func (*MetadataFilter) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MetadataFilter) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *MetadataFilter) GetPredicate() isMetadataFilter_Predicate {
+	if x != nil {
+		return x.Predicate
+	}
+	return nil
+}
+
+func (x *MetadataFilter) GetEquals() string {
+	if x != nil {
+		if x, ok := x.Predicate.(*MetadataFilter_Equals); ok {
+			return x.Equals
+		}
+	}
+	return ""
+}
+
+func (x *MetadataFilter) GetIn() *StringList {
+	if x != nil {
+		if x, ok := x.Predicate.(*MetadataFilter_In); ok {
+			return x.In
+		}
+	}
+	return nil
+}
+
+func (x *MetadataFilter) GetRange() *RangeFilter {
+	if x != nil {
+		if x, ok := x.Predicate.(*MetadataFilter_Range); ok {
+			return x.Range
+		}
+	}
+	return nil
+}
+
+type isMetadataFilter_Predicate interface {
+	isMetadataFilter_Predicate()
+}
+
+type MetadataFilter_Equals struct {
+	Equals string `protobuf:"bytes,2,opt,name=equals,proto3,oneof"` // The field equals this value.
+}
+
+type MetadataFilter_In struct {
+	In *StringList `protobuf:"bytes,3,opt,name=in,proto3,oneof"` // The field equals any of these values.
+}
+
+type MetadataFilter_Range struct {
+	Range *RangeFilter `protobuf:"bytes,4,opt,name=range,proto3,oneof"` // The field lies within this range.
+}
+
+func (*MetadataFilter_Equals) isMetadataFilter_Predicate() {}
+
+func (*MetadataFilter_In) isMetadataFilter_Predicate() {}
+
+func (*MetadataFilter_Range) isMetadataFilter_Predicate() {}
+
+type StringList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringList) Reset() {
+	*x = StringList{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringList) ProtoMessage() {}
+
+func (x *StringList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringList.ProtoReflect.Descriptor instead.
+func (*StringList) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StringList) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Bounds of a range predicate. Unset bounds are open. Only the numeric fields "page_number" and "indexed_at" can be
+// filtered by range, and their bounds are compared as integers.
+type RangeFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gt            *string                `protobuf:"bytes,1,opt,name=gt,proto3,oneof" json:"gt,omitempty"`
+	Gte           *string                `protobuf:"bytes,2,opt,name=gte,proto3,oneof" json:"gte,omitempty"`
+	Lt            *string                `protobuf:"bytes,3,opt,name=lt,proto3,oneof" json:"lt,omitempty"`
+	Lte           *string                `protobuf:"bytes,4,opt,name=lte,proto3,oneof" json:"lte,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RangeFilter) Reset() {
+	*x = RangeFilter{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RangeFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RangeFilter) ProtoMessage() {}
+
+func (x *RangeFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RangeFilter.ProtoReflect.Descriptor instead.
+func (*RangeFilter) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RangeFilter) GetGt() string {
+	if x != nil && x.Gt != nil {
+		return *x.Gt
+	}
+	return ""
+}
+
+func (x *RangeFilter) GetGte() string {
+	if x != nil && x.Gte != nil {
+		return *x.Gte
+	}
+	return ""
+}
+
+func (x *RangeFilter) GetLt() string {
+	if x != nil && x.Lt != nil {
+		return *x.Lt
+	}
+	return ""
+}
+
+func (x *RangeFilter) GetLte() string {
+	if x != nil && x.Lte != nil {
+		return *x.Lte
+	}
+	return ""
+}
+
+// RetrievedDocument represents a chunk of a source document relevant to the query.
+type RetrievedDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Score         float32                `protobuf:"fixed32,3,opt,name=score,proto3" json:"score,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SourceType    SourceType             `protobuf:"varint,5,opt,name=source_type,json=sourceType,proto3,enum=v1.rag.SourceType" json:"source_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetrievedDocument) Reset() {
+	*x = RetrievedDocument{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetrievedDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetrievedDocument) ProtoMessage() {}
+
+func (x *RetrievedDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetrievedDocument.ProtoReflect.Descriptor instead.
+func (*RetrievedDocument) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RetrievedDocument) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RetrievedDocument) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *RetrievedDocument) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RetrievedDocument) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RetrievedDocument) GetSourceType() SourceType {
+	if x != nil {
+		return x.SourceType
+	}
+	return SourceType_SOURCE_TYPE_DOCUMENT
+}
+
+// QueryResponse contains the answer and the source documents.
+type QueryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The answer, with inline citation markers like [1] or [2, 3] that refer to citations by marker.
+	Answer  string               `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Sources []*RetrievedDocument `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	// The sources the answer actually cites, in order of first use.
+	Citations []*Citation `protobuf:"bytes,3,rep,name=citations,proto3" json:"citations,omitempty"`
+	// The query retrieval was run with: the original query, or its standalone rewrite if history was given.
+	StandaloneQuery string `protobuf:"bytes,4,opt,name=standalone_query,json=standaloneQuery,proto3" json:"standalone_query,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse) ProtoMessage() {}
+
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QueryResponse) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *QueryResponse) GetSources() []*RetrievedDocument {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetCitations() []*Citation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetStandaloneQuery() string {
+	if x != nil {
+		return x.StandaloneQuery
+	}
+	return ""
+}
+
+// One event of a streamed query.
+type QueryStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*QueryStreamResponse_Sources
+	//	*QueryStreamResponse_Token
+	//	*QueryStreamResponse_Completed
+	Event         isQueryStreamResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryStreamResponse) Reset() {
+	*x = QueryStreamResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryStreamResponse) ProtoMessage() {}
+
+func (x *QueryStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryStreamResponse.ProtoReflect.Descriptor instead.
+func (*QueryStreamResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QueryStreamResponse) GetEvent() isQueryStreamResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *QueryStreamResponse) GetSources() *QuerySources {
+	if x != nil {
+		if x, ok := x.Event.(*QueryStreamResponse_Sources); ok {
+			return x.Sources
+		}
+	}
+	return nil
+}
+
+func (x *QueryStreamResponse) GetToken() string {
+	if x != nil {
+		if x, ok := x.Event.(*QueryStreamResponse_Token); ok {
+			return x.Token
+		}
+	}
+	return ""
+}
+
+func (x *QueryStreamResponse) GetCompleted() *QueryCompleted {
+	if x != nil {
+		if x, ok := x.Event.(*QueryStreamResponse_Completed); ok {
+			return x.Completed
+		}
+	}
+	return nil
+}
+
+type isQueryStreamResponse_Event interface {
+	isQueryStreamResponse_Event()
+}
+
+type QueryStreamResponse_Sources struct {
+	Sources *QuerySources `protobuf:"bytes,1,opt,name=sources,proto3,oneof"` // Sent once, before any token.
+}
+
+type QueryStreamResponse_Token struct {
+	Token string `protobuf:"bytes,2,opt,name=token,proto3,oneof"` // A piece of the answer as generated by the LLM.
+}
+
+type QueryStreamResponse_Completed struct {
+	Completed *QueryCompleted `protobuf:"bytes,3,opt,name=completed,proto3,oneof"` // Sent once, last.
+}
+
+func (*QueryStreamResponse_Sources) isQueryStreamResponse_Event() {}
+
+func (*QueryStreamResponse_Token) isQueryStreamResponse_Event() {}
+
+func (*QueryStreamResponse_Completed) isQueryStreamResponse_Event() {}
+
+type QuerySources struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []*RetrievedDocument   `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuerySources) Reset() {
+	*x = QuerySources{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuerySources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuerySources) ProtoMessage() {}
+
+func (x *QuerySources) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuerySources.ProtoReflect.Descriptor instead.
+func (*QuerySources) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *QuerySources) GetSources() []*RetrievedDocument {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type QueryCompleted struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The full answer with citation markers that do not refer to a source removed.
+	Answer    string        `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
+	Citations []*Citation   `protobuf:"bytes,2,rep,name=citations,proto3" json:"citations,omitempty"`
+	Timings   *QueryTimings `protobuf:"bytes,3,opt,name=timings,proto3" json:"timings,omitempty"`
+	// The query retrieval was run with: the original query, or its standalone rewrite if history was given.
+	StandaloneQuery string `protobuf:"bytes,4,opt,name=standalone_query,json=standaloneQuery,proto3" json:"standalone_query,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *QueryCompleted) Reset() {
+	*x = QueryCompleted{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryCompleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryCompleted) ProtoMessage() {}
+
+func (x *QueryCompleted) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryCompleted.ProtoReflect.Descriptor instead.
+func (*QueryCompleted) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QueryCompleted) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
+}
+
+func (x *QueryCompleted) GetCitations() []*Citation {
+	if x != nil {
+		return x.Citations
+	}
+	return nil
+}
+
+func (x *QueryCompleted) GetTimings() *QueryTimings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
+func (x *QueryCompleted) GetStandaloneQuery() string {
+	if x != nil {
+		return x.StandaloneQuery
+	}
+	return ""
+}
+
+// Durations of the stages of a query, in milliseconds.
+type QueryTimings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RetrievalMs   int64                  `protobuf:"varint,1,opt,name=retrieval_ms,json=retrievalMs,proto3" json:"retrieval_ms,omitempty"`      // Retrieval, fusion and reranking.
+	FirstTokenMs  int64                  `protobuf:"varint,2,opt,name=first_token_ms,json=firstTokenMs,proto3" json:"first_token_ms,omitempty"` // From the start of the query until the first answer token.
+	GenerationMs  int64                  `protobuf:"varint,3,opt,name=generation_ms,json=generationMs,proto3" json:"generation_ms,omitempty"`   // Answer generation.
+	TotalMs       int64                  `protobuf:"varint,4,opt,name=total_ms,json=totalMs,proto3" json:"total_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryTimings) Reset() {
+	*x = QueryTimings{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryTimings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryTimings) ProtoMessage() {}
+
+func (x *QueryTimings) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryTimings.ProtoReflect.Descriptor instead.
+func (*QueryTimings) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *QueryTimings) GetRetrievalMs() int64 {
+	if x != nil {
+		return x.RetrievalMs
+	}
+	return 0
+}
+
+func (x *QueryTimings) GetFirstTokenMs() int64 {
+	if x != nil {
+		return x.FirstTokenMs
+	}
+	return 0
+}
+
+func (x *QueryTimings) GetGenerationMs() int64 {
+	if x != nil {
+		return x.GenerationMs
+	}
+	return 0
+}
+
+func (x *QueryTimings) GetTotalMs() int64 {
+	if x != nil {
+		return x.TotalMs
+	}
+	return 0
+}
+
+// Links an inline citation marker in an answer to a retrieved document.
+type Citation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Marker        int32                  `protobuf:"varint,1,opt,name=marker,proto3" json:"marker,omitempty"`                          // The number used in the answer, e.g. 2 for "[2]"; also the 1-based position in sources.
+	DocumentId    string                 `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"` // The ID of the cited document in sources.
+	FileName      string                 `protobuf:"bytes,3,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	PageLabel     string                 `protobuf:"bytes,4,opt,name=page_label,json=pageLabel,proto3" json:"page_label,omitempty"`
+	ChunkNumber   int32                  `protobuf:"varint,5,opt,name=chunk_number,json=chunkNumber,proto3" json:"chunk_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Citation) Reset() {
+	*x = Citation{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Citation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Citation) ProtoMessage() {}
+
+func (x *Citation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Citation.ProtoReflect.Descriptor instead.
+func (*Citation) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Citation) GetMarker() int32 {
+	if x != nil {
+		return x.Marker
+	}
+	return 0
+}
+
+func (x *Citation) GetDocumentId() string {
+	if x != nil {
+		return x.DocumentId
+	}
+	return ""
+}
+
+func (x *Citation) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Citation) GetPageLabel() string {
+	if x != nil {
+		return x.PageLabel
+	}
+	return ""
+}
+
+func (x *Citation) GetChunkNumber() int32 {
+	if x != nil {
+		return x.ChunkNumber
+	}
+	return 0
+}
+
+// A folder object.
+type Folder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Sync          *FolderSync            `protobuf:"bytes,4,opt,name=sync,proto3" json:"sync,omitempty"` // The folder's sync source and the result of its last run, if it has one.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Folder) Reset() {
+	*x = Folder{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Folder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Folder) ProtoMessage() {}
+
+func (x *Folder) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
+func (*Folder) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Folder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Folder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Folder) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Folder) GetSync() *FolderSync {
+	if x != nil {
+		return x.Sync
+	}
+	return nil
+}
+
+// Request to create a new folder.
+type CreateFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderRequest) Reset() {
+	*x = CreateFolderRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderRequest) ProtoMessage() {}
+
+func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
+func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateFolderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateFolderRequest) GetFolderName() string {
+	if x != nil {
+		return x.FolderName
+	}
+	return ""
+}
+
+// Response containing a single folder.
+type FolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FolderResponse) Reset() {
+	*x = FolderResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FolderResponse) ProtoMessage() {}
+
+func (x *FolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FolderResponse.ProtoReflect.Descriptor instead.
+func (*FolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FolderResponse) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+// Request to list folders for a user.
+type ListFoldersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFoldersRequest) Reset() {
+	*x = ListFoldersRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFoldersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersRequest) ProtoMessage() {}
+
+func (x *ListFoldersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersRequest.ProtoReflect.Descriptor instead.
+func (*ListFoldersRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListFoldersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Response containing a list of folders.
+type ListFoldersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFoldersResponse) Reset() {
+	*x = ListFoldersResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFoldersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFoldersResponse) ProtoMessage() {}
+
+func (x *ListFoldersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFoldersResponse.ProtoReflect.Descriptor instead.
+func (*ListFoldersResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListFoldersResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+// Request to delete a folder.
+type DeleteFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderRequest) ProtoMessage() {}
+
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteFolderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+// Response to a folder deletion.
+type DeleteFolderResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DeletedDocuments int32                  `protobuf:"varint,1,opt,name=deleted_documents,json=deletedDocuments,proto3" json:"deleted_documents,omitempty"` // The number of documents removed with the folder.
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeleteFolderResponse) Reset() {
+	*x = DeleteFolderResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderResponse) ProtoMessage() {}
+
+func (x *DeleteFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFolderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteFolderResponse) GetDeletedDocuments() int32 {
+	if x != nil {
+		return x.DeletedDocuments
+	}
+	return 0
+}
+
+// An indexed document (a file or URL) in a folder.
+type Document struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                              // The file path or URL the document was loaded from.
+	ContentHash   string                 `protobuf:"bytes,4,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"` // SHA-256 of the loaded content, used to skip unchanged documents.
+	ChunkCount    int32                  `protobuf:"varint,5,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // "indexing", "indexed" or "failed".
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`   // The error of the last failed indexing attempt.
+	IndexedAt     string                 `protobuf:"bytes,8,opt,name=indexed_at,json=indexedAt,proto3" json:"indexed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Document) Reset() {
+	*x = Document{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Document) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Document) ProtoMessage() {}
+
+func (x *Document) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Document.ProtoReflect.Descriptor instead.
+func (*Document) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Document) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Document) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *Document) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Document) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *Document) GetChunkCount() int32 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *Document) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Document) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Document) GetIndexedAt() string {
+	if x != nil {
+		return x.IndexedAt
+	}
+	return ""
+}
+
+// Request to list the documents in a folder.
+type ListDocumentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentsRequest) Reset() {
+	*x = ListDocumentsRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentsRequest) ProtoMessage() {}
+
+func (x *ListDocumentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentsRequest.ProtoReflect.Descriptor instead.
+func (*ListDocumentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListDocumentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListDocumentsRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+// Response containing a list of documents.
+type ListDocumentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Documents     []*Document            `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentsResponse) Reset() {
+	*x = ListDocumentsResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentsResponse) ProtoMessage() {}
+
+func (x *ListDocumentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentsResponse.ProtoReflect.Descriptor instead.
+func (*ListDocumentsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListDocumentsResponse) GetDocuments() []*Document {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+// Request to delete a document.
+type DeleteDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DocumentId    string                 `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDocumentRequest) Reset() {
+	*x = DeleteDocumentRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDocumentRequest) ProtoMessage() {}
+
+func (x *DeleteDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDocumentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteDocumentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteDocumentRequest) GetDocumentId() string {
+	if x != nil {
+		return x.DocumentId
+	}
+	return ""
+}
+
+// Response to a document deletion.
+type DeleteDocumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedChunks int32                  `protobuf:"varint,1,opt,name=deleted_chunks,json=deletedChunks,proto3" json:"deleted_chunks,omitempty"` // The number of chunks purged.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDocumentResponse) Reset() {
+	*x = DeleteDocumentResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDocumentResponse) ProtoMessage() {}
+
+func (x *DeleteDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDocumentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteDocumentResponse) GetDeletedChunks() int32 {
+	if x != nil {
+		return x.DeletedChunks
+	}
+	return 0
+}
+
+// Request to re-index the documents of a folder.
+type ReindexRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Force         bool                   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"` // If true, re-indexes documents even if their content is unchanged.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *QueryRequest) Reset() {
-	*x = QueryRequest{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[2]
+func (x *ReindexRequest) Reset() {
+	*x = ReindexRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *QueryRequest) String() string {
+func (x *ReindexRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryRequest) ProtoMessage() {}
+func (*ReindexRequest) ProtoMessage() {}
 
-func (x *QueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[2]
+func (x *ReindexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -179,58 +2189,68 @@ func (x *QueryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
-func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ReindexRequest.ProtoReflect.Descriptor instead.
+func (*ReindexRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *QueryRequest) GetUserId() string {
+func (x *ReindexRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *QueryRequest) GetQuery() string {
+func (x *ReindexRequest) GetFolderId() string {
 	if x != nil {
-		return x.Query
+		return x.FolderId
 	}
 	return ""
 }
 
-func (x *QueryRequest) GetFolderIds() []string {
+func (x *ReindexRequest) GetForce() bool {
 	if x != nil {
-		return x.FolderIds
+		return x.Force
 	}
-	return nil
-}
-
-// RetrievedDocument represents a chunk of a source document relevant to the query.
-type RetrievedDocument struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	Score         float32                `protobuf:"fixed32,3,opt,name=score,proto3" json:"score,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return false
 }
 
-func (x *RetrievedDocument) Reset() {
-	*x = RetrievedDocument{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[3]
+// The source a folder is kept in sync with, and the status of the sync.
+type FolderSync struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*FolderSync_Directory
+	//	*FolderSync_Minio
+	Source          isFolderSync_Source `protobuf_oneof:"source"`
+	IntervalSeconds int32               `protobuf:"varint,3,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // How often the source is polled for changes.
+	Status          string              `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                           // "idle", "running" or "failed".
+	LastRunAt       string              `protobuf:"bytes,5,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	LastError       string              `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"` // Why the last run failed, if it did.
+	Added           int32               `protobuf:"varint,7,opt,name=added,proto3" json:"added,omitempty"`                         // Files indexed for the first time by the last run.
+	Updated         int32               `protobuf:"varint,8,opt,name=updated,proto3" json:"updated,omitempty"`                     // Changed files re-indexed by the last run.
+	Deleted         int32               `protobuf:"varint,9,opt,name=deleted,proto3" json:"deleted,omitempty"`                     // Documents deleted by the last run because their files were removed.
+	Unchanged       int32               `protobuf:"varint,10,opt,name=unchanged,proto3" json:"unchanged,omitempty"`
+	Failed          int32               `protobuf:"varint,11,opt,name=failed,proto3" json:"failed,omitempty"` // Files the last run failed to index.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FolderSync) Reset() {
+	*x = FolderSync{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RetrievedDocument) String() string {
+func (x *FolderSync) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RetrievedDocument) ProtoMessage() {}
+func (*FolderSync) ProtoMessage() {}
 
-func (x *RetrievedDocument) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[3]
+func (x *FolderSync) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,117 +2261,139 @@ func (x *RetrievedDocument) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RetrievedDocument.ProtoReflect.Descriptor instead.
-func (*RetrievedDocument) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use FolderSync.ProtoReflect.Descriptor instead.
+func (*FolderSync) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *RetrievedDocument) GetId() string {
+func (x *FolderSync) GetSource() isFolderSync_Source {
 	if x != nil {
-		return x.Id
+		return x.Source
 	}
-	return ""
+	return nil
 }
 
-func (x *RetrievedDocument) GetText() string {
+func (x *FolderSync) GetDirectory() string {
 	if x != nil {
-		return x.Text
+		if x, ok := x.Source.(*FolderSync_Directory); ok {
+			return x.Directory
+		}
 	}
 	return ""
 }
 
-func (x *RetrievedDocument) GetScore() float32 {
+func (x *FolderSync) GetMinio() *MinIOSource {
 	if x != nil {
-		return x.Score
+		if x, ok := x.Source.(*FolderSync_Minio); ok {
+			return x.Minio
+		}
 	}
-	return 0
+	return nil
 }
 
-func (x *RetrievedDocument) GetMetadata() map[string]string {
+func (x *FolderSync) GetIntervalSeconds() int32 {
 	if x != nil {
-		return x.Metadata
+		return x.IntervalSeconds
 	}
-	return nil
+	return 0
 }
 
-// QueryResponse contains the answer and the source documents.
-type QueryResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Answer        string                 `protobuf:"bytes,1,opt,name=answer,proto3" json:"answer,omitempty"`
-	Sources       []*RetrievedDocument   `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FolderSync) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-func (x *QueryResponse) Reset() {
-	*x = QueryResponse{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *FolderSync) GetLastRunAt() string {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return ""
 }
 
-func (x *QueryResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *FolderSync) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
 }
 
-func (*QueryResponse) ProtoMessage() {}
+func (x *FolderSync) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
 
-func (x *QueryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[4]
+func (x *FolderSync) GetUpdated() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Updated
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
-func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{4}
+func (x *FolderSync) GetDeleted() int32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
 }
 
-func (x *QueryResponse) GetAnswer() string {
+func (x *FolderSync) GetUnchanged() int32 {
 	if x != nil {
-		return x.Answer
+		return x.Unchanged
 	}
-	return ""
+	return 0
 }
 
-func (x *QueryResponse) GetSources() []*RetrievedDocument {
+func (x *FolderSync) GetFailed() int32 {
 	if x != nil {
-		return x.Sources
+		return x.Failed
 	}
-	return nil
+	return 0
 }
 
-// A folder object.
-type Folder struct {
+type isFolderSync_Source interface {
+	isFolderSync_Source()
+}
+
+type FolderSync_Directory struct {
+	Directory string `protobuf:"bytes,1,opt,name=directory,proto3,oneof"` // A local directory, scanned recursively.
+}
+
+type FolderSync_Minio struct {
+	Minio *MinIOSource `protobuf:"bytes,2,opt,name=minio,proto3,oneof"` // A MinIO bucket prefix.
+}
+
+func (*FolderSync_Directory) isFolderSync_Source() {}
+
+func (*FolderSync_Minio) isFolderSync_Source() {}
+
+// A prefix in a MinIO bucket.
+type MinIOSource struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Bucket        string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Prefix        string                 `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Folder) Reset() {
-	*x = Folder{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[5]
+func (x *MinIOSource) Reset() {
+	*x = MinIOSource{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Folder) String() string {
+func (x *MinIOSource) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Folder) ProtoMessage() {}
+func (*MinIOSource) ProtoMessage() {}
 
-func (x *Folder) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[5]
+func (x *MinIOSource) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -362,56 +2404,50 @@ func (x *Folder) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
-func (*Folder) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *Folder) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+// Deprecated: Use MinIOSource.ProtoReflect.Descriptor instead.
+func (*MinIOSource) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *Folder) GetName() string {
+func (x *MinIOSource) GetBucket() string {
 	if x != nil {
-		return x.Name
+		return x.Bucket
 	}
 	return ""
 }
 
-func (x *Folder) GetCreatedAt() string {
+func (x *MinIOSource) GetPrefix() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Prefix
 	}
 	return ""
 }
 
-// Request to create a new folder.
-type CreateFolderRequest struct {
+// Request to bind a sync source to a folder.
+type SetFolderSyncRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Sync          *FolderSync            `protobuf:"bytes,3,opt,name=sync,proto3" json:"sync,omitempty"` // The source and interval; the status fields are ignored.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateFolderRequest) Reset() {
-	*x = CreateFolderRequest{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[6]
+func (x *SetFolderSyncRequest) Reset() {
+	*x = SetFolderSyncRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateFolderRequest) String() string {
+func (x *SetFolderSyncRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFolderRequest) ProtoMessage() {}
+func (*SetFolderSyncRequest) ProtoMessage() {}
 
-func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[6]
+func (x *SetFolderSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,48 +2458,56 @@ func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
-func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SetFolderSyncRequest.ProtoReflect.Descriptor instead.
+func (*SetFolderSyncRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *CreateFolderRequest) GetUserId() string {
+func (x *SetFolderSyncRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *CreateFolderRequest) GetFolderName() string {
+func (x *SetFolderSyncRequest) GetFolderId() string {
 	if x != nil {
-		return x.FolderName
+		return x.FolderId
 	}
 	return ""
 }
 
-// Response containing a single folder.
-type FolderResponse struct {
+func (x *SetFolderSyncRequest) GetSync() *FolderSync {
+	if x != nil {
+		return x.Sync
+	}
+	return nil
+}
+
+// Request to unbind the sync source of a folder.
+type DeleteFolderSyncRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FolderResponse) Reset() {
-	*x = FolderResponse{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[7]
+func (x *DeleteFolderSyncRequest) Reset() {
+	*x = DeleteFolderSyncRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FolderResponse) String() string {
+func (x *DeleteFolderSyncRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FolderResponse) ProtoMessage() {}
+func (*DeleteFolderSyncRequest) ProtoMessage() {}
 
-func (x *FolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[7]
+func (x *DeleteFolderSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -474,41 +2518,47 @@ func (x *FolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FolderResponse.ProtoReflect.Descriptor instead.
-func (*FolderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DeleteFolderSyncRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderSyncRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *FolderResponse) GetFolder() *Folder {
+func (x *DeleteFolderSyncRequest) GetUserId() string {
 	if x != nil {
-		return x.Folder
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-// Request to list folders for a user.
-type ListFoldersRequest struct {
+func (x *DeleteFolderSyncRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+// Response to unbinding a sync source.
+type DeleteFolderSyncResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListFoldersRequest) Reset() {
-	*x = ListFoldersRequest{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[8]
+func (x *DeleteFolderSyncResponse) Reset() {
+	*x = DeleteFolderSyncResponse{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListFoldersRequest) String() string {
+func (x *DeleteFolderSyncResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListFoldersRequest) ProtoMessage() {}
+func (*DeleteFolderSyncResponse) ProtoMessage() {}
 
-func (x *ListFoldersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[8]
+func (x *DeleteFolderSyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -519,41 +2569,35 @@ func (x *ListFoldersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListFoldersRequest.ProtoReflect.Descriptor instead.
-func (*ListFoldersRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *ListFoldersRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use DeleteFolderSyncResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFolderSyncResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{35}
 }
 
-// Response containing a list of folders.
-type ListFoldersResponse struct {
+// Request to sync a folder now.
+type SyncFolderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListFoldersResponse) Reset() {
-	*x = ListFoldersResponse{}
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[9]
+func (x *SyncFolderRequest) Reset() {
+	*x = SyncFolderRequest{}
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListFoldersResponse) String() string {
+func (x *SyncFolderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListFoldersResponse) ProtoMessage() {}
+func (*SyncFolderRequest) ProtoMessage() {}
 
-func (x *ListFoldersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[9]
+func (x *SyncFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_v1_rag_rag_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -564,52 +2608,154 @@ func (x *ListFoldersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListFoldersResponse.ProtoReflect.Descriptor instead.
-func (*ListFoldersResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SyncFolderRequest.ProtoReflect.Descriptor instead.
+func (*SyncFolderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_v1_rag_rag_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ListFoldersResponse) GetFolders() []*Folder {
+func (x *SyncFolderRequest) GetUserId() string {
 	if x != nil {
-		return x.Folders
+		return x.UserId
 	}
-	return nil
+	return ""
+}
+
+func (x *SyncFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
 }
 
 var File_api_proto_v1_rag_rag_proto protoreflect.FileDescriptor
 
 const file_api_proto_v1_rag_rag_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapi/proto/v1/rag/rag.proto\x12\x06v1.rag\"t\n" +
+	"\x1aapi/proto/v1/rag/rag.proto\x12\x06v1.rag\"\xa0\x01\n" +
 	"\fIndexRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x14\n" +
 	"\x05paths\x18\x03 \x03(\tR\x05paths\x12\x18\n" +
-	"\areindex\x18\x04 \x01(\bR\areindex\"E\n" +
+	"\areindex\x18\x04 \x01(\bR\areindex\x12*\n" +
+	"\x05crawl\x18\x05 \x01(\v2\x14.v1.rag.CrawlOptionsR\x05crawl\"\x8e\x01\n" +
+	"\fCrawlOptions\x12\x1b\n" +
+	"\tmax_depth\x18\x01 \x01(\x05R\bmaxDepth\x12\x1b\n" +
+	"\tmax_pages\x18\x02 \x01(\x05R\bmaxPages\x12\x1f\n" +
+	"\vpath_prefix\x18\x03 \x01(\tR\n" +
+	"pathPrefix\x12#\n" +
+	"\rallowed_hosts\x18\x04 \x03(\tR\fallowedHosts\"o\n" +
 	"\rIndexResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1a\n" +
-	"\bprogress\x18\x02 \x01(\x05R\bprogress\"\\\n" +
+	"\bprogress\x18\x02 \x01(\x05R\bprogress\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"D\n" +
+	"\x12GetIndexJobRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\"\x85\x03\n" +
+	"\bIndexJob\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1f\n" +
+	"\vtotal_files\x18\x05 \x01(\x05R\n" +
+	"totalFiles\x12#\n" +
+	"\rindexed_files\x18\x06 \x01(\x05R\findexedFiles\x12#\n" +
+	"\rskipped_files\x18\a \x01(\x05R\fskippedFiles\x12!\n" +
+	"\ffailed_files\x18\b \x01(\x05R\vfailedFiles\x12*\n" +
+	"\x05files\x18\t \x03(\v2\x14.v1.rag.IndexJobFileR\x05files\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\v \x01(\tR\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\f \x01(\tR\n" +
+	"finishedAt\"q\n" +
+	"\fIndexJobFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1f\n" +
+	"\vchunk_count\x18\x04 \x01(\x05R\n" +
+	"chunkCount\"\x93\x03\n" +
 	"\fQueryRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1d\n" +
 	"\n" +
-	"folder_ids\x18\x03 \x03(\tR\tfolderIds\"\xcf\x01\n" +
+	"folder_ids\x18\x03 \x03(\tR\tfolderIds\x12#\n" +
+	"\rvector_weight\x18\x04 \x01(\x02R\fvectorWeight\x12%\n" +
+	"\x0ekeyword_weight\x18\x05 \x01(\x02R\rkeywordWeight\x120\n" +
+	"\afilters\x18\x06 \x03(\v2\x16.v1.rag.MetadataFilterR\afilters\x12*\n" +
+	"\ahistory\x18\a \x03(\v2\x10.v1.rag.ChatTurnR\ahistory\x124\n" +
+	"\texpansion\x18\b \x01(\x0e2\x16.v1.rag.QueryExpansionR\texpansion\x12,\n" +
+	"\x12num_query_variants\x18\t \x01(\x05R\x10numQueryVariants\x12'\n" +
+	"\x0fgraph_retrieval\x18\n" +
+	" \x01(\bR\x0egraphRetrieval\"8\n" +
+	"\bChatTurn\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\xa0\x01\n" +
+	"\x0eMetadataFilter\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x18\n" +
+	"\x06equals\x18\x02 \x01(\tH\x00R\x06equals\x12$\n" +
+	"\x02in\x18\x03 \x01(\v2\x12.v1.rag.StringListH\x00R\x02in\x12+\n" +
+	"\x05range\x18\x04 \x01(\v2\x13.v1.rag.RangeFilterH\x00R\x05rangeB\v\n" +
+	"\tpredicate\"$\n" +
+	"\n" +
+	"StringList\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"\x83\x01\n" +
+	"\vRangeFilter\x12\x13\n" +
+	"\x02gt\x18\x01 \x01(\tH\x00R\x02gt\x88\x01\x01\x12\x15\n" +
+	"\x03gte\x18\x02 \x01(\tH\x01R\x03gte\x88\x01\x01\x12\x13\n" +
+	"\x02lt\x18\x03 \x01(\tH\x02R\x02lt\x88\x01\x01\x12\x15\n" +
+	"\x03lte\x18\x04 \x01(\tH\x03R\x03lte\x88\x01\x01B\x05\n" +
+	"\x03_gtB\x06\n" +
+	"\x04_gteB\x05\n" +
+	"\x03_ltB\x06\n" +
+	"\x04_lte\"\x84\x02\n" +
 	"\x11RetrievedDocument\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
 	"\x05score\x18\x03 \x01(\x02R\x05score\x12C\n" +
-	"\bmetadata\x18\x04 \x03(\v2'.v1.rag.RetrievedDocument.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\x04 \x03(\v2'.v1.rag.RetrievedDocument.MetadataEntryR\bmetadata\x123\n" +
+	"\vsource_type\x18\x05 \x01(\x0e2\x12.v1.rag.SourceTypeR\n" +
+	"sourceType\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\\\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb7\x01\n" +
 	"\rQueryResponse\x12\x16\n" +
 	"\x06answer\x18\x01 \x01(\tR\x06answer\x123\n" +
-	"\asources\x18\x02 \x03(\v2\x19.v1.rag.RetrievedDocumentR\asources\"K\n" +
+	"\asources\x18\x02 \x03(\v2\x19.v1.rag.RetrievedDocumentR\asources\x12.\n" +
+	"\tcitations\x18\x03 \x03(\v2\x10.v1.rag.CitationR\tcitations\x12)\n" +
+	"\x10standalone_query\x18\x04 \x01(\tR\x0fstandaloneQuery\"\xa0\x01\n" +
+	"\x13QueryStreamResponse\x120\n" +
+	"\asources\x18\x01 \x01(\v2\x14.v1.rag.QuerySourcesH\x00R\asources\x12\x16\n" +
+	"\x05token\x18\x02 \x01(\tH\x00R\x05token\x126\n" +
+	"\tcompleted\x18\x03 \x01(\v2\x16.v1.rag.QueryCompletedH\x00R\tcompletedB\a\n" +
+	"\x05event\"C\n" +
+	"\fQuerySources\x123\n" +
+	"\asources\x18\x01 \x03(\v2\x19.v1.rag.RetrievedDocumentR\asources\"\xb3\x01\n" +
+	"\x0eQueryCompleted\x12\x16\n" +
+	"\x06answer\x18\x01 \x01(\tR\x06answer\x12.\n" +
+	"\tcitations\x18\x02 \x03(\v2\x10.v1.rag.CitationR\tcitations\x12.\n" +
+	"\atimings\x18\x03 \x01(\v2\x14.v1.rag.QueryTimingsR\atimings\x12)\n" +
+	"\x10standalone_query\x18\x04 \x01(\tR\x0fstandaloneQuery\"\x97\x01\n" +
+	"\fQueryTimings\x12!\n" +
+	"\fretrieval_ms\x18\x01 \x01(\x03R\vretrievalMs\x12$\n" +
+	"\x0efirst_token_ms\x18\x02 \x01(\x03R\ffirstTokenMs\x12#\n" +
+	"\rgeneration_ms\x18\x03 \x01(\x03R\fgenerationMs\x12\x19\n" +
+	"\btotal_ms\x18\x04 \x01(\x03R\atotalMs\"\xa2\x01\n" +
+	"\bCitation\x12\x16\n" +
+	"\x06marker\x18\x01 \x01(\x05R\x06marker\x12\x1f\n" +
+	"\vdocument_id\x18\x02 \x01(\tR\n" +
+	"documentId\x12\x1b\n" +
+	"\tfile_name\x18\x03 \x01(\tR\bfileName\x12\x1d\n" +
+	"\n" +
+	"page_label\x18\x04 \x01(\tR\tpageLabel\x12!\n" +
+	"\fchunk_number\x18\x05 \x01(\x05R\vchunkNumber\"s\n" +
 	"\x06Folder\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x03 \x01(\tR\tcreatedAt\"O\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12&\n" +
+	"\x04sync\x18\x04 \x01(\v2\x12.v1.rag.FolderSyncR\x04sync\"O\n" +
 	"\x13CreateFolderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
 	"\vfolder_name\x18\x02 \x01(\tR\n" +
@@ -619,13 +2765,94 @@ const file_api_proto_v1_rag_rag_proto_rawDesc = "" +
 	"\x12ListFoldersRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
 	"\x13ListFoldersResponse\x12(\n" +
-	"\afolders\x18\x01 \x03(\v2\x0e.v1.rag.FolderR\afolders2\x87\x02\n" +
+	"\afolders\x18\x01 \x03(\v2\x0e.v1.rag.FolderR\afolders\"K\n" +
+	"\x13DeleteFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\"C\n" +
+	"\x14DeleteFolderResponse\x12+\n" +
+	"\x11deleted_documents\x18\x01 \x01(\x05R\x10deletedDocuments\"\xe0\x01\n" +
+	"\bDocument\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12!\n" +
+	"\fcontent_hash\x18\x04 \x01(\tR\vcontentHash\x12\x1f\n" +
+	"\vchunk_count\x18\x05 \x01(\x05R\n" +
+	"chunkCount\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"indexed_at\x18\b \x01(\tR\tindexedAt\"L\n" +
+	"\x14ListDocumentsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\"G\n" +
+	"\x15ListDocumentsResponse\x12.\n" +
+	"\tdocuments\x18\x01 \x03(\v2\x10.v1.rag.DocumentR\tdocuments\"Q\n" +
+	"\x15DeleteDocumentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vdocument_id\x18\x02 \x01(\tR\n" +
+	"documentId\"?\n" +
+	"\x16DeleteDocumentResponse\x12%\n" +
+	"\x0edeleted_chunks\x18\x01 \x01(\x05R\rdeletedChunks\"\\\n" +
+	"\x0eReindexRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\"\xe5\x02\n" +
+	"\n" +
+	"FolderSync\x12\x1e\n" +
+	"\tdirectory\x18\x01 \x01(\tH\x00R\tdirectory\x12+\n" +
+	"\x05minio\x18\x02 \x01(\v2\x13.v1.rag.MinIOSourceH\x00R\x05minio\x12)\n" +
+	"\x10interval_seconds\x18\x03 \x01(\x05R\x0fintervalSeconds\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1e\n" +
+	"\vlast_run_at\x18\x05 \x01(\tR\tlastRunAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12\x14\n" +
+	"\x05added\x18\a \x01(\x05R\x05added\x12\x18\n" +
+	"\aupdated\x18\b \x01(\x05R\aupdated\x12\x18\n" +
+	"\adeleted\x18\t \x01(\x05R\adeleted\x12\x1c\n" +
+	"\tunchanged\x18\n" +
+	" \x01(\x05R\tunchanged\x12\x16\n" +
+	"\x06failed\x18\v \x01(\x05R\x06failedB\b\n" +
+	"\x06source\"=\n" +
+	"\vMinIOSource\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\tR\x06prefix\"t\n" +
+	"\x14SetFolderSyncRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12&\n" +
+	"\x04sync\x18\x03 \x01(\v2\x12.v1.rag.FolderSyncR\x04sync\"O\n" +
+	"\x17DeleteFolderSyncRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\"\x1a\n" +
+	"\x18DeleteFolderSyncResponse\"I\n" +
+	"\x11SyncFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId*e\n" +
+	"\x0eQueryExpansion\x12\x18\n" +
+	"\x14QUERY_EXPANSION_NONE\x10\x00\x12\x1f\n" +
+	"\x1bQUERY_EXPANSION_MULTI_QUERY\x10\x01\x12\x18\n" +
+	"\x14QUERY_EXPANSION_HYDE\x10\x02*G\n" +
+	"\n" +
+	"SourceType\x12\x18\n" +
+	"\x14SOURCE_TYPE_DOCUMENT\x10\x00\x12\x1f\n" +
+	"\x1bSOURCE_TYPE_KNOWLEDGE_GRAPH\x10\x012\x80\b\n" +
 	"\n" +
 	"RagService\x126\n" +
-	"\x05Index\x12\x14.v1.rag.IndexRequest\x1a\x15.v1.rag.IndexResponse0\x01\x124\n" +
-	"\x05Query\x12\x14.v1.rag.QueryRequest\x1a\x15.v1.rag.QueryResponse\x12C\n" +
+	"\x05Index\x12\x14.v1.rag.IndexRequest\x1a\x15.v1.rag.IndexResponse0\x01\x128\n" +
+	"\x0eSubmitIndexJob\x12\x14.v1.rag.IndexRequest\x1a\x10.v1.rag.IndexJob\x12;\n" +
+	"\vGetIndexJob\x12\x1a.v1.rag.GetIndexJobRequest\x1a\x10.v1.rag.IndexJob\x12?\n" +
+	"\rWatchIndexJob\x12\x1a.v1.rag.GetIndexJobRequest\x1a\x10.v1.rag.IndexJob0\x01\x124\n" +
+	"\x05Query\x12\x14.v1.rag.QueryRequest\x1a\x15.v1.rag.QueryResponse\x12B\n" +
+	"\vQueryStream\x12\x14.v1.rag.QueryRequest\x1a\x1b.v1.rag.QueryStreamResponse0\x01\x12C\n" +
 	"\fCreateFolder\x12\x1b.v1.rag.CreateFolderRequest\x1a\x16.v1.rag.FolderResponse\x12F\n" +
-	"\vListFolders\x12\x1a.v1.rag.ListFoldersRequest\x1a\x1b.v1.rag.ListFoldersResponseB\x1dZ\x1bJarvis_2.0/api/proto/v1/ragb\x06proto3"
+	"\vListFolders\x12\x1a.v1.rag.ListFoldersRequest\x1a\x1b.v1.rag.ListFoldersResponse\x12I\n" +
+	"\fDeleteFolder\x12\x1b.v1.rag.DeleteFolderRequest\x1a\x1c.v1.rag.DeleteFolderResponse\x12L\n" +
+	"\rListDocuments\x12\x1c.v1.rag.ListDocumentsRequest\x1a\x1d.v1.rag.ListDocumentsResponse\x12O\n" +
+	"\x0eDeleteDocument\x12\x1d.v1.rag.DeleteDocumentRequest\x1a\x1e.v1.rag.DeleteDocumentResponse\x12:\n" +
+	"\aReindex\x12\x16.v1.rag.ReindexRequest\x1a\x15.v1.rag.IndexResponse0\x01\x12A\n" +
+	"\rSetFolderSync\x12\x1c.v1.rag.SetFolderSyncRequest\x1a\x12.v1.rag.FolderSync\x12U\n" +
+	"\x10DeleteFolderSync\x12\x1f.v1.rag.DeleteFolderSyncRequest\x1a .v1.rag.DeleteFolderSyncResponse\x12;\n" +
+	"\n" +
+	"SyncFolder\x12\x19.v1.rag.SyncFolderRequest\x1a\x12.v1.rag.FolderSyncB\x1dZ\x1bJarvis_2.0/api/proto/v1/ragb\x06proto3"
 
 var (
 	file_api_proto_v1_rag_rag_proto_rawDescOnce sync.Once
@@ -639,38 +2866,108 @@ func file_api_proto_v1_rag_rag_proto_rawDescGZIP() []byte {
 	return file_api_proto_v1_rag_rag_proto_rawDescData
 }
 
-var file_api_proto_v1_rag_rag_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_v1_rag_rag_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_proto_v1_rag_rag_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_api_proto_v1_rag_rag_proto_goTypes = []any{
-	(*IndexRequest)(nil),        // 0: v1.rag.IndexRequest
-	(*IndexResponse)(nil),       // 1: v1.rag.IndexResponse
-	(*QueryRequest)(nil),        // 2: v1.rag.QueryRequest
-	(*RetrievedDocument)(nil),   // 3: v1.rag.RetrievedDocument
-	(*QueryResponse)(nil),       // 4: v1.rag.QueryResponse
-	(*Folder)(nil),              // 5: v1.rag.Folder
-	(*CreateFolderRequest)(nil), // 6: v1.rag.CreateFolderRequest
-	(*FolderResponse)(nil),      // 7: v1.rag.FolderResponse
-	(*ListFoldersRequest)(nil),  // 8: v1.rag.ListFoldersRequest
-	(*ListFoldersResponse)(nil), // 9: v1.rag.ListFoldersResponse
-	nil,                         // 10: v1.rag.RetrievedDocument.MetadataEntry
+	(QueryExpansion)(0),              // 0: v1.rag.QueryExpansion
+	(SourceType)(0),                  // 1: v1.rag.SourceType
+	(*IndexRequest)(nil),             // 2: v1.rag.IndexRequest
+	(*CrawlOptions)(nil),             // 3: v1.rag.CrawlOptions
+	(*IndexResponse)(nil),            // 4: v1.rag.IndexResponse
+	(*GetIndexJobRequest)(nil),       // 5: v1.rag.GetIndexJobRequest
+	(*IndexJob)(nil),                 // 6: v1.rag.IndexJob
+	(*IndexJobFile)(nil),             // 7: v1.rag.IndexJobFile
+	(*QueryRequest)(nil),             // 8: v1.rag.QueryRequest
+	(*ChatTurn)(nil),                 // 9: v1.rag.ChatTurn
+	(*MetadataFilter)(nil),           // 10: v1.rag.MetadataFilter
+	(*StringList)(nil),               // 11: v1.rag.StringList
+	(*RangeFilter)(nil),              // 12: v1.rag.RangeFilter
+	(*RetrievedDocument)(nil),        // 13: v1.rag.RetrievedDocument
+	(*QueryResponse)(nil),            // 14: v1.rag.QueryResponse
+	(*QueryStreamResponse)(nil),      // 15: v1.rag.QueryStreamResponse
+	(*QuerySources)(nil),             // 16: v1.rag.QuerySources
+	(*QueryCompleted)(nil),           // 17: v1.rag.QueryCompleted
+	(*QueryTimings)(nil),             // 18: v1.rag.QueryTimings
+	(*Citation)(nil),                 // 19: v1.rag.Citation
+	(*Folder)(nil),                   // 20: v1.rag.Folder
+	(*CreateFolderRequest)(nil),      // 21: v1.rag.CreateFolderRequest
+	(*FolderResponse)(nil),           // 22: v1.rag.FolderResponse
+	(*ListFoldersRequest)(nil),       // 23: v1.rag.ListFoldersRequest
+	(*ListFoldersResponse)(nil),      // 24: v1.rag.ListFoldersResponse
+	(*DeleteFolderRequest)(nil),      // 25: v1.rag.DeleteFolderRequest
+	(*DeleteFolderResponse)(nil),     // 26: v1.rag.DeleteFolderResponse
+	(*Document)(nil),                 // 27: v1.rag.Document
+	(*ListDocumentsRequest)(nil),     // 28: v1.rag.ListDocumentsRequest
+	(*ListDocumentsResponse)(nil),    // 29: v1.rag.ListDocumentsResponse
+	(*DeleteDocumentRequest)(nil),    // 30: v1.rag.DeleteDocumentRequest
+	(*DeleteDocumentResponse)(nil),   // 31: v1.rag.DeleteDocumentResponse
+	(*ReindexRequest)(nil),           // 32: v1.rag.ReindexRequest
+	(*FolderSync)(nil),               // 33: v1.rag.FolderSync
+	(*MinIOSource)(nil),              // 34: v1.rag.MinIOSource
+	(*SetFolderSyncRequest)(nil),     // 35: v1.rag.SetFolderSyncRequest
+	(*DeleteFolderSyncRequest)(nil),  // 36: v1.rag.DeleteFolderSyncRequest
+	(*DeleteFolderSyncResponse)(nil), // 37: v1.rag.DeleteFolderSyncResponse
+	(*SyncFolderRequest)(nil),        // 38: v1.rag.SyncFolderRequest
+	nil,                              // 39: v1.rag.RetrievedDocument.MetadataEntry
 }
 var file_api_proto_v1_rag_rag_proto_depIdxs = []int32{
-	10, // 0: v1.rag.RetrievedDocument.metadata:type_name -> v1.rag.RetrievedDocument.MetadataEntry
-	3,  // 1: v1.rag.QueryResponse.sources:type_name -> v1.rag.RetrievedDocument
-	5,  // 2: v1.rag.FolderResponse.folder:type_name -> v1.rag.Folder
-	5,  // 3: v1.rag.ListFoldersResponse.folders:type_name -> v1.rag.Folder
-	0,  // 4: v1.rag.RagService.Index:input_type -> v1.rag.IndexRequest
-	2,  // 5: v1.rag.RagService.Query:input_type -> v1.rag.QueryRequest
-	6,  // 6: v1.rag.RagService.CreateFolder:input_type -> v1.rag.CreateFolderRequest
-	8,  // 7: v1.rag.RagService.ListFolders:input_type -> v1.rag.ListFoldersRequest
-	1,  // 8: v1.rag.RagService.Index:output_type -> v1.rag.IndexResponse
-	4,  // 9: v1.rag.RagService.Query:output_type -> v1.rag.QueryResponse
-	7,  // 10: v1.rag.RagService.CreateFolder:output_type -> v1.rag.FolderResponse
-	9,  // 11: v1.rag.RagService.ListFolders:output_type -> v1.rag.ListFoldersResponse
-	8,  // [8:12] is the sub-list for method output_type
-	4,  // [4:8] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	3,  // 0: v1.rag.IndexRequest.crawl:type_name -> v1.rag.CrawlOptions
+	7,  // 1: v1.rag.IndexJob.files:type_name -> v1.rag.IndexJobFile
+	10, // 2: v1.rag.QueryRequest.filters:type_name -> v1.rag.MetadataFilter
+	9,  // 3: v1.rag.QueryRequest.history:type_name -> v1.rag.ChatTurn
+	0,  // 4: v1.rag.QueryRequest.expansion:type_name -> v1.rag.QueryExpansion
+	11, // 5: v1.rag.MetadataFilter.in:type_name -> v1.rag.StringList
+	12, // 6: v1.rag.MetadataFilter.range:type_name -> v1.rag.RangeFilter
+	39, // 7: v1.rag.RetrievedDocument.metadata:type_name -> v1.rag.RetrievedDocument.MetadataEntry
+	1,  // 8: v1.rag.RetrievedDocument.source_type:type_name -> v1.rag.SourceType
+	13, // 9: v1.rag.QueryResponse.sources:type_name -> v1.rag.RetrievedDocument
+	19, // 10: v1.rag.QueryResponse.citations:type_name -> v1.rag.Citation
+	16, // 11: v1.rag.QueryStreamResponse.sources:type_name -> v1.rag.QuerySources
+	17, // 12: v1.rag.QueryStreamResponse.completed:type_name -> v1.rag.QueryCompleted
+	13, // 13: v1.rag.QuerySources.sources:type_name -> v1.rag.RetrievedDocument
+	19, // 14: v1.rag.QueryCompleted.citations:type_name -> v1.rag.Citation
+	18, // 15: v1.rag.QueryCompleted.timings:type_name -> v1.rag.QueryTimings
+	33, // 16: v1.rag.Folder.sync:type_name -> v1.rag.FolderSync
+	20, // 17: v1.rag.FolderResponse.folder:type_name -> v1.rag.Folder
+	20, // 18: v1.rag.ListFoldersResponse.folders:type_name -> v1.rag.Folder
+	27, // 19: v1.rag.ListDocumentsResponse.documents:type_name -> v1.rag.Document
+	34, // 20: v1.rag.FolderSync.minio:type_name -> v1.rag.MinIOSource
+	33, // 21: v1.rag.SetFolderSyncRequest.sync:type_name -> v1.rag.FolderSync
+	2,  // 22: v1.rag.RagService.Index:input_type -> v1.rag.IndexRequest
+	2,  // 23: v1.rag.RagService.SubmitIndexJob:input_type -> v1.rag.IndexRequest
+	5,  // 24: v1.rag.RagService.GetIndexJob:input_type -> v1.rag.GetIndexJobRequest
+	5,  // 25: v1.rag.RagService.WatchIndexJob:input_type -> v1.rag.GetIndexJobRequest
+	8,  // 26: v1.rag.RagService.Query:input_type -> v1.rag.QueryRequest
+	8,  // 27: v1.rag.RagService.QueryStream:input_type -> v1.rag.QueryRequest
+	21, // 28: v1.rag.RagService.CreateFolder:input_type -> v1.rag.CreateFolderRequest
+	23, // 29: v1.rag.RagService.ListFolders:input_type -> v1.rag.ListFoldersRequest
+	25, // 30: v1.rag.RagService.DeleteFolder:input_type -> v1.rag.DeleteFolderRequest
+	28, // 31: v1.rag.RagService.ListDocuments:input_type -> v1.rag.ListDocumentsRequest
+	30, // 32: v1.rag.RagService.DeleteDocument:input_type -> v1.rag.DeleteDocumentRequest
+	32, // 33: v1.rag.RagService.Reindex:input_type -> v1.rag.ReindexRequest
+	35, // 34: v1.rag.RagService.SetFolderSync:input_type -> v1.rag.SetFolderSyncRequest
+	36, // 35: v1.rag.RagService.DeleteFolderSync:input_type -> v1.rag.DeleteFolderSyncRequest
+	38, // 36: v1.rag.RagService.SyncFolder:input_type -> v1.rag.SyncFolderRequest
+	4,  // 37: v1.rag.RagService.Index:output_type -> v1.rag.IndexResponse
+	6,  // 38: v1.rag.RagService.SubmitIndexJob:output_type -> v1.rag.IndexJob
+	6,  // 39: v1.rag.RagService.GetIndexJob:output_type -> v1.rag.IndexJob
+	6,  // 40: v1.rag.RagService.WatchIndexJob:output_type -> v1.rag.IndexJob
+	14, // 41: v1.rag.RagService.Query:output_type -> v1.rag.QueryResponse
+	15, // 42: v1.rag.RagService.QueryStream:output_type -> v1.rag.QueryStreamResponse
+	22, // 43: v1.rag.RagService.CreateFolder:output_type -> v1.rag.FolderResponse
+	24, // 44: v1.rag.RagService.ListFolders:output_type -> v1.rag.ListFoldersResponse
+	26, // 45: v1.rag.RagService.DeleteFolder:output_type -> v1.rag.DeleteFolderResponse
+	29, // 46: v1.rag.RagService.ListDocuments:output_type -> v1.rag.ListDocumentsResponse
+	31, // 47: v1.rag.RagService.DeleteDocument:output_type -> v1.rag.DeleteDocumentResponse
+	4,  // 48: v1.rag.RagService.Reindex:output_type -> v1.rag.IndexResponse
+	33, // 49: v1.rag.RagService.SetFolderSync:output_type -> v1.rag.FolderSync
+	37, // 50: v1.rag.RagService.DeleteFolderSync:output_type -> v1.rag.DeleteFolderSyncResponse
+	33, // 51: v1.rag.RagService.SyncFolder:output_type -> v1.rag.FolderSync
+	37, // [37:52] is the sub-list for method output_type
+	22, // [22:37] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_v1_rag_rag_proto_init() }
@@ -678,18 +2975,34 @@ func file_api_proto_v1_rag_rag_proto_init() {
 	if File_api_proto_v1_rag_rag_proto != nil {
 		return
 	}
+	file_api_proto_v1_rag_rag_proto_msgTypes[8].OneofWrappers = []any{
+		(*MetadataFilter_Equals)(nil),
+		(*MetadataFilter_In)(nil),
+		(*MetadataFilter_Range)(nil),
+	}
+	file_api_proto_v1_rag_rag_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_proto_v1_rag_rag_proto_msgTypes[13].OneofWrappers = []any{
+		(*QueryStreamResponse_Sources)(nil),
+		(*QueryStreamResponse_Token)(nil),
+		(*QueryStreamResponse_Completed)(nil),
+	}
+	file_api_proto_v1_rag_rag_proto_msgTypes[31].OneofWrappers = []any{
+		(*FolderSync_Directory)(nil),
+		(*FolderSync_Minio)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_v1_rag_rag_proto_rawDesc), len(file_api_proto_v1_rag_rag_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_v1_rag_rag_proto_goTypes,
 		DependencyIndexes: file_api_proto_v1_rag_rag_proto_depIdxs,
+		EnumInfos:         file_api_proto_v1_rag_rag_proto_enumTypes,
 		MessageInfos:      file_api_proto_v1_rag_rag_proto_msgTypes,
 	}.Build()
 	File_api_proto_v1_rag_rag_proto = out.File
@@ -19,10 +19,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RagService_Index_FullMethodName        = "/v1.rag.RagService/Index"
-	RagService_Query_FullMethodName        = "/v1.rag.RagService/Query"
-	RagService_CreateFolder_FullMethodName = "/v1.rag.RagService/CreateFolder"
-	RagService_ListFolders_FullMethodName  = "/v1.rag.RagService/ListFolders"
+	RagService_Index_FullMethodName            = "/v1.rag.RagService/Index"
+	RagService_SubmitIndexJob_FullMethodName   = "/v1.rag.RagService/SubmitIndexJob"
+	RagService_GetIndexJob_FullMethodName      = "/v1.rag.RagService/GetIndexJob"
+	RagService_WatchIndexJob_FullMethodName    = "/v1.rag.RagService/WatchIndexJob"
+	RagService_Query_FullMethodName            = "/v1.rag.RagService/Query"
+	RagService_QueryStream_FullMethodName      = "/v1.rag.RagService/QueryStream"
+	RagService_CreateFolder_FullMethodName     = "/v1.rag.RagService/CreateFolder"
+	RagService_ListFolders_FullMethodName      = "/v1.rag.RagService/ListFolders"
+	RagService_DeleteFolder_FullMethodName     = "/v1.rag.RagService/DeleteFolder"
+	RagService_ListDocuments_FullMethodName    = "/v1.rag.RagService/ListDocuments"
+	RagService_DeleteDocument_FullMethodName   = "/v1.rag.RagService/DeleteDocument"
+	RagService_Reindex_FullMethodName          = "/v1.rag.RagService/Reindex"
+	RagService_SetFolderSync_FullMethodName    = "/v1.rag.RagService/SetFolderSync"
+	RagService_DeleteFolderSync_FullMethodName = "/v1.rag.RagService/DeleteFolderSync"
+	RagService_SyncFolder_FullMethodName       = "/v1.rag.RagService/SyncFolder"
 )
 
 // RagServiceClient is the client API for RagService service.
@@ -31,12 +42,36 @@ const (
 type RagServiceClient interface {
 	// Indexes a list of files or URLs into a specific user's folder.
 	Index(ctx context.Context, in *IndexRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexResponse], error)
+	// Queues an indexing job that is processed in the background, independently of the client connection.
+	SubmitIndexJob(ctx context.Context, in *IndexRequest, opts ...grpc.CallOption) (*IndexJob, error)
+	// Returns the status of an indexing job together with the result of each file.
+	GetIndexJob(ctx context.Context, in *GetIndexJobRequest, opts ...grpc.CallOption) (*IndexJob, error)
+	// Streams the status of an indexing job whenever it changes, until the job has finished.
+	WatchIndexJob(ctx context.Context, in *GetIndexJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexJob], error)
 	// Performs a query against documents in specific folders for a user.
 	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Performs a query and streams the result: first the retrieved sources, then the answer tokens as they are
+	// generated, and finally a completion message with the validated answer, citations and timings.
+	QueryStream(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryStreamResponse], error)
 	// Creates a new folder for a user.
 	CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error)
 	// Lists all folders for a user.
 	ListFolders(ctx context.Context, in *ListFoldersRequest, opts ...grpc.CallOption) (*ListFoldersResponse, error)
+	// Deletes a folder together with all of its documents and their chunks.
+	DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteFolderResponse, error)
+	// Lists the documents indexed into a folder.
+	ListDocuments(ctx context.Context, in *ListDocumentsRequest, opts ...grpc.CallOption) (*ListDocumentsResponse, error)
+	// Deletes a document and purges its chunks from the vector store and the doc store.
+	DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error)
+	// Re-indexes every document of a folder, skipping documents whose content is unchanged unless forced.
+	Reindex(ctx context.Context, in *ReindexRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexResponse], error)
+	// Binds a sync source to a folder, replacing any previous one. The folder is then kept in sync with the source:
+	// new files are indexed, changed files re-indexed and the documents of removed files deleted.
+	SetFolderSync(ctx context.Context, in *SetFolderSyncRequest, opts ...grpc.CallOption) (*FolderSync, error)
+	// Unbinds the sync source of a folder. Documents already indexed from it are kept.
+	DeleteFolderSync(ctx context.Context, in *DeleteFolderSyncRequest, opts ...grpc.CallOption) (*DeleteFolderSyncResponse, error)
+	// Syncs a folder with its source now instead of waiting for the next poll, returning the result of the run.
+	SyncFolder(ctx context.Context, in *SyncFolderRequest, opts ...grpc.CallOption) (*FolderSync, error)
 }
 
 type ragServiceClient struct {
@@ -66,6 +101,45 @@ func (c *ragServiceClient) Index(ctx context.Context, in *IndexRequest, opts ...
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type RagService_IndexClient = grpc.ServerStreamingClient[IndexResponse]
 
+func (c *ragServiceClient) SubmitIndexJob(ctx context.Context, in *IndexRequest, opts ...grpc.CallOption) (*IndexJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexJob)
+	err := c.cc.Invoke(ctx, RagService_SubmitIndexJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) GetIndexJob(ctx context.Context, in *GetIndexJobRequest, opts ...grpc.CallOption) (*IndexJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IndexJob)
+	err := c.cc.Invoke(ctx, RagService_GetIndexJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) WatchIndexJob(ctx context.Context, in *GetIndexJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexJob], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RagService_ServiceDesc.Streams[1], RagService_WatchIndexJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetIndexJobRequest, IndexJob]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_WatchIndexJobClient = grpc.ServerStreamingClient[IndexJob]
+
 func (c *ragServiceClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(QueryResponse)
@@ -76,6 +150,25 @@ func (c *ragServiceClient) Query(ctx context.Context, in *QueryRequest, opts ...
 	return out, nil
 }
 
+func (c *ragServiceClient) QueryStream(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[QueryStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RagService_ServiceDesc.Streams[2], RagService_QueryStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[QueryRequest, QueryStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_QueryStreamClient = grpc.ServerStreamingClient[QueryStreamResponse]
+
 func (c *ragServiceClient) CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*FolderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FolderResponse)
@@ -96,18 +189,121 @@ func (c *ragServiceClient) ListFolders(ctx context.Context, in *ListFoldersReque
 	return out, nil
 }
 
+func (c *ragServiceClient) DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFolderResponse)
+	err := c.cc.Invoke(ctx, RagService_DeleteFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) ListDocuments(ctx context.Context, in *ListDocumentsRequest, opts ...grpc.CallOption) (*ListDocumentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDocumentsResponse)
+	err := c.cc.Invoke(ctx, RagService_ListDocuments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDocumentResponse)
+	err := c.cc.Invoke(ctx, RagService_DeleteDocument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) Reindex(ctx context.Context, in *ReindexRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[IndexResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RagService_ServiceDesc.Streams[3], RagService_Reindex_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReindexRequest, IndexResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_ReindexClient = grpc.ServerStreamingClient[IndexResponse]
+
+func (c *ragServiceClient) SetFolderSync(ctx context.Context, in *SetFolderSyncRequest, opts ...grpc.CallOption) (*FolderSync, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FolderSync)
+	err := c.cc.Invoke(ctx, RagService_SetFolderSync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) DeleteFolderSync(ctx context.Context, in *DeleteFolderSyncRequest, opts ...grpc.CallOption) (*DeleteFolderSyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteFolderSyncResponse)
+	err := c.cc.Invoke(ctx, RagService_DeleteFolderSync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ragServiceClient) SyncFolder(ctx context.Context, in *SyncFolderRequest, opts ...grpc.CallOption) (*FolderSync, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FolderSync)
+	err := c.cc.Invoke(ctx, RagService_SyncFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RagServiceServer is the server API for RagService service.
 // All implementations must embed UnimplementedRagServiceServer
 // for forward compatibility.
 type RagServiceServer interface {
 	// Indexes a list of files or URLs into a specific user's folder.
 	Index(*IndexRequest, grpc.ServerStreamingServer[IndexResponse]) error
+	// Queues an indexing job that is processed in the background, independently of the client connection.
+	SubmitIndexJob(context.Context, *IndexRequest) (*IndexJob, error)
+	// Returns the status of an indexing job together with the result of each file.
+	GetIndexJob(context.Context, *GetIndexJobRequest) (*IndexJob, error)
+	// Streams the status of an indexing job whenever it changes, until the job has finished.
+	WatchIndexJob(*GetIndexJobRequest, grpc.ServerStreamingServer[IndexJob]) error
 	// Performs a query against documents in specific folders for a user.
 	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	// Performs a query and streams the result: first the retrieved sources, then the answer tokens as they are
+	// generated, and finally a completion message with the validated answer, citations and timings.
+	QueryStream(*QueryRequest, grpc.ServerStreamingServer[QueryStreamResponse]) error
 	// Creates a new folder for a user.
 	CreateFolder(context.Context, *CreateFolderRequest) (*FolderResponse, error)
 	// Lists all folders for a user.
 	ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error)
+	// Deletes a folder together with all of its documents and their chunks.
+	DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error)
+	// Lists the documents indexed into a folder.
+	ListDocuments(context.Context, *ListDocumentsRequest) (*ListDocumentsResponse, error)
+	// Deletes a document and purges its chunks from the vector store and the doc store.
+	DeleteDocument(context.Context, *DeleteDocumentRequest) (*DeleteDocumentResponse, error)
+	// Re-indexes every document of a folder, skipping documents whose content is unchanged unless forced.
+	Reindex(*ReindexRequest, grpc.ServerStreamingServer[IndexResponse]) error
+	// Binds a sync source to a folder, replacing any previous one. The folder is then kept in sync with the source:
+	// new files are indexed, changed files re-indexed and the documents of removed files deleted.
+	SetFolderSync(context.Context, *SetFolderSyncRequest) (*FolderSync, error)
+	// Unbinds the sync source of a folder. Documents already indexed from it are kept.
+	DeleteFolderSync(context.Context, *DeleteFolderSyncRequest) (*DeleteFolderSyncResponse, error)
+	// Syncs a folder with its source now instead of waiting for the next poll, returning the result of the run.
+	SyncFolder(context.Context, *SyncFolderRequest) (*FolderSync, error)
 	mustEmbedUnimplementedRagServiceServer()
 }
 
@@ -121,15 +317,48 @@ type UnimplementedRagServiceServer struct{}
 func (UnimplementedRagServiceServer) Index(*IndexRequest, grpc.ServerStreamingServer[IndexResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Index not implemented")
 }
+func (UnimplementedRagServiceServer) SubmitIndexJob(context.Context, *IndexRequest) (*IndexJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitIndexJob not implemented")
+}
+func (UnimplementedRagServiceServer) GetIndexJob(context.Context, *GetIndexJobRequest) (*IndexJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIndexJob not implemented")
+}
+func (UnimplementedRagServiceServer) WatchIndexJob(*GetIndexJobRequest, grpc.ServerStreamingServer[IndexJob]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchIndexJob not implemented")
+}
 func (UnimplementedRagServiceServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
 }
+func (UnimplementedRagServiceServer) QueryStream(*QueryRequest, grpc.ServerStreamingServer[QueryStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method QueryStream not implemented")
+}
 func (UnimplementedRagServiceServer) CreateFolder(context.Context, *CreateFolderRequest) (*FolderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
 }
 func (UnimplementedRagServiceServer) ListFolders(context.Context, *ListFoldersRequest) (*ListFoldersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListFolders not implemented")
 }
+func (UnimplementedRagServiceServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedRagServiceServer) ListDocuments(context.Context, *ListDocumentsRequest) (*ListDocumentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDocuments not implemented")
+}
+func (UnimplementedRagServiceServer) DeleteDocument(context.Context, *DeleteDocumentRequest) (*DeleteDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDocument not implemented")
+}
+func (UnimplementedRagServiceServer) Reindex(*ReindexRequest, grpc.ServerStreamingServer[IndexResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Reindex not implemented")
+}
+func (UnimplementedRagServiceServer) SetFolderSync(context.Context, *SetFolderSyncRequest) (*FolderSync, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFolderSync not implemented")
+}
+func (UnimplementedRagServiceServer) DeleteFolderSync(context.Context, *DeleteFolderSyncRequest) (*DeleteFolderSyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolderSync not implemented")
+}
+func (UnimplementedRagServiceServer) SyncFolder(context.Context, *SyncFolderRequest) (*FolderSync, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncFolder not implemented")
+}
 func (UnimplementedRagServiceServer) mustEmbedUnimplementedRagServiceServer() {}
 func (UnimplementedRagServiceServer) testEmbeddedByValue()                    {}
 
@@ -162,6 +391,53 @@ func _RagService_Index_Handler(srv interface{}, stream grpc.ServerStream) error
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type RagService_IndexServer = grpc.ServerStreamingServer[IndexResponse]
 
+func _RagService_SubmitIndexJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).SubmitIndexJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_SubmitIndexJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).SubmitIndexJob(ctx, req.(*IndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_GetIndexJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIndexJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).GetIndexJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_GetIndexJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).GetIndexJob(ctx, req.(*GetIndexJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_WatchIndexJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetIndexJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RagServiceServer).WatchIndexJob(m, &grpc.GenericServerStream[GetIndexJobRequest, IndexJob]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_WatchIndexJobServer = grpc.ServerStreamingServer[IndexJob]
+
 func _RagService_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(QueryRequest)
 	if err := dec(in); err != nil {
@@ -180,6 +456,17 @@ func _RagService_Query_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RagService_QueryStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RagServiceServer).QueryStream(m, &grpc.GenericServerStream[QueryRequest, QueryStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_QueryStreamServer = grpc.ServerStreamingServer[QueryStreamResponse]
+
 func _RagService_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateFolderRequest)
 	if err := dec(in); err != nil {
@@ -216,6 +503,125 @@ func _RagService_ListFolders_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RagService_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_ListDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDocumentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).ListDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_ListDocuments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).ListDocuments(ctx, req.(*ListDocumentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_DeleteDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).DeleteDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_DeleteDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).DeleteDocument(ctx, req.(*DeleteDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_Reindex_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReindexRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RagServiceServer).Reindex(m, &grpc.GenericServerStream[ReindexRequest, IndexResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RagService_ReindexServer = grpc.ServerStreamingServer[IndexResponse]
+
+func _RagService_SetFolderSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFolderSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).SetFolderSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_SetFolderSync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).SetFolderSync(ctx, req.(*SetFolderSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_DeleteFolderSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).DeleteFolderSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_DeleteFolderSync_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).DeleteFolderSync(ctx, req.(*DeleteFolderSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RagService_SyncFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RagServiceServer).SyncFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RagService_SyncFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RagServiceServer).SyncFolder(ctx, req.(*SyncFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RagService_ServiceDesc is the grpc.ServiceDesc for RagService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -223,6 +629,14 @@ var RagService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.rag.RagService",
 	HandlerType: (*RagServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitIndexJob",
+			Handler:    _RagService_SubmitIndexJob_Handler,
+		},
+		{
+			MethodName: "GetIndexJob",
+			Handler:    _RagService_GetIndexJob_Handler,
+		},
 		{
 			MethodName: "Query",
 			Handler:    _RagService_Query_Handler,
@@ -235,6 +649,30 @@ var RagService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListFolders",
 			Handler:    _RagService_ListFolders_Handler,
 		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _RagService_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "ListDocuments",
+			Handler:    _RagService_ListDocuments_Handler,
+		},
+		{
+			MethodName: "DeleteDocument",
+			Handler:    _RagService_DeleteDocument_Handler,
+		},
+		{
+			MethodName: "SetFolderSync",
+			Handler:    _RagService_SetFolderSync_Handler,
+		},
+		{
+			MethodName: "DeleteFolderSync",
+			Handler:    _RagService_DeleteFolderSync_Handler,
+		},
+		{
+			MethodName: "SyncFolder",
+			Handler:    _RagService_SyncFolder_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -242,6 +680,21 @@ var RagService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _RagService_Index_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchIndexJob",
+			Handler:       _RagService_WatchIndexJob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "QueryStream",
+			Handler:       _RagService_QueryStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Reindex",
+			Handler:       _RagService_Reindex_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/proto/v1/rag/rag.proto",
 }
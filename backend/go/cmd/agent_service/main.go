@@ -94,7 +94,7 @@ func main() {
 	coordinator := service.NewCoordinator(agentService, resultPublisher, taskUpdater, contentProcessor, serviceLogger)
 
 	// --- 5. 启动Kafka消费者 ---
-	taskConsumer, err := consumer.NewTaskConsumer(cfg.Databases.Kafka.Brokers, cfg.TaskIngestion.KafkaTasksTopic, "agent-service-group", serviceLogger)
+	taskConsumer, err := consumer.NewTaskConsumer(cfg.Databases.Kafka.Brokers, cfg.TaskIngestion.KafkaTasksTopic, "agent-service-group", cfg.Databases.Kafka.Retry, serviceLogger)
 	if err != nil {
 		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Failed to create Kafka task consumer")
 	}
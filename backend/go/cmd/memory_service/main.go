@@ -13,6 +13,7 @@ import (
 	"Jarvis_2.0/backend/go/internal/memory/store"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -38,12 +39,6 @@ func main() {
 
 	// Initialize database clients
 	ctx := context.Background()
-	milvusClient, err := milvus.GetClient(ctx, &cfg.Databases.Milvus)
-	if err != nil {
-		appLogger.Fatal(err.Error())
-	}
-	defer milvusClient.Close()
-
 	neo4jClient, err := neo4j.GetClient(ctx, &cfg.Databases.Neo4j)
 	if err != nil {
 		appLogger.Fatal(err.Error())
@@ -68,7 +63,30 @@ func main() {
 	}
 
 	// Initialize stores
-	vecStore := store.NewMilvusStore(milvusClient, embedder, cfg.Databases.Milvus.Schema.CollectionName)
+	var vecStore store.Store
+	switch cfg.Memory.VectorStore.Backend {
+	case "milvus", "":
+		milvusClient, err := milvus.GetClient(ctx, &cfg.Databases.Milvus)
+		if err != nil {
+			appLogger.Fatal(err.Error())
+		}
+		defer milvusClient.Close()
+		vecStore = store.NewMilvusStore(milvusClient, embedder, cfg.Databases.Milvus.Schema.CollectionName)
+	case "local":
+		// 进程内向量存储，无需 Milvus
+		localStore, err := store.NewLocalStore(embedder, cfg.Memory.VectorStore.SnapshotPath, appLogger)
+		if err != nil {
+			appLogger.Fatal(err.Error())
+		}
+		defer func() {
+			if err := localStore.Close(); err != nil {
+				appLogger.Error(fmt.Sprintf("Failed to close fact store: %v", err))
+			}
+		}()
+		vecStore = localStore
+	default:
+		appLogger.Fatal(fmt.Sprintf("unsupported memory vector store backend %q", cfg.Memory.VectorStore.Backend))
+	}
 	graphStore := store.NewNeo4jStore(neo4jClient)
 
 	// Initialize extractors
@@ -79,7 +97,11 @@ func main() {
 	memoryService := service.NewMemoryService(factExtractor, graphExtractor, vecStore, graphStore, llmClient, appLogger)
 
 	// Initialize and start Kafka consumer
-	kafkaConsumer := consumer.NewKafkaConsumer(kafkaClient, memoryService, appLogger)
+	kafkaConsumer, err := consumer.NewKafkaConsumer(kafkaClient, cfg.Databases.Kafka.MemoryTopic, "memory-service-group", memoryService, appLogger)
+	if err != nil {
+		appLogger.Fatal(err.Error())
+	}
+	defer kafkaConsumer.Close()
 	kafkaConsumer.Start(ctx)
 
 	appLogger.Info("Memory service started")
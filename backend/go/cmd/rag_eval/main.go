@@ -0,0 +1,204 @@
+// Command rag_eval measures the retrieval and answer quality of the RAG pipeline on a golden question set.
+//
+// It retrieves for every question of a YAML dataset with the components configured for rag_service, reports
+// recall@k, MRR and nDCG against the expected sources, and has an LLM judge the faithfulness of generated answers
+// and their correctness against reference answers. The report can be saved and compared with a previous run:
+//
+//	rag_eval -dataset eval/handbook.yaml -out eval/results/latest.json -baseline eval/results/previous.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/embedding"
+	"Jarvis_2.0/backend/go/internal/llm"
+	"Jarvis_2.0/backend/go/internal/rag_service/components"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/evaluation"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/llms"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config.yaml", "path of the application config")
+	datasetPath := flag.String("dataset", "", "path of the YAML dataset to evaluate (required)")
+	outPath := flag.String("out", "", "path to write the JSON report to")
+	baselinePath := flag.String("baseline", "", "path of a previous JSON report to compare against")
+	retrievalOnly := flag.Bool("retrieval-only", false, "skip answer generation and LLM judging")
+	threshold := flag.Float64("threshold", 0.05, "smallest per-question change reported when comparing runs")
+	flag.Parse()
+	if *datasetPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Keep the pipelines' progress logs out of the report on stdout.
+	logger.Init(logrus.WarnLevel)
+	appLogger := logger.New("RAGEval", "", "")
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	dataset, err := evaluation.LoadDataset(*datasetPath)
+	if err != nil {
+		log.Fatalf("Failed to load dataset: %v", err)
+	}
+	var baseline *evaluation.Report
+	if *baselinePath != "" {
+		if baseline, err = evaluation.LoadReport(*baselinePath); err != nil {
+			log.Fatalf("Failed to load baseline: %v", err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	evaluator, closeComponents, err := newEvaluator(ctx, cfg, *retrievalOnly, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to set up the pipelines: %v", err)
+	}
+	defer closeComponents()
+
+	report, err := evaluator.Run(ctx, dataset)
+	if err != nil {
+		log.Fatalf("Evaluation aborted: %v", err)
+	}
+	if *outPath != "" {
+		if err := evaluation.WriteReport(*outPath, report); err != nil {
+			log.Fatalf("Failed to save report: %v", err)
+		}
+	}
+
+	printReport(report)
+	if baseline != nil {
+		printComparison(evaluation.Compare(baseline, report, *threshold))
+	}
+}
+
+// newEvaluator builds the retrieval and QA pipelines from the same components rag_service uses, so the evaluation
+// measures the configured setup. The returned function releases the components once the evaluation is done.
+//
+// The QA model and the judge use a stateless LLM, so every answer and every judgement starts from an empty chat
+// history and the scores do not depend on the order of the questions.
+func newEvaluator(ctx context.Context, cfg *config.AppConfig, retrievalOnly bool, log *logger.Logger) (*evaluation.Evaluator, func(), error) {
+	vectorStore, err := components.NewVectorStore(cfg, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create vector store: %w", err)
+	}
+	docStore, err := components.NewDocStore(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create doc store: %w", err)
+	}
+	keywordIndex, err := components.NewKeywordIndex(cfg, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create keyword index: %w", err)
+	}
+	closeComponents := func() {
+		for name, component := range map[string]any{"vector store": vectorStore, "keyword index": keywordIndex} {
+			if closer, ok := component.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					log.Error(fmt.Sprintf("Failed to close %s: %v", name, err))
+				}
+			}
+		}
+	}
+	geminiEmbedding, err := embedding.NewGoogleModel(cfg.Embedding.Gemini.APIKey, cfg.Embedding.Gemini.Model)
+	if err != nil {
+		closeComponents()
+		return nil, nil, fmt.Errorf("failed to create Gemini embedding client: %w", err)
+	}
+	embedder, err := components.NewEmbedder(cfg, geminiEmbedding, log)
+	if err != nil {
+		closeComponents()
+		return nil, nil, fmt.Errorf("failed to create embedder: %w", err)
+	}
+	geminiLLM, err := llm.NewGemini(ctx, cfg.LLM.Gemini.Model, cfg.LLM.Gemini.APIKey, nil)
+	if err != nil {
+		closeComponents()
+		return nil, nil, fmt.Errorf("failed to create Gemini LLM client: %w", err)
+	}
+	reranker, err := components.NewReranker(cfg.RAG.Reranker, embedder, geminiLLM)
+	if err != nil {
+		closeComponents()
+		return nil, nil, fmt.Errorf("failed to create reranker: %w", err)
+	}
+
+	llmAdapter := llms.NewGeminiAdapter(geminiLLM.Stateless())
+	retrieval := pipeline.NewRetrievalPipeline(embedder, vectorStore, docStore, keywordIndex, reranker, llmAdapter, nil, *log)
+	if retrievalOnly {
+		return evaluation.NewEvaluator(retrieval, nil, nil, *log), closeComponents, nil
+	}
+	qa := pipeline.NewQAPipeline(llmAdapter, *log)
+	return evaluation.NewEvaluator(retrieval, qa, evaluation.NewJudge(llmAdapter), *log), closeComponents, nil
+}
+
+// printReport prints the per-question results and the summary as tables.
+func printReport(report *evaluation.Report) {
+	fmt.Printf("Dataset %q, top %d, %d questions, %d failed\n\n", report.Dataset, report.TopK, report.Summary.Questions, report.Summary.Failed)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "QUESTION")
+	for _, metric := range evaluation.Metrics {
+		fmt.Fprintf(w, "\t%s", metric.Name)
+	}
+	fmt.Fprintln(w, "\tERROR")
+	for i := range report.Questions {
+		q := &report.Questions[i]
+		fmt.Fprint(w, q.ID)
+		for _, metric := range evaluation.Metrics {
+			fmt.Fprintf(w, "\t%s", formatValue(metric.Value(q)))
+		}
+		fmt.Fprintf(w, "\t%s\n", q.Error)
+	}
+	fmt.Fprint(w, "MEAN")
+	for _, metric := range evaluation.Metrics {
+		fmt.Fprintf(w, "\t%s", formatValue(report.Summary.Value(metric.Name)))
+	}
+	fmt.Fprintln(w, "\t")
+	w.Flush()
+}
+
+// printComparison prints how the summary metrics changed and which questions got better or worse.
+func printComparison(comparison *evaluation.Comparison) {
+	fmt.Println("\nCompared with the baseline:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tBASELINE\tCURRENT\tCHANGE")
+	for _, delta := range comparison.Summary {
+		change := "-"
+		if delta.Previous != nil && delta.Current != nil {
+			change = fmt.Sprintf("%+.3f", *delta.Current-*delta.Previous)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", delta.Metric, formatValue(delta.Previous), formatValue(delta.Current), change)
+	}
+	w.Flush()
+
+	for _, section := range []struct {
+		title  string
+		deltas []evaluation.QuestionDelta
+	}{{"Regressions", comparison.Regressions}, {"Improvements", comparison.Improvements}} {
+		if len(section.deltas) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", section.title)
+		for _, delta := range section.deltas {
+			fmt.Printf("  %s %s: %.3f -> %.3f\n", delta.ID, delta.Metric, delta.Previous, delta.Current)
+		}
+	}
+}
+
+func formatValue(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", *value)
+}
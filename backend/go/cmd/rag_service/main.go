@@ -3,25 +3,41 @@ package main
 import (
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/dal"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	v1 "Jarvis_2.0/api/proto/v1"
 	ragv1 "Jarvis_2.0/api/proto/v1/rag"
 	"Jarvis_2.0/backend/go/internal/config"
-	"Jarvis_2.0/backend/go/internal/database/milvus"
+	"Jarvis_2.0/backend/go/internal/database/kafka"
+	"Jarvis_2.0/backend/go/internal/database/minio"
 	"Jarvis_2.0/backend/go/internal/database/mysql"
+	"Jarvis_2.0/backend/go/internal/discovery/etcd"
 	"Jarvis_2.0/backend/go/internal/embedding"
 	"Jarvis_2.0/backend/go/internal/llm"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/rag_service/components"
+	"Jarvis_2.0/backend/go/internal/rag_service/consumer"
+	"Jarvis_2.0/backend/go/internal/rag_service/publisher"
 	"Jarvis_2.0/backend/go/internal/rag_service/service"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"github.com/gin-gonic/gin"
+	minioapi "github.com/minio/minio-go/v7"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 const (
@@ -49,11 +65,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to MySQL: %v", err)
 	}
+	if err := db.AutoMigrate(&models.RagFolder{}, &models.RagDocument{}, &models.RagIndexJob{}, &models.RagIndexJobFile{}, &models.RagFolderSync{}, &models.RagChunkFingerprint{}, &models.RagChunkRef{}); err != nil {
+		log.Fatalf("Failed to migrate RAG tables: %v", err)
+	}
 	folderDal := dal.NewFolderDAL(db)
+	documentDal := dal.NewDocumentDAL(db)
+	indexJobDal := dal.NewIndexJobDAL(db)
+	folderSyncDal := dal.NewFolderSyncDAL(db)
+	chunkDal := dal.NewChunkDAL(db)
 
-	milvusClient, err := milvus.GetClient(context.Background(), &cfg.Databases.Milvus)
+	vectorStore, err := components.NewVectorStore(cfg, appLogger)
 	if err != nil {
-		log.Fatalf("Failed to connect to Milvus: %v", err)
+		log.Fatalf("Failed to create vector store: %v", err)
+	}
+	appLogger.Info(fmt.Sprintf("Using vector store %q", cfg.RAG.VectorStore.Backend))
+	if closer, ok := vectorStore.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				appLogger.Error(fmt.Sprintf("Failed to close vector store: %v", err))
+			}
+		}()
 	}
 
 	geminiEmbedding, err := embedding.NewGoogleModel(cfg.Embedding.Gemini.APIKey, cfg.Embedding.Gemini.Model)
@@ -66,14 +97,124 @@ func main() {
 		log.Fatalf("Failed to create Gemini LLM client: %v", err)
 	}
 
-	// Get Cohere API key from environment as it's not in the config struct
-	cohereAPIKey := os.Getenv("COHERE_API_KEY")
-	if cohereAPIKey == "" {
-		appLogger.Warn("COHERE_API_KEY environment variable not set. Reranker will not function.")
+	embedder, err := components.NewEmbedder(cfg, geminiEmbedding, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create embedder: %v", err)
+	}
+
+	docStore, err := components.NewDocStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create doc store: %v", err)
+	}
+
+	// The keyword index lives in this process, so only one replica may run while it is enabled.
+	var replicaLock *etcd.Lock
+	if keywordCfg := cfg.RAG.KeywordIndex; keywordCfg.Enabled && keywordCfg.ReplicaLock != "" {
+		sd, err := etcd.NewServiceDiscovery(cfg.Databases.Etcd.Endpoints)
+		if err != nil {
+			log.Fatalf("Failed to create service discovery client: %v", err)
+		}
+		defer sd.Close()
+		replicaLock, err = sd.TryLock(context.Background(), keywordCfg.ReplicaLock, keywordCfg.ReplicaLockTTL)
+		if errors.Is(err, etcd.ErrLocked) {
+			log.Fatalf("Another replica holds %q: the in-process keyword index requires a single replica of the RAG service", keywordCfg.ReplicaLock)
+		}
+		if err != nil {
+			log.Fatalf("Failed to acquire replica lock %q: %v", keywordCfg.ReplicaLock, err)
+		}
+		defer replicaLock.Unlock(context.Background())
+	}
+
+	keywordIndex, err := components.NewKeywordIndex(cfg, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create keyword index: %v", err)
+	}
+	if closer, ok := keywordIndex.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				appLogger.Error(fmt.Sprintf("Failed to close keyword index: %v", err))
+			}
+		}()
+	}
+
+	deduplicator, err := components.NewDeduplicator(context.Background(), cfg, chunkDal, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create deduplicator: %v", err)
+	}
+
+	mediaExtractor, err := components.NewMediaExtractor(context.Background(), cfg, geminiLLM, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create media extractor: %v", err)
 	}
 
+	graphRetriever, err := components.NewGraphRetriever(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Failed to create knowledge graph retriever: %v", err)
+	}
+
+	reranker, err := components.NewReranker(cfg.RAG.Reranker, embedder, geminiLLM)
+	if err != nil {
+		log.Fatalf("Failed to create reranker: %v", err)
+	}
+	appLogger.Info(fmt.Sprintf("Using reranker %q", cfg.RAG.Reranker.Type))
+
+	// MinIO sync sources are only enabled on request; the media extractor above connects to MinIO on its own.
+	var minioClient *minioapi.Client
+	if cfg.RAG.FolderSync.MinIO {
+		minioClient, err = minio.GetClient(&cfg.Databases.MinIO)
+		if err != nil {
+			log.Fatalf("Failed to connect to MinIO: %v", err)
+		}
+	}
+	folderSyncPollInterval, err := time.ParseDuration(cfg.RAG.FolderSync.PollInterval)
+	if err != nil {
+		log.Fatalf("Invalid rag.folder_sync.poll_interval: %v", err)
+	}
+
+	// Creating the Kafka client also creates the configured topics, including the index job topics.
+	kafkaClient, err := kafka.GetClient(&cfg.Databases.Kafka)
+	if err != nil {
+		log.Fatalf("Failed to connect to Kafka: %v", err)
+	}
+	defer kafkaClient.Close()
+	jobPublisher := publisher.NewIndexJobPublisher(cfg.Databases.Kafka.Brokers, cfg.RAG.IndexJobs.Topic, appLogger)
+	defer jobPublisher.Close()
+
 	// 4. Create the RAG Service
-	ragService := service.NewServer(*appLogger, folderDal, milvusClient, geminiEmbedding, geminiLLM, cfg.Databases.Milvus.Schema.CollectionName, cohereAPIKey)
+	ragService := service.NewServer(*appLogger, folderDal, documentDal, indexJobDal, folderSyncDal, chunkDal, vectorStore, minioClient, cfg.RAG.FolderSync.Roots, docStore, keywordIndex, deduplicator, mediaExtractor, graphRetriever, embedder, geminiLLM, reranker, jobPublisher, cfg.RAG.IndexJobs.Workers)
+
+	// Start the indexing workers, which run queued index jobs until shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if replicaLock != nil {
+		// Once the lock is lost another replica may start, so this one must not keep indexing on its own.
+		go func() {
+			<-replicaLock.Lost()
+			if ctx.Err() == nil {
+				log.Fatalf("Lost replica lock %q", cfg.RAG.KeywordIndex.ReplicaLock)
+			}
+		}()
+	}
+	jobConsumer, err := consumer.NewIndexJobConsumer(cfg.Databases.Kafka.Brokers, cfg.RAG.IndexJobs.Topic, cfg.RAG.IndexJobs.ConsumerGroup, cfg.Databases.Kafka.Retry, ragService, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to create index job consumer: %v", err)
+	}
+	defer jobConsumer.Close()
+	jobConsumer.Start(ctx)
+
+	// Keep synced folders up to date with their sources until shutdown; replicas elect a leader via etcd so each
+	// folder is synced by one replica only.
+	var folderSyncElector service.LeaderElector
+	if cfg.RAG.FolderSync.ElectionName != "" {
+		sd, err := etcd.NewServiceDiscovery(cfg.Databases.Etcd.Endpoints)
+		if err != nil {
+			log.Fatalf("Failed to create service discovery client: %v", err)
+		}
+		defer sd.Close()
+		hostname, _ := os.Hostname()
+		folderSyncElector = sd.NewElection(cfg.RAG.FolderSync.ElectionName, hostname+grpcPort, cfg.RAG.FolderSync.ElectionTTL)
+	}
+	go ragService.RunFolderSyncs(ctx, folderSyncPollInterval, folderSyncElector)
 
 	// 5. Start gRPC Server in a goroutine
 	go func() {
@@ -83,12 +224,28 @@ func main() {
 		}
 		grpcServer := grpc.NewServer()
 		ragv1.RegisterRagServiceServer(grpcServer, ragService)
+		// The same server answers the coordinator's tasks as the knowledge base sub-agent.
+		v1.RegisterAgentServiceServer(grpcServer, service.NewAgentServer(ragService))
 		appLogger.Info(fmt.Sprintf("gRPC server listening at %v", lis.Addr()))
 		if err := grpcServer.Serve(lis); err != nil {
 			log.Fatalf("Failed to serve gRPC: %v", err)
 		}
 	}()
 
+	// Register the knowledge base agent in etcd, where the coordinator discovers its tools.
+	if cfg.RAG.Agent.Enabled {
+		sd, err := etcd.NewServiceDiscovery(cfg.Databases.Etcd.Endpoints)
+		if err != nil {
+			log.Fatalf("Failed to create service discovery client: %v", err)
+		}
+		stopChan, err := sd.Register(service.AgentName, cfg.RAG.Agent.Address, cfg.RAG.Agent.TTL)
+		if err != nil {
+			log.Fatalf("Failed to register agent %q: %v", service.AgentName, err)
+		}
+		defer close(stopChan) // Revokes the registration on shutdown
+		appLogger.Info(fmt.Sprintf("Agent %q registered at %s", service.AgentName, cfg.RAG.Agent.Address))
+	}
+
 	// 6. Start Gin HTTP Server in a goroutine
 	go func() {
 		gin.SetMode(gin.ReleaseMode)
@@ -98,8 +255,19 @@ func main() {
 		api := router.Group("/api/v1")
 		{
 			api.POST("/rag/query", httpHandler.query)
+			api.POST("/rag/query/stream", httpHandler.queryStream)
 			api.POST("/rag/folders", httpHandler.createFolder)
 			api.GET("/rag/folders", httpHandler.listFolders)
+			api.DELETE("/rag/folders/:id", httpHandler.deleteFolder)
+			api.GET("/rag/folders/:id/documents", httpHandler.listDocuments)
+			api.POST("/rag/folders/:id/reindex", httpHandler.reindex)
+			api.PUT("/rag/folders/:id/sync", httpHandler.setFolderSync)
+			api.DELETE("/rag/folders/:id/sync", httpHandler.deleteFolderSync)
+			api.POST("/rag/folders/:id/sync/run", httpHandler.syncFolder)
+			api.POST("/rag/folders/:id/index-jobs", httpHandler.submitIndexJob)
+			api.GET("/rag/index-jobs/:id", httpHandler.getIndexJob)
+			api.GET("/rag/index-jobs/:id/events", httpHandler.watchIndexJob)
+			api.DELETE("/rag/documents/:id", httpHandler.deleteDocument)
 		}
 
 		appLogger.Info(fmt.Sprintf("HTTP server listening at %s", httpPort))
@@ -113,6 +281,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	appLogger.Info("Shutting down servers...")
+	cancel()
 
 	// Add graceful shutdown logic for servers if needed
 	appLogger.Info("Servers gracefully stopped")
@@ -128,20 +297,119 @@ func NewHttpHandler(service *service.Server) *HttpHandler {
 }
 
 func (h *HttpHandler) query(c *gin.Context) {
-	var req ragv1.QueryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	req, ok := bindQueryRequest(c)
+	if !ok {
 		return
 	}
 
-	resp, err := h.service.Query(c.Request.Context(), &req)
+	resp, err := h.service.Query(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// queryStream serves QueryStream as server-sent events named "sources", "token" and "completed",
+// each carrying the JSON of the corresponding message. An error after the stream started is sent as an "error" event.
+func (h *HttpHandler) queryStream(c *gin.Context) {
+	req, ok := bindQueryRequest(c)
+	if !ok {
+		return
+	}
+
+	stream := &sseQueryStream{c: c}
+	if err := h.service.QueryStream(req, stream); err != nil {
+		if !stream.started {
+			c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+	}
+}
+
+// bindQueryRequest decodes a QueryRequest from the request body, writing a 400 response on failure.
+// QueryRequest contains oneof filters, which only protojson can decode.
+func bindQueryRequest(c *gin.Context) (*ragv1.QueryRequest, bool) {
+	var req ragv1.QueryRequest
+	if !bindProtoJSON(c, &req) {
+		return nil, false
+	}
+	return &req, true
+}
+
+// writeProtoJSON writes a proto message as the JSON response. It is encoded with protojson, which encodes oneof fields
+// like plain fields, using the snake_case proto field names like the responses encoded by gin.
+func writeProtoJSON(c *gin.Context, code int, msg proto.Message) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(code, "application/json", data)
+}
+
+// bindProtoJSON decodes a proto message from the request body with protojson, writing a 400 response on failure.
+func bindProtoJSON(c *gin.Context, msg proto.Message) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// sseQueryStream adapts the QueryStream server stream to server-sent events on an HTTP response.
+type sseQueryStream struct {
+	grpc.ServerStream
+	c       *gin.Context
+	started bool
+}
+
+func (s *sseQueryStream) Context() context.Context {
+	return s.c.Request.Context()
+}
+
+func (s *sseQueryStream) Send(resp *ragv1.QueryStreamResponse) error {
+	var event string
+	var msg proto.Message
+	switch e := resp.GetEvent().(type) {
+	case *ragv1.QueryStreamResponse_Sources:
+		event, msg = "sources", e.Sources
+	case *ragv1.QueryStreamResponse_Token:
+		event, msg = "token", wrapperspb.String(e.Token)
+	case *ragv1.QueryStreamResponse_Completed:
+		event, msg = "completed", e.Completed
+	default:
+		return fmt.Errorf("unknown query stream event %T", e)
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if !s.started {
+		s.started = true
+		startSSE(s.c)
+	}
+	s.c.SSEvent(event, string(data))
+	s.c.Writer.Flush()
+	return s.c.Request.Context().Err()
+}
+
+// startSSE writes the headers of a server-sent events response.
+func startSSE(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+}
+
 func (h *HttpHandler) createFolder(c *gin.Context) {
 	var req ragv1.CreateFolderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -157,6 +425,7 @@ func (h *HttpHandler) createFolder(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// listFolders lists the folders with their sync sources, whose oneof source is encoded with protojson.
 func (h *HttpHandler) listFolders(c *gin.Context) {
 	userID := c.Query("user_id")
 	if userID == "" {
@@ -170,5 +439,228 @@ func (h *HttpHandler) listFolders(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	writeProtoJSON(c, http.StatusOK, resp)
+}
+
+func (h *HttpHandler) deleteFolder(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.DeleteFolderRequest{UserId: userID, FolderId: c.Param("id")}
+
+	resp, err := h.service.DeleteFolder(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *HttpHandler) listDocuments(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.ListDocumentsRequest{UserId: userID, FolderId: c.Param("id")}
+
+	resp, err := h.service.ListDocuments(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *HttpHandler) deleteDocument(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.DeleteDocumentRequest{UserId: userID, DocumentId: c.Param("id")}
+
+	resp, err := h.service.DeleteDocument(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *HttpHandler) reindex(c *gin.Context) {
+	var req ragv1.ReindexRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.FolderId = c.Param("id")
+
+	stream := &indexCollector{ctx: c.Request.Context()}
+	if err := h.service.Reindex(&req, stream); err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"progress": stream.responses})
+}
+
+// setFolderSync binds a sync source to the folder. The body is a SetFolderSyncRequest, whose source is a oneof and
+// therefore decoded with protojson.
+func (h *HttpHandler) setFolderSync(c *gin.Context) {
+	var req ragv1.SetFolderSyncRequest
+	if !bindProtoJSON(c, &req) {
+		return
+	}
+	req.FolderId = c.Param("id")
+
+	resp, err := h.service.SetFolderSync(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	writeProtoJSON(c, http.StatusOK, resp)
+}
+
+func (h *HttpHandler) deleteFolderSync(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.DeleteFolderSyncRequest{UserId: userID, FolderId: c.Param("id")}
+
+	resp, err := h.service.DeleteFolderSync(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// syncFolder syncs the folder with its source and responds with the result once the sync has finished.
+func (h *HttpHandler) syncFolder(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.SyncFolderRequest{UserId: userID, FolderId: c.Param("id")}
+
+	resp, err := h.service.SyncFolder(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	writeProtoJSON(c, http.StatusOK, resp)
+}
+
+// submitIndexJob queues an indexing job for the folder and responds with 202 and the job, whose status can be
+// polled via getIndexJob or followed via watchIndexJob.
+func (h *HttpHandler) submitIndexJob(c *gin.Context) {
+	var req ragv1.IndexRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.FolderId = c.Param("id")
+
+	resp, err := h.service.SubmitIndexJob(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func (h *HttpHandler) getIndexJob(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.GetIndexJobRequest{UserId: userID, JobId: c.Param("id")}
+
+	resp, err := h.service.GetIndexJob(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// watchIndexJob serves WatchIndexJob as server-sent "job" events, each carrying the JSON of the job's status.
+func (h *HttpHandler) watchIndexJob(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	req := ragv1.GetIndexJobRequest{UserId: userID, JobId: c.Param("id")}
+
+	stream := &sseIndexJobStream{c: c}
+	if err := h.service.WatchIndexJob(&req, stream); err != nil {
+		if !stream.started {
+			c.JSON(httpStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+	}
+}
+
+// sseIndexJobStream adapts the WatchIndexJob server stream to server-sent events on an HTTP response.
+type sseIndexJobStream struct {
+	grpc.ServerStream
+	c       *gin.Context
+	started bool
+}
+
+func (s *sseIndexJobStream) Context() context.Context {
+	return s.c.Request.Context()
+}
+
+func (s *sseIndexJobStream) Send(job *ragv1.IndexJob) error {
+	data, err := protojson.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if !s.started {
+		s.started = true
+		startSSE(s.c)
+	}
+	s.c.SSEvent("job", string(data))
+	s.c.Writer.Flush()
+	return s.c.Request.Context().Err()
+}
+
+// indexCollector adapts a server stream of index progress to a plain HTTP response by collecting every message.
+type indexCollector struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*ragv1.IndexResponse
+}
+
+func (s *indexCollector) Context() context.Context {
+	return s.ctx
+}
+
+func (s *indexCollector) Send(resp *ragv1.IndexResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+// httpStatus maps a gRPC status error returned by the service to an HTTP status code.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
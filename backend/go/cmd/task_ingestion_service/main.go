@@ -2,7 +2,9 @@ package main
 
 import (
 	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/database/kafka"
 	"Jarvis_2.0/backend/go/internal/database/mongo"
+	"Jarvis_2.0/backend/go/internal/discovery/etcd"
 	"Jarvis_2.0/backend/go/internal/models"
 	"Jarvis_2.0/backend/go/internal/task_ingestion_service/api"
 	"Jarvis_2.0/backend/go/internal/task_ingestion_service/consumer"
@@ -48,22 +50,74 @@ func main() {
 	serviceLogger.Info("Successfully connected to MongoDB")
 
 	// Create components with logger injection
-	taskStore := store.NewMongoTaskStore(db, cfg.TaskIngestion.MongoCollection)
+	taskStore := store.NewMongoTaskStore(db, cfg.TaskIngestion.MongoCollection, cfg.TaskIngestion.Outbox.Collection)
+	if err := taskStore.EnsureIndexes(context.Background()); err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Failed to create task indexes")
+	}
 	connManager := service.NewConnectionManager()
 	taskPublisher := publisher.NewTaskPublisher(cfg.Databases.Kafka.Brokers, cfg.TaskIngestion.KafkaTasksTopic, serviceLogger)
-	taskService := service.NewTaskService(taskStore, connManager, taskPublisher, serviceLogger)
-	resultConsumer := consumer.NewResultConsumer(cfg.Databases.Kafka.Brokers, cfg.TaskIngestion.KafkaResultsTopic, "task-ingestion-group", serviceLogger)
+	initialBackoff, err := time.ParseDuration(cfg.TaskIngestion.Webhook.InitialBackoff)
+	if err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Invalid webhook initial backoff")
+	}
+	webhookStore := store.NewMongoWebhookStore(db, cfg.TaskIngestion.Webhook.EndpointCollection, cfg.TaskIngestion.Webhook.DeliveryCollection)
+	webhookDispatcher := service.NewWebhookDispatcher(webhookStore, cfg.Middleware.CircuitBreaker, cfg.TaskIngestion.Webhook.MaxAttempts, initialBackoff, serviceLogger)
+	taskService := service.NewTaskService(taskStore, connManager, webhookDispatcher, serviceLogger)
+
+	// Tasks are written to Mongo together with an outbox entry; the relay publishes them to Kafka
+	outboxPollInterval, err := time.ParseDuration(cfg.TaskIngestion.Outbox.PollInterval)
+	if err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Invalid outbox poll interval")
+	}
+	outboxLease, err := time.ParseDuration(cfg.TaskIngestion.Outbox.Lease)
+	if err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Invalid outbox lease")
+	}
+	outboxStore := store.NewMongoOutboxStore(db, cfg.TaskIngestion.Outbox.Collection)
+	outboxRelay := service.NewOutboxRelay(outboxStore, taskPublisher, outboxPollInterval, outboxLease, serviceLogger)
+	resultConsumer, err := consumer.NewResultConsumer(cfg.Databases.Kafka.Brokers, cfg.TaskIngestion.KafkaResultsTopic, "task-ingestion-group", cfg.Databases.Kafka.Retry, serviceLogger)
+	if err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Failed to create Kafka result consumer")
+	}
+	deadLetters := kafka.NewDeadLetterAdmin(cfg.Databases.Kafka.Brokers, cfg.Databases.Kafka.Retry)
+
+	// Create the scheduler; replicas elect a leader via etcd so each schedule fires only once
+	schedulerCfg := cfg.TaskIngestion.Scheduler
+	pollInterval, err := time.ParseDuration(schedulerCfg.PollInterval)
+	if err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Invalid scheduler poll interval")
+	}
+	scheduleStore := store.NewMongoScheduleStore(db, schedulerCfg.ScheduleCollection, schedulerCfg.RunCollection)
+	var sd *etcd.ServiceDiscovery
+	var elector service.LeaderElector
+	if schedulerCfg.Enabled {
+		sd, err = etcd.NewServiceDiscovery(cfg.Databases.Etcd.Endpoints)
+		if err != nil {
+			serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Fatal("Failed to create service discovery client")
+		}
+		hostname, _ := os.Hostname()
+		elector = sd.NewElection(schedulerCfg.ElectionName, hostname+cfg.TaskIngestion.ServerAddress, schedulerCfg.ElectionTTL)
+	}
+	scheduler := service.NewScheduler(scheduleStore, taskService, elector, pollInterval, serviceLogger)
 
 	// Start Kafka consumer
 	ctx, cancel := context.WithCancel(context.Background())
 	resultConsumer.Start(ctx, taskService.HandleResult)
 	serviceLogger.Info("Kafka result consumer started")
 
+	go outboxRelay.Run(ctx)
+	serviceLogger.Info("Outbox relay started")
+
+	if schedulerCfg.Enabled {
+		go scheduler.Run(ctx)
+		serviceLogger.Info("Task scheduler started")
+	}
+
 	// Setup HTTP server
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
-	apiHandler := api.NewAPI(taskService, serviceLogger)
-	api.RegisterRoutes(router, apiHandler)
+	apiHandler := api.NewAPI(taskService, scheduler, webhookDispatcher, deadLetters, serviceLogger)
+	api.RegisterRoutes(router, apiHandler, cfg.TaskIngestion.AdminUserIDs)
 
 	srv := &http.Server{
 		Addr:    cfg.TaskIngestion.ServerAddress,
@@ -98,6 +152,18 @@ func main() {
 	if err := resultConsumer.Close(); err != nil {
 		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error closing Kafka consumer")
 	}
+	// Results are no longer consumed, so no new deliveries start while waiting for the in-flight ones.
+	if err := webhookDispatcher.Shutdown(shutdownCtx); err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Warn("Aborted in-flight webhook deliveries")
+	}
+	if err := deadLetters.Close(); err != nil {
+		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error closing dead-letter admin")
+	}
+	if sd != nil {
+		if err := sd.Close(); err != nil {
+			serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error closing etcd client")
+		}
+	}
 	// Use the provided Close function for MongoDB
 	if err := mongo.Close(context.Background()); err != nil {
 		serviceLogger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error disconnecting from MongoDB")
@@ -1,64 +1,34 @@
 package consumer
 
 import (
-	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/config"
+	jkafka "Jarvis_2.0/backend/go/internal/database/kafka"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"context"
 	"github.com/segmentio/kafka-go"
 )
 
 // TaskConsumer is responsible for consuming tasks from Kafka.
+// Failed tasks are retried and finally parked in the dead-letter topic instead of being dropped.
 type TaskConsumer struct {
-	reader *kafka.Reader
-	logger *logger.Logger
+	consumer *jkafka.Consumer
 }
 
 // NewTaskConsumer creates a new TaskConsumer.
-func NewTaskConsumer(brokers []string, topic, groupID string, logger *logger.Logger) (*TaskConsumer, error) {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  brokers,
-		GroupID:  groupID,
-		Topic:    topic,
-		MinBytes: 10e3, // 10KB
-		MaxBytes: 10e6, // 10MB
-	})
-	return &TaskConsumer{reader: reader, logger: logger}, nil
+func NewTaskConsumer(brokers []string, topic, groupID string, retryCfg config.KafkaRetryConfig, logger *logger.Logger) (*TaskConsumer, error) {
+	consumer, err := jkafka.NewConsumer(brokers, topic, groupID, retryCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskConsumer{consumer: consumer}, nil
 }
 
-// Start begins consuming messages from the Kafka topic.
+// Start begins consuming messages from the Kafka topic and its retry topic.
 func (c *TaskConsumer) Start(ctx context.Context, handler func(context.Context, kafka.Message) error) {
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				c.logger.Info("Stopping Kafka task consumer...")
-				return
-			default:
-				msg, err := c.reader.FetchMessage(ctx)
-				if err != nil {
-					if ctx.Err() == nil {
-						c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error fetching message from Kafka")
-					}
-					continue
-				}
-
-				if err := handler(ctx, msg); err != nil {
-					c.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{
-						"topic":     msg.Topic,
-						"partition": msg.Partition,
-						"offset":    msg.Offset,
-					}).Error("Error handling Kafka message")
-				}
-
-				if err := c.reader.CommitMessages(ctx, msg); err != nil {
-					c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to commit Kafka message")
-				}
-			}
-		}
-	}()
+	c.consumer.Start(ctx, handler)
 }
 
-// Close closes the underlying Kafka reader.
+// Close closes the underlying Kafka readers and writer.
 func (c *TaskConsumer) Close() error {
-	return c.reader.Close()
+	return c.consumer.Close()
 }
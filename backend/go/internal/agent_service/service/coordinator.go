@@ -4,10 +4,12 @@ import (
 	"Jarvis_2.0/api/proto/v1"
 	"Jarvis_2.0/backend/go/internal/agent_service/publisher"
 	"Jarvis_2.0/backend/go/internal/agent_service/store"
+	jkafka "Jarvis_2.0/backend/go/internal/database/kafka"
 	"Jarvis_2.0/backend/go/internal/models"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -36,7 +38,7 @@ func (c *Coordinator) ProcessTask(ctx context.Context, msg kafka.Message) error
 	var task models.TaskRecord
 	if err := json.Unmarshal(msg.Value, &task); err != nil {
 		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to unmarshal task from Kafka")
-		return err
+		return fmt.Errorf("%w: %v", jkafka.ErrNonRetryable, err)
 	}
 
 	taskLogger := logger.New("AgentCoordinator", task.ID, task.UserID)
@@ -60,6 +62,9 @@ func (c *Coordinator) ProcessTask(ctx context.Context, msg kafka.Message) error
 				Parts: []*v1.Part{{Text: content}},
 			},
 		},
+		// The user and the optional knowledge base scope are passed down to every sub-agent the task calls.
+		UserId:    task.UserID,
+		FolderIds: payloadStrings(payload["folder_ids"]),
 	}
 
 	resultTask, err := c.agentService.RunReActLoop(ctx, protoTask)
@@ -76,6 +81,18 @@ func (c *Coordinator) ProcessTask(ctx context.Context, msg kafka.Message) error
 	return c.updateAndPublishResult(ctx, &task, models.TaskStatusSuccess, finalContent)
 }
 
+// payloadStrings returns the strings of a JSON array decoded from a task payload, ignoring other values.
+func payloadStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var strs []string
+	for _, item := range items {
+		if str, ok := item.(string); ok && str != "" {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
 func (c *Coordinator) updateAndPublishResult(ctx context.Context, task *models.TaskRecord, status models.TaskStatus, resultData interface{}) error {
 	var finalResultData interface{} = resultData
 	var err error
@@ -116,8 +116,20 @@ type AuthConfig struct {
 
 // KafkaConfig 定义了 Kafka 消息队列的连接配置。
 type KafkaConfig struct {
-	Brokers []string `yaml:"brokers"` // Kafka Broker 地址列表
-	Topics  []string `yaml:"topics"`  // Kafka 主题列表
+	Brokers     []string         `yaml:"brokers"`      // Kafka Broker 地址列表
+	Topics      []string         `yaml:"topics"`       // Kafka 主题列表
+	MemoryTopic string           `yaml:"memory_topic"` // 记忆服务消费的历史消息主题
+	Retry       KafkaRetryConfig `yaml:"retry"`        // 消费失败时的重试与死信配置
+}
+
+// KafkaRetryConfig 定义了 Kafka 消费失败时的重试主题与死信主题配置。
+type KafkaRetryConfig struct {
+	MaxInProcessRetries   int    `yaml:"max_in_process_retries"`   // 单轮投递内的进程内重试次数
+	InProcessBackoff      string `yaml:"in_process_backoff"`       // 进程内首次重试前的等待时间，之后每次翻倍, 例如: "500ms"
+	MaxAttempts           int    `yaml:"max_attempts"`             // 最多投递轮数（含首次），超过后进入死信主题
+	RetryDelay            string `yaml:"retry_delay"`              // 消息在重试主题中的延迟, 例如: "30s"
+	RetryTopicSuffix      string `yaml:"retry_topic_suffix"`       // 重试主题后缀, 例如: ".retry"
+	DeadLetterTopicSuffix string `yaml:"dead_letter_topic_suffix"` // 死信主题后缀, 例如: ".dlq"
 }
 
 // DatabaseConfigs 包含所有数据库的配置。
@@ -147,22 +159,172 @@ type LoggerConfig struct {
 
 // AppConfig 是整个 YAML 文件的根结构，包含了应用程序的所有配置。
 type AppConfig struct {
-	App        AppInfo         `yaml:"app"`       // 应用程序信息
-	Auth       AuthConfig      `yaml:"auth"`      // 认证配置
-	LLM        LLMConfig       `yaml:"llm"`       // LLM 配置部分
-	Embedding  EmbeddingConfig `yaml:"embedding"` // Embedding 配置部分
-	Logger     LoggerConfig    `yaml:"logger"`    // 日志记录器配置
-	Databases  DatabaseConfigs `yaml:"databases"` // 数据库配置
-	Middleware MiddlewareConfig `yaml:"middleware"` // 中间件配置
+	App           AppInfo                    `yaml:"app"`            // 应用程序信息
+	Auth          AuthConfig                 `yaml:"auth"`           // 认证配置
+	LLM           LLMConfig                  `yaml:"llm"`            // LLM 配置部分
+	Embedding     EmbeddingConfig            `yaml:"embedding"`      // Embedding 配置部分
+	Logger        LoggerConfig               `yaml:"logger"`         // 日志记录器配置
+	Databases     DatabaseConfigs            `yaml:"databases"`      // 数据库配置
+	Middleware    MiddlewareConfig           `yaml:"middleware"`     // 中间件配置
 	TaskIngestion TaskIngestionServiceConfig `yaml:"task_ingestion"` // 任务接收服务配置
+	RAG           RAGServiceConfig           `yaml:"rag"`            // RAG 服务配置
+	Memory        MemoryServiceConfig        `yaml:"memory"`         // 记忆服务配置
+}
+
+// MemoryServiceConfig 定义了记忆服务的配置。
+type MemoryServiceConfig struct {
+	VectorStore VectorStoreConfig `yaml:"vector_store"` // 事实向量存储配置
+}
+
+// VectorStoreConfig 定义了向量存储后端的配置。
+type VectorStoreConfig struct {
+	Backend      string `yaml:"backend"`       // 存储后端, "milvus" 或 "local" (进程内精确检索, 无需外部服务)
+	SnapshotPath string `yaml:"snapshot_path"` // backend 为 local 时的快照文件路径，为空时只保存在内存中，重启后丢失
+}
+
+// RAGServiceConfig 定义了 RAG 服务的配置。
+type RAGServiceConfig struct {
+	VectorStore  VectorStoreConfig  `yaml:"vector_store"`  // 文档块向量存储配置
+	DocStore     DocStoreConfig     `yaml:"doc_store"`     // 文档块存储配置
+	KeywordIndex KeywordIndexConfig `yaml:"keyword_index"` // BM25 关键词索引配置
+	Reranker     RerankerConfig     `yaml:"reranker"`      // 检索结果重排序配置
+	IndexJobs    IndexJobsConfig    `yaml:"index_jobs"`    // 异步索引任务配置
+	FolderSync   FolderSyncConfig   `yaml:"folder_sync"`   // 文件夹与本地目录或 MinIO 前缀的自动同步配置
+	Embeddings   EmbeddingsConfig   `yaml:"embeddings"`    // Embedding 调用的批量、限流、重试与缓存配置
+	Agent        RAGAgentConfig     `yaml:"agent"`         // 作为子 Agent 注册到 etcd 供主 Agent 调用的配置
+	Dedup        DedupConfig        `yaml:"dedup"`         // 索引时重复文档块检测的配置
+	Media        RAGMediaConfig     `yaml:"media"`         // 索引时图片与表格的理解配置
+	Graph        RAGGraphConfig     `yaml:"graph"`         // 基于知识图谱的检索增强配置
+}
+
+// RAGGraphConfig 定义了检索时结合记忆服务在 Neo4j 中构建的知识图谱的配置。
+// 开启后，查询可以要求从问题中抽取实体，沿图谱关系扩展并将得到的事实作为额外的上下文。
+type RAGGraphConfig struct {
+	Enabled  bool `yaml:"enabled"`   // 是否开启，开启后连接 databases.neo4j
+	MaxHops  int  `yaml:"max_hops"`  // 从问题中的实体出发最多扩展的关系跳数, 1 或 2
+	MaxFacts int  `yaml:"max_facts"` // 加入上下文的事实（关系）数上限，距离实体越近越优先
+}
+
+// RAGMediaConfig 定义了索引 PDF、Word 等文档时对其中图片与表格的处理。
+// 图片由多模态 LLM 生成描述（包括图中文字），原图保存到 databases.minio；表格转换为 Markdown。
+// 二者都作为单独的文档块索引，并记录所在页码。
+type RAGMediaConfig struct {
+	Enabled      bool   `yaml:"enabled"`        // 是否开启，关闭时图片与表格不会单独索引
+	Bucket       string `yaml:"bucket"`         // 保存原图的 MinIO 存储桶，不存在时自动创建
+	MinImageSize int    `yaml:"min_image_size"` // 宽或高小于该像素数的图片（如图标、分隔线）会被跳过
+	Concurrency  int    `yaml:"concurrency"`    // 同时生成描述的图片数
+}
+
+// DedupConfig 定义了索引时检测重复与近似重复文档块的配置。
+// 重复的块不会再次生成向量，而是作为对已存储块的引用记录下来。检测范围为同一用户的同一文件夹。
+type DedupConfig struct {
+	Enabled      bool    `yaml:"enabled"`       // 是否开启去重
+	MaxDistance  int     `yaml:"max_distance"`  // 近似重复块的 SimHash 最大汉明距离, 0 到 3, 为 0 时只去除内容完全相同的块
+	SnapshotPath string  `yaml:"snapshot_path"` // 布隆过滤器的快照文件，为空时每次启动从数据库重建
+	Capacity     uint    `yaml:"capacity"`      // 布隆过滤器的初始容量，超出后自动扩容
+	ErrorRate    float64 `yaml:"error_rate"`    // 布隆过滤器的误报率, 例如: 0.01
+}
+
+// RAGAgentConfig 定义了 RAG 服务以子 Agent 身份注册到 etcd 的配置。
+type RAGAgentConfig struct {
+	Enabled bool   `yaml:"enabled"` // 是否在 etcd 中注册，关闭后主 Agent 无法发现知识库工具
+	Address string `yaml:"address"` // 注册到 etcd 的 gRPC 地址，主 Agent 通过该地址调用, 例如: "localhost:50051"
+	TTL     int64  `yaml:"ttl"`     // 注册租约的 TTL（秒）
+}
+
+// EmbeddingsConfig 定义了 RAG 服务调用 Embedding 模型的方式。
+type EmbeddingsConfig struct {
+	BatchSize      int                  `yaml:"batch_size"`      // 单次请求的文本数, 为 0 时使用厂商允许的上限
+	Concurrency    int                  `yaml:"concurrency"`     // 同时进行的请求数
+	MaxRetries     int                  `yaml:"max_retries"`     // 遇到限流 (429) 或服务端错误 (5xx) 时的最大重试次数
+	InitialBackoff string               `yaml:"initial_backoff"` // 首次重试前的等待时间，之后每次翻倍, 例如: "1s"
+	RateLimit      TokenBucketConfig    `yaml:"rate_limit"`      // 请求的令牌桶限流, rate 为 0 时不限流
+	Cache          EmbeddingCacheConfig `yaml:"cache"`           // 向量缓存，未变化的文本不会被重复 embedding
+}
+
+// EmbeddingCacheConfig 定义了按 (模型, 文本哈希) 缓存 Embedding 向量的配置。
+type EmbeddingCacheConfig struct {
+	Backend   string `yaml:"backend"`    // 缓存后端, "redis"、"disk" 或 "none"
+	KeyPrefix string `yaml:"key_prefix"` // backend 为 redis 时使用的键前缀
+	TTL       string `yaml:"ttl"`        // backend 为 redis 时向量的过期时间, 为空表示不过期, 例如: "720h"
+	Dir       string `yaml:"dir"`        // backend 为 disk 时的缓存目录
+}
+
+// FolderSyncConfig 定义了 RAG 文件夹同步的配置。
+type FolderSyncConfig struct {
+	PollInterval string   `yaml:"poll_interval"` // 检查哪些同步源到期的间隔，各同步源另有自己的轮询间隔, 例如: "30s"
+	MinIO        bool     `yaml:"minio"`         // 是否允许以 MinIO 存储桶前缀作为同步源，开启后连接 databases.minio
+	Roots        []string `yaml:"roots"`         // 允许作为同步源的本地目录，同步目录必须位于其中（解析符号链接后）；为空时禁用本地目录同步源
+	ElectionName string   `yaml:"election_name"` // etcd 中用于 leader 选举的名称，只有 leader 副本定时同步；为空时本实例总是同步，仅适用于单副本部署
+	ElectionTTL  int      `yaml:"election_ttl"`  // leader 会话租约的 TTL（秒）
+}
+
+// IndexJobsConfig 定义了通过 Kafka 排队的异步 RAG 索引任务的配置。
+type IndexJobsConfig struct {
+	Topic         string `yaml:"topic"`          // 索引任务主题，其重试与死信主题沿用 kafka.retry 中的后缀
+	ConsumerGroup string `yaml:"consumer_group"` // 消费索引任务的消费者组
+	Workers       int    `yaml:"workers"`        // 同一任务中并发索引的文件数
+}
+
+// RerankerConfig 定义了 RAG 检索结果重排序器的配置。
+type RerankerConfig struct {
+	Type        string  `yaml:"type"`         // 重排序器类型: "none"、"cohere"、"llm"（LLM 打分）、"embedding"（向量余弦相似度）或 "mmr"（最大边际相关性）
+	TopN        int     `yaml:"top_n"`        // 重排序后保留的文档数
+	CohereModel string  `yaml:"cohere_model"` // type 为 cohere 时使用的模型，API Key 从环境变量 COHERE_API_KEY 读取
+	MMRLambda   float64 `yaml:"mmr_lambda"`   // type 为 mmr 时相关性与多样性的权衡系数, 取值 0 到 1，越大越偏向相关性
+}
+
+// DocStoreConfig 定义了 RAG 文档块 (chunk) 存储的配置。
+type DocStoreConfig struct {
+	Backend    string `yaml:"backend"`    // 存储后端, "mongo"、"redis" 或 "memory"（仅用于本地测试，重启后丢失）
+	Collection string `yaml:"collection"` // backend 为 mongo 时使用的集合
+	KeyPrefix  string `yaml:"key_prefix"` // backend 为 redis 时使用的键前缀
+}
+
+// KeywordIndexConfig 定义了 RAG 混合检索所用的 BM25 关键词索引的配置。
+// 索引保存在进程内存中，多副本时各副本的索引互不同步，因此启用后 RAG 服务只能以单副本运行，同时负责索引与查询。
+type KeywordIndexConfig struct {
+	Enabled        bool   `yaml:"enabled"`          // 是否启用关键词检索
+	SnapshotPath   string `yaml:"snapshot_path"`    // 索引快照文件路径，为空时索引只保存在内存中，重启后丢失
+	ReplicaLock    string `yaml:"replica_lock"`     // etcd 中的锁名称，启用时持有该锁以保证只有一个副本运行，其他副本启动失败；为空时不检查
+	ReplicaLockTTL int    `yaml:"replica_lock_ttl"` // 锁会话租约的 TTL（秒），副本退出后最多这么久锁被释放
 }
 
 // TaskIngestionServiceConfig 定义了任务接收服务的配置。
 type TaskIngestionServiceConfig struct {
-	ServerAddress    string `yaml:"server_address"`
-	KafkaTasksTopic  string `yaml:"kafka_tasks_topic"`
-	KafkaResultsTopic string `yaml:"kafka_results_topic"`
-	MongoCollection  string `yaml:"mongo_collection"`
+	ServerAddress     string          `yaml:"server_address"`
+	KafkaTasksTopic   string          `yaml:"kafka_tasks_topic"`
+	KafkaResultsTopic string          `yaml:"kafka_results_topic"`
+	MongoCollection   string          `yaml:"mongo_collection"`
+	Scheduler         SchedulerConfig `yaml:"scheduler"`      // 定时任务调度器配置
+	Webhook           WebhookConfig   `yaml:"webhook"`        // 任务完成 webhook 配置
+	Outbox            OutboxConfig    `yaml:"outbox"`         // 任务发布 outbox 配置
+	AdminUserIDs      []string        `yaml:"admin_user_ids"` // 可以使用 /admin 管理接口（如死信查看与重放）的用户 ID，为空时所有用户都无权访问
+}
+
+// OutboxConfig 定义了 transactional outbox 及其 relay 的配置。
+type OutboxConfig struct {
+	Collection   string `yaml:"collection"`    // 存储 outbox 条目的 MongoDB 集合，需与任务集合位于同一数据库
+	PollInterval string `yaml:"poll_interval"` // relay 轮询待发布条目的间隔, 例如: "1s"
+	Lease        string `yaml:"lease"`         // relay 认领条目的租约时长，超时未确认的条目会被重新发布, 例如: "30s"
+}
+
+// WebhookConfig 定义了任务完成时对外 webhook 投递的配置。
+type WebhookConfig struct {
+	EndpointCollection string `yaml:"endpoint_collection"` // 存储 webhook 端点的 MongoDB 集合
+	DeliveryCollection string `yaml:"delivery_collection"` // 存储投递日志的 MongoDB 集合
+	MaxAttempts        int    `yaml:"max_attempts"`        // 单次投递的最大尝试次数
+	InitialBackoff     string `yaml:"initial_backoff"`     // 首次重试前的等待时间，之后每次翻倍, 例如: "2s"
+}
+
+// SchedulerConfig 定义了定时/周期任务调度器的配置。
+type SchedulerConfig struct {
+	Enabled            bool   `yaml:"enabled"`             // 是否在本实例上运行调度循环
+	ScheduleCollection string `yaml:"schedule_collection"` // 存储计划的 MongoDB 集合
+	RunCollection      string `yaml:"run_collection"`      // 存储触发历史的 MongoDB 集合
+	PollInterval       string `yaml:"poll_interval"`       // 检查到期计划的间隔, 例如: "15s"
+	ElectionName       string `yaml:"election_name"`       // etcd 中用于 leader 选举的名称
+	ElectionTTL        int    `yaml:"election_ttl"`        // leader 会话租约的 TTL（秒）
 }
 
 // LLMConfig 包含了不同LLM提供商的配置。
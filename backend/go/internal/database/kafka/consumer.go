@@ -0,0 +1,323 @@
+package kafka
+
+import (
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// 重试/死信消息上携带的 header。
+const (
+	HeaderAttempt           = "x-attempt"            // 已经失败的投递轮数
+	HeaderRetryAt           = "x-retry-at"           // 重试主题中的消息最早可被处理的时间 (RFC3339Nano)
+	HeaderOriginalTopic     = "x-original-topic"     // 消息最初所在的主题
+	HeaderOriginalPartition = "x-original-partition" // 消息最初所在的分区
+	HeaderOriginalOffset    = "x-original-offset"    // 消息最初的 offset
+	HeaderConsumerGroup     = "x-consumer-group"     // 处理失败的消费者组
+	HeaderError             = "x-error"              // 最后一次处理失败的错误信息
+	HeaderFailedAt          = "x-failed-at"          // 最后一次处理失败的时间 (RFC3339Nano)
+	HeaderReplayedFrom      = "x-replayed-from"      // 从死信主题重放时记录来源, 格式为 "topic/partition/offset"
+)
+
+// ErrNonRetryable 标记无法通过重试恢复的错误（例如消息格式错误），这类消息会直接进入死信主题。
+var ErrNonRetryable = errors.New("non-retryable message")
+
+// Handler 处理一条 Kafka 消息。返回错误时消息会被重试，最终进入死信主题。
+type Handler func(ctx context.Context, msg kafka.Message) error
+
+// RetryPolicy 描述了消费失败时的重试策略。
+type RetryPolicy struct {
+	MaxInProcessRetries int           // 单轮投递内的进程内重试次数
+	InProcessBackoff    time.Duration // 进程内首次重试前的等待时间，之后每次翻倍
+	MaxAttempts         int           // 最多投递轮数（含首次），超过后进入死信主题
+	RetryDelay          time.Duration // 重试主题中消息的延迟时间
+	RetryTopic          string        // 重试主题
+	DeadLetterTopic     string        // 死信主题
+}
+
+// NewRetryPolicy 根据配置为指定主题生成重试策略。
+func NewRetryPolicy(topic string, cfg config.KafkaRetryConfig) (RetryPolicy, error) {
+	backoff, err := time.ParseDuration(cfg.InProcessBackoff)
+	if err != nil {
+		return RetryPolicy{}, fmt.Errorf("无效的 in_process_backoff: %w", err)
+	}
+	delay, err := time.ParseDuration(cfg.RetryDelay)
+	if err != nil {
+		return RetryPolicy{}, fmt.Errorf("无效的 retry_delay: %w", err)
+	}
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return RetryPolicy{
+		MaxInProcessRetries: cfg.MaxInProcessRetries,
+		InProcessBackoff:    backoff,
+		MaxAttempts:         maxAttempts,
+		RetryDelay:          delay,
+		RetryTopic:          topic + cfg.RetryTopicSuffix,
+		DeadLetterTopic:     topic + cfg.DeadLetterTopicSuffix,
+	}, nil
+}
+
+// Consumer 是带重试和死信队列的 Kafka 消费者。
+// 它同时消费主主题和对应的重试主题：处理失败的消息先在进程内重试，仍失败则带延迟写入重试主题，
+// 投递轮数达到上限或遇到 ErrNonRetryable 时写入死信主题。消息只有在被成功处理或转发后才会提交 offset。
+// 重试主题由同一主题的所有消费者组共享，每个组只处理 x-consumer-group 为自己的重试消息。
+type Consumer struct {
+	topic       string
+	groupID     string
+	policy      RetryPolicy
+	reader      *kafka.Reader
+	retryReader *kafka.Reader
+	writer      *kafka.Writer
+	logger      *logger.Logger
+}
+
+// NewConsumer 创建一个新的 Consumer。
+func NewConsumer(brokers []string, topic, groupID string, retryCfg config.KafkaRetryConfig, logger *logger.Logger) (*Consumer, error) {
+	policy, err := NewRetryPolicy(topic, retryCfg)
+	if err != nil {
+		return nil, err
+	}
+	newReader := func(topic string) *kafka.Reader {
+		return kafka.NewReader(kafka.ReaderConfig{
+			Brokers:  brokers,
+			GroupID:  groupID,
+			Topic:    topic,
+			MinBytes: 10e3, // 10KB
+			MaxBytes: 10e6, // 10MB
+		})
+	}
+	return &Consumer{
+		topic:       topic,
+		groupID:     groupID,
+		policy:      policy,
+		reader:      newReader(topic),
+		retryReader: newReader(policy.RetryTopic),
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+		logger: logger,
+	}, nil
+}
+
+// Start 开始消费主主题和重试主题。
+func (c *Consumer) Start(ctx context.Context, handler Handler) {
+	go c.consume(ctx, c.reader, handler)
+	go c.consume(ctx, c.retryReader, handler)
+}
+
+func (c *Consumer) consume(ctx context.Context, reader *kafka.Reader, handler Handler) {
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.WithPayload(map[string]interface{}{"topic": reader.Config().Topic}).Info("Stopping Kafka consumer...")
+				return
+			}
+			c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Error fetching message from Kafka")
+			continue
+		}
+
+		if c.ownsMessage(msg) {
+			// 重试主题中的消息按写入顺序排列且延迟相同，因此阻塞等待到期不会延误后面的消息。
+			if !c.waitUntilDue(ctx, msg) {
+				return
+			}
+
+			if err := c.process(ctx, msg, handler); err != nil {
+				// 只在关停时发生，不提交 offset，重启后重新投递。
+				return
+			}
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to commit Kafka message")
+		}
+	}
+}
+
+// process 处理一条消息，失败时将其转发到重试主题或死信主题。只有 ctx 被取消时才返回错误。
+func (c *Consumer) process(ctx context.Context, msg kafka.Message, handler Handler) error {
+	handleErr := c.handleWithRetries(ctx, msg, handler)
+	if handleErr == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	attempt := headerInt(msg, HeaderAttempt) + 1
+	target := c.policy.RetryTopic
+	if attempt >= c.policy.MaxAttempts || errors.Is(handleErr, ErrNonRetryable) {
+		target = c.policy.DeadLetterTopic
+	}
+
+	payload := map[string]interface{}{
+		"topic":     msg.Topic,
+		"partition": msg.Partition,
+		"offset":    msg.Offset,
+		"attempt":   attempt,
+		"target":    target,
+	}
+	c.logger.WithError(models.ErrorInfo{Message: handleErr.Error()}).WithPayload(payload).Error("Error handling Kafka message")
+
+	forward := c.forwardMessage(msg, target, attempt, handleErr)
+	// 转发失败时不能提交 offset，否则消息会丢失；持续重试直到成功或关停。
+	backoff := c.policy.InProcessBackoff
+	for {
+		err := c.writer.WriteMessages(ctx, forward)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(payload).Error("Failed to forward Kafka message")
+		if !sleep(ctx, backoff) {
+			return ctx.Err()
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// handleWithRetries 调用 handler，并在失败时按指数退避进行进程内重试。
+func (c *Consumer) handleWithRetries(ctx context.Context, msg kafka.Message, handler Handler) error {
+	backoff := c.policy.InProcessBackoff
+	var err error
+	for i := 0; i <= c.policy.MaxInProcessRetries; i++ {
+		if i > 0 {
+			if !sleep(ctx, backoff) {
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+		if err = handler(ctx, msg); err == nil || errors.Is(err, ErrNonRetryable) {
+			return err
+		}
+	}
+	return err
+}
+
+// forwardMessage 构造写往重试主题或死信主题的消息，保留原始 key/value 并附带错误元数据。
+func (c *Consumer) forwardMessage(msg kafka.Message, target string, attempt int, handleErr error) kafka.Message {
+	now := time.Now()
+	originalTopic, originalPartition, originalOffset := msg.Topic, strconv.Itoa(msg.Partition), strconv.FormatInt(msg.Offset, 10)
+	if v, ok := header(msg, HeaderOriginalTopic); ok {
+		originalTopic = v
+		originalPartition, _ = header(msg, HeaderOriginalPartition)
+		originalOffset, _ = header(msg, HeaderOriginalOffset)
+	}
+
+	headers := withoutHeaders(msg.Headers, HeaderAttempt, HeaderRetryAt, HeaderOriginalTopic, HeaderOriginalPartition,
+		HeaderOriginalOffset, HeaderConsumerGroup, HeaderError, HeaderFailedAt)
+	headers = append(headers,
+		kafka.Header{Key: HeaderAttempt, Value: []byte(strconv.Itoa(attempt))},
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(originalTopic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(originalPartition)},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(originalOffset)},
+		kafka.Header{Key: HeaderConsumerGroup, Value: []byte(c.groupID)},
+		kafka.Header{Key: HeaderError, Value: []byte(handleErr.Error())},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(now.Format(time.RFC3339Nano))},
+	)
+	if target == c.policy.RetryTopic {
+		retryAt := now.Add(c.policy.RetryDelay)
+		headers = append(headers, kafka.Header{Key: HeaderRetryAt, Value: []byte(retryAt.Format(time.RFC3339Nano))})
+	}
+
+	return kafka.Message{
+		Topic:   target,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
+
+// ownsMessage 判断消息是否应由本消费者组处理。其他组写入共享重试主题的消息直接跳过。
+func (c *Consumer) ownsMessage(msg kafka.Message) bool {
+	group, ok := header(msg, HeaderConsumerGroup)
+	return !ok || group == c.groupID
+}
+
+// waitUntilDue 阻塞到消息的重试时间到达。ctx 被取消时返回 false。
+func (c *Consumer) waitUntilDue(ctx context.Context, msg kafka.Message) bool {
+	v, ok := header(msg, HeaderRetryAt)
+	if !ok {
+		return true
+	}
+	retryAt, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return true
+	}
+	return sleep(ctx, time.Until(retryAt))
+}
+
+// Close 关闭底层的 reader 和 writer。
+func (c *Consumer) Close() error {
+	var errs []error
+	if err := c.reader.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.retryReader.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.writer.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// sleep 等待 d 时长。ctx 被取消时返回 false。
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func header(msg kafka.Message, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+func headerInt(msg kafka.Message, key string) int {
+	v, _ := header(msg, key)
+	n, _ := strconv.Atoi(v)
+	return n
+}
+
+func withoutHeaders(headers []kafka.Header, keys ...string) []kafka.Header {
+	drop := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		drop[k] = struct{}{}
+	}
+	kept := make([]kafka.Header, 0, len(headers))
+	for _, h := range headers {
+		if _, ok := drop[h.Key]; !ok {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
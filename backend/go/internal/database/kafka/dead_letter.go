@@ -0,0 +1,180 @@
+package kafka
+
+import (
+	"Jarvis_2.0/backend/go/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	// ErrNotDeadLetterTopic 表示请求的主题不是死信主题。
+	ErrNotDeadLetterTopic = errors.New("not a dead-letter topic")
+	// ErrDeadLetterNotFound 表示指定 offset 上没有死信消息。
+	ErrDeadLetterNotFound = errors.New("dead-letter message not found")
+)
+
+// DeadLetter 是死信主题中的一条消息及其错误元数据。
+type DeadLetter struct {
+	Topic             string    `json:"topic"`
+	Partition         int       `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Key               string    `json:"key"`
+	Value             string    `json:"value"`
+	OriginalTopic     string    `json:"original_topic"`
+	OriginalPartition int       `json:"original_partition"`
+	OriginalOffset    int64     `json:"original_offset"`
+	ConsumerGroup     string    `json:"consumer_group"`
+	Attempts          int       `json:"attempts"`
+	Error             string    `json:"error"`
+	FailedAt          time.Time `json:"failed_at"`
+}
+
+// DeadLetterAdmin 提供查看和重放死信消息的管理功能。
+type DeadLetterAdmin struct {
+	brokers     []string
+	suffix      string
+	retrySuffix string
+	writer      *kafka.Writer
+}
+
+// NewDeadLetterAdmin 创建一个新的 DeadLetterAdmin。只有以死信主题后缀结尾的主题才能被访问。
+func NewDeadLetterAdmin(brokers []string, retryCfg config.KafkaRetryConfig) *DeadLetterAdmin {
+	return &DeadLetterAdmin{
+		brokers:     brokers,
+		suffix:      retryCfg.DeadLetterTopicSuffix,
+		retrySuffix: retryCfg.RetryTopicSuffix,
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Balancer: &kafka.Hash{},
+		},
+	}
+}
+
+// List 从指定分区的 offset 开始读取最多 limit 条死信消息。offset 小于 0 时从最早的消息开始。
+func (a *DeadLetterAdmin) List(ctx context.Context, topic string, partition int, offset int64, limit int) ([]*DeadLetter, error) {
+	msgs, err := a.read(ctx, topic, partition, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]*DeadLetter, len(msgs))
+	for i, msg := range msgs {
+		letters[i] = toDeadLetter(msg)
+	}
+	return letters, nil
+}
+
+// read 从指定分区的 offset 开始读取最多 limit 条原始死信消息。
+func (a *DeadLetterAdmin) read(ctx context.Context, topic string, partition int, offset int64, limit int) ([]kafka.Message, error) {
+	if !strings.HasSuffix(topic, a.suffix) {
+		return nil, fmt.Errorf("%w: %s", ErrNotDeadLetterTopic, topic)
+	}
+
+	conn, err := kafka.DialLeader(ctx, "tcp", a.brokers[0], topic, partition)
+	if err != nil {
+		return nil, fmt.Errorf("连接死信主题失败: %w", err)
+	}
+	first, last, err := conn.ReadOffsets()
+	conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取死信主题 offset 失败: %w", err)
+	}
+	if offset < first {
+		offset = first
+	}
+
+	var msgs []kafka.Message
+	if offset >= last || limit <= 0 {
+		return msgs, nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   a.brokers,
+		Topic:     topic,
+		Partition: partition,
+		MinBytes:  1,
+		MaxBytes:  10e6, // 10MB
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(offset); err != nil {
+		return nil, err
+	}
+
+	for len(msgs) < limit && offset < last {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("读取死信消息失败: %w", err)
+		}
+		msgs = append(msgs, msg)
+		offset = msg.Offset + 1
+	}
+	return msgs, nil
+}
+
+// Replay 将指定的死信消息重新投递给处理失败的消费者组，重试计数被清零，其余 header 保留，并返回实际写入的主题。
+// 消息被写入原始主题对应的重试主题并立即到期，因此不会被其他已成功处理它的消费者组重复处理；
+// 缺少消费者组信息的消息写回原始主题。
+func (a *DeadLetterAdmin) Replay(ctx context.Context, topic string, partition int, offset int64) (string, error) {
+	msgs, err := a.read(ctx, topic, partition, offset, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 || msgs[0].Offset != offset {
+		return "", fmt.Errorf("%w: %s/%d/%d", ErrDeadLetterNotFound, topic, partition, offset)
+	}
+	msg := msgs[0]
+	letter := toDeadLetter(msg)
+	if letter.OriginalTopic == "" {
+		return "", fmt.Errorf("死信消息缺少原始主题: %s/%d/%d", topic, partition, offset)
+	}
+
+	target := letter.OriginalTopic
+	if letter.ConsumerGroup != "" {
+		target = letter.OriginalTopic + a.retrySuffix
+	}
+	headers := withoutHeaders(msg.Headers, HeaderAttempt, HeaderRetryAt, HeaderError, HeaderFailedAt, HeaderReplayedFrom)
+	headers = append(headers, kafka.Header{Key: HeaderReplayedFrom, Value: []byte(fmt.Sprintf("%s/%d/%d", topic, partition, offset))})
+
+	err = a.writer.WriteMessages(ctx, kafka.Message{
+		Topic:   target,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+	if err != nil {
+		return "", fmt.Errorf("重放死信消息失败: %w", err)
+	}
+	return target, nil
+}
+
+// Close 关闭底层的 writer。
+func (a *DeadLetterAdmin) Close() error {
+	return a.writer.Close()
+}
+
+func toDeadLetter(msg kafka.Message) *DeadLetter {
+	letter := &DeadLetter{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     string(msg.Value),
+		Attempts:  headerInt(msg, HeaderAttempt),
+	}
+	letter.OriginalTopic, _ = header(msg, HeaderOriginalTopic)
+	letter.OriginalPartition = headerInt(msg, HeaderOriginalPartition)
+	if v, ok := header(msg, HeaderOriginalOffset); ok {
+		letter.OriginalOffset, _ = strconv.ParseInt(v, 10, 64)
+	}
+	letter.ConsumerGroup, _ = header(msg, HeaderConsumerGroup)
+	letter.Error, _ = header(msg, HeaderError)
+	if v, ok := header(msg, HeaderFailedAt); ok {
+		letter.FailedAt, _ = time.Parse(time.RFC3339Nano, v)
+	}
+	return letter
+}
@@ -0,0 +1,66 @@
+package etcd
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// Election 基于 etcd 实现 leader 选举，保证同一时刻只有一个副本持有领导权。
+type Election struct {
+	cli      *clientv3.Client
+	name     string // 选举名称，作为 etcd 中的 key 前缀
+	value    string // 当选后写入的值，通常是实例地址或ID
+	ttl      int    // 会话租约的 TTL（秒）
+	session  *concurrency.Session
+	election *concurrency.Election
+}
+
+// NewElection 使用 ServiceDiscovery 的 etcd 客户端创建一个新的选举。
+func (s *ServiceDiscovery) NewElection(name, value string, ttl int) *Election {
+	return &Election{
+		cli:   s.cli,
+		name:  "/elections/" + name,
+		value: value,
+		ttl:   ttl,
+	}
+}
+
+// Campaign 阻塞直到当选为 leader 或 ctx 被取消。
+// 返回的 channel 在领导权丢失（会话租约过期）时关闭。
+func (e *Election) Campaign(ctx context.Context) (<-chan struct{}, error) {
+	// 释放上一轮已失效的会话
+	if e.session != nil {
+		e.session.Close()
+		e.session = nil
+		e.election = nil
+	}
+
+	session, err := concurrency.NewSession(e.cli, concurrency.WithTTL(e.ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	election := concurrency.NewElection(session, e.name)
+	if err := election.Campaign(ctx, e.value); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	e.session = session
+	e.election = election
+	return session.Done(), nil
+}
+
+// Resign 主动放弃领导权并关闭会话。
+func (e *Election) Resign(ctx context.Context) error {
+	if e.election == nil {
+		return nil
+	}
+	err := e.election.Resign(ctx)
+	e.session.Close()
+	e.election = nil
+	e.session = nil
+	return err
+}
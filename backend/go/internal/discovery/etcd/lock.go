@@ -0,0 +1,43 @@
+package etcd
+
+import (
+	"context"
+
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// ErrLocked 表示锁已被其他实例持有。
+var ErrLocked = concurrency.ErrLocked
+
+// Lock 是基于 etcd 会话的互斥锁，会话租约过期时锁自动释放。
+type Lock struct {
+	session *concurrency.Session
+	mutex   *concurrency.Mutex
+}
+
+// TryLock 尝试获取名为 name 的锁，不等待：锁已被其他实例持有时返回 ErrLocked。
+// ttl 为会话租约的 TTL（秒），持有锁的进程退出后最多 ttl 秒锁即被释放。
+func (s *ServiceDiscovery) TryLock(ctx context.Context, name string, ttl int) (*Lock, error) {
+	session, err := concurrency.NewSession(s.cli, concurrency.WithTTL(ttl))
+	if err != nil {
+		return nil, err
+	}
+	mutex := concurrency.NewMutex(session, "/locks/"+name)
+	if err := mutex.TryLock(ctx); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return &Lock{session: session, mutex: mutex}, nil
+}
+
+// Lost 返回的 channel 在会话租约过期、锁可能已被其他实例获取时关闭，Unlock 后也会关闭。
+func (l *Lock) Lost() <-chan struct{} {
+	return l.session.Done()
+}
+
+// Unlock 释放锁并关闭会话。
+func (l *Lock) Unlock(ctx context.Context) error {
+	err := l.mutex.Unlock(ctx)
+	l.session.Close()
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -103,6 +104,12 @@ func (m *HuggingFaceModel) EmbedBatch(ctx context.Context, texts []string) ([][]
 	}
 	defer resp.Body.Close() // 确保在函数退出时关闭响应体。
 
+	// 将非成功状态码作为 StatusError 返回，以便调用方判断是否重试。
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
 	// 解码响应。
 	var embeddings [][]float32
 	if err := json.NewDecoder(resp.Body).Decode(&embeddings); err != nil {
@@ -0,0 +1,79 @@
+package embedding
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	openai "github.com/meguminnnnnnnnn/go-openai"
+	ollama "github.com/ollama/ollama/api"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusError 表示 Embedding 服务返回的非成功 HTTP 状态码。
+type StatusError struct {
+	StatusCode int    // HTTP 状态码。
+	Body       string // 响应体的开头部分，用于排查问题。
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("embedding request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsRetryable 判断 Embedding 请求的错误是否值得重试，即限流 (429) 或服务端错误 (5xx)。
+// 它能识别各厂商客户端返回的错误类型；无法识别的错误视为不可重试。
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := httpStatusCode(err); ok {
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+	// Google GenAI 客户端通过 gRPC 返回错误。
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.ResourceExhausted, codes.Unavailable, codes.Internal:
+			return true
+		}
+	}
+	return false
+}
+
+// httpStatusCode 从各厂商客户端的错误中提取 HTTP 状态码。
+func httpStatusCode(err error) (int, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode, true
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return googleErr.Code, true
+	}
+	var openaiAPIErr *openai.APIError
+	if errors.As(err, &openaiAPIErr) && openaiAPIErr.HTTPStatusCode > 0 {
+		return openaiAPIErr.HTTPStatusCode, true
+	}
+	var openaiReqErr *openai.RequestError
+	if errors.As(err, &openaiReqErr) && openaiReqErr.HTTPStatusCode > 0 {
+		return openaiReqErr.HTTPStatusCode, true
+	}
+	var ollamaErr ollama.StatusError
+	if errors.As(err, &ollamaErr) {
+		return ollamaErr.StatusCode, true
+	}
+	return 0, false
+}
+
+// DefaultBatchSize 返回各厂商单次批量 Embedding 请求允许的文本数。
+func DefaultBatchSize(modelType ModelType) int {
+	switch modelType {
+	case Google:
+		return 100 // BatchEmbedContents 的上限
+	case OpenAI:
+		return 2048 // embeddings 接口 input 数组的上限
+	default:
+		return 32 // Ollama 和 HuggingFace 没有硬性上限，取一个不易超时的批量
+	}
+}
@@ -0,0 +1,45 @@
+package embedding
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	openai "github.com/meguminnnnnnnnn/go-openai"
+	ollama "github.com/ollama/ollama/api"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"unknown error", errors.New("connection refused"), false},
+		{"rate limited", &StatusError{StatusCode: 429}, true},
+		{"server error", &StatusError{StatusCode: 503}, true},
+		{"bad request", &StatusError{StatusCode: 400}, false},
+		{"wrapped", fmt.Errorf("embed batch: %w", &StatusError{StatusCode: 500}), true},
+		{"google rate limited", &googleapi.Error{Code: 429}, true},
+		{"google forbidden", &googleapi.Error{Code: 403}, false},
+		{"openai server error", &openai.APIError{HTTPStatusCode: 502}, true},
+		{"openai invalid request", &openai.APIError{HTTPStatusCode: 400}, false},
+		{"openai request error", &openai.RequestError{HTTPStatusCode: 429}, true},
+		{"ollama server error", ollama.StatusError{StatusCode: 500}, true},
+		{"ollama not found", ollama.StatusError{StatusCode: 404}, false},
+		{"grpc unavailable", status.Error(codes.Unavailable, "unavailable"), true},
+		{"grpc resource exhausted", status.Error(codes.ResourceExhausted, "quota"), true},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "bad input"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
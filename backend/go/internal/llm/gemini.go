@@ -85,7 +85,7 @@ func (g *Gemini) GenerateContent(ctx context.Context, req *models.GenerateConten
 //
 // 返回值:
 //
-//	<-chan *GenerateContentResponse: 接收流式响应的通道。
+//	<-chan *GenerateContentResponse: 接收流式响应的通道。流因错误中断时，最后一个响应的 Err 携带该错误。
 //	error: 如果无法启动流式发送，则返回错误。
 func (g *Gemini) GenerateContentStream(ctx context.Context, req *models.GenerateContentRequest) (<-chan *models.GenerateContentResponse, error) {
 
@@ -103,6 +103,7 @@ func (g *Gemini) GenerateContentStream(ctx context.Context, req *models.Generate
 				return // 流结束。
 			}
 			if err != nil {
+				ch <- &models.GenerateContentResponse{Err: err} // 将错误作为最后一个响应发送，使调用方能区分中断与正常结束。
 				return
 			}
 			ch <- fromGenaiResponse(resp) // 将 GenAI 响应转换为内部响应格式并发送到通道。
@@ -121,6 +122,37 @@ func (g *Gemini) GetHistory() []*genai.Content {
 	return g.chatSession.History // 返回聊天会话的历史记录。
 }
 
+// Stateless 返回一个与该客户端共用同一模型、但不保留聊天历史的 LLM。
+// 每个请求都在新的聊天会话中发送，适用于彼此独立且可能并发的请求（例如为图片生成描述），
+// 避免历史记录随请求不断累积。
+//
+// 返回值:
+//
+//	LLM: 无状态的 Gemini 客户端。
+func (g *Gemini) Stateless() LLM {
+	return &statelessGemini{model: g.model}
+}
+
+// statelessGemini 为每个请求开启新的聊天会话。
+type statelessGemini struct {
+	model *genai.GenerativeModel
+}
+
+// session 创建一个只用于单个请求的 Gemini 客户端。
+func (s *statelessGemini) session() *Gemini {
+	return &Gemini{model: s.model, chatSession: s.model.StartChat()}
+}
+
+// GenerateContent 在新的聊天会话中发送请求并返回响应。
+func (s *statelessGemini) GenerateContent(ctx context.Context, req *models.GenerateContentRequest) (*models.GenerateContentResponse, error) {
+	return s.session().GenerateContent(ctx, req)
+}
+
+// GenerateContentStream 在新的聊天会话中发送请求并返回响应通道。
+func (s *statelessGemini) GenerateContentStream(ctx context.Context, req *models.GenerateContentRequest) (<-chan *models.GenerateContentResponse, error) {
+	return s.session().GenerateContentStream(ctx, req)
+}
+
 // toGenaiParts 将内部 Content 结构体转换为 GenAI Part 切片。
 //
 // 参数:
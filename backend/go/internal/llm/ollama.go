@@ -101,7 +101,7 @@ func (o *Ollama) GenerateContent(ctx context.Context, req *models.GenerateConten
 //
 // 返回值:
 //
-//	<-chan *GenerateContentResponse: 接收流式响应的通道。
+//	<-chan *GenerateContentResponse: 接收流式响应的通道。流因错误中断时，最后一个响应的 Err 携带该错误。
 //	error: 如果无法启动流式发送，则返回错误。
 func (o *Ollama) GenerateContentStream(ctx context.Context, req *models.GenerateContentRequest) (<-chan *models.GenerateContentResponse, error) {
 	// 将内部请求转换为 Ollama 提示格式。
@@ -123,7 +123,7 @@ func (o *Ollama) GenerateContentStream(ctx context.Context, req *models.Generate
 		})
 
 		if err != nil {
-			return
+			respChan <- &models.GenerateContentResponse{Err: err}
 		}
 	}()
 
@@ -3,7 +3,9 @@ package llm
 import (
 	"Jarvis_2.0/backend/go/internal/models"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	openai "github.com/meguminnnnnnnnn/go-openai"
 )
 
@@ -64,7 +66,11 @@ go func() {
 
 		for {
 			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
 			if err != nil {
+				respChan <- &models.GenerateContentResponse{Err: err} // 流因错误中断时，最后一个响应携带该错误。
 				return
 			}
 			respChan <- o.toGenerateContentResponseStream(&resp)
@@ -7,48 +7,53 @@ import (
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"context"
 	"encoding/json"
+	"fmt"
+
+	kafkago "github.com/segmentio/kafka-go"
 )
 
 // KafkaConsumer consumes messages from a Kafka topic and processes them with the MemoryService.
 type KafkaConsumer struct {
-	kafkaClient   *kafka.KafkaClient
+	consumer      *kafka.Consumer
 	memoryService *service.MemoryService
 	logger        *logger.Logger
 }
 
 // NewKafkaConsumer creates a new KafkaConsumer.
-func NewKafkaConsumer(kafkaClient *kafka.KafkaClient, memoryService *service.MemoryService, logger *logger.Logger) *KafkaConsumer {
+func NewKafkaConsumer(kafkaClient *kafka.KafkaClient, topic, groupID string, memoryService *service.MemoryService, logger *logger.Logger) (*KafkaConsumer, error) {
+	consumer, err := kafka.NewConsumer(kafkaClient.Config.Brokers, topic, groupID, kafkaClient.Config.Retry, logger)
+	if err != nil {
+		return nil, err
+	}
 	return &KafkaConsumer{
-		kafkaClient:   kafkaClient,
+		consumer:      consumer,
 		memoryService: memoryService,
 		logger:        logger,
-	}
+	}, nil
 }
 
 // Start starts the Kafka consumer.
 func (c *KafkaConsumer) Start(ctx context.Context) {
-	go func() {
-		for {
-			msg, err := c.kafkaClient.Reader.FetchMessage(ctx)
-			if err != nil {
-				c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to fetch message")
-				continue
-			}
-
-			var historyContent models.HistoryContent
-			if err := json.Unmarshal(msg.Value, &historyContent); err != nil {
-				c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to unmarshal message")
-				continue
-			}
-
-			if err := c.memoryService.AddMemory(ctx, &historyContent); err != nil {
-				c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to add memory")
-				continue
-			}
-
-			if err := c.kafkaClient.Reader.CommitMessages(ctx, msg); err != nil {
-				c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to commit message")
-			}
-		}
-	}()
+	c.consumer.Start(ctx, c.handle)
+}
+
+// handle adds the history content carried by a message to memory.
+// Malformed messages are not retried and go straight to the dead-letter topic.
+func (c *KafkaConsumer) handle(ctx context.Context, msg kafkago.Message) error {
+	var historyContent models.HistoryContent
+	if err := json.Unmarshal(msg.Value, &historyContent); err != nil {
+		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to unmarshal message")
+		return fmt.Errorf("%w: %v", kafka.ErrNonRetryable, err)
+	}
+
+	if err := c.memoryService.AddMemory(ctx, &historyContent); err != nil {
+		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to add memory")
+		return err
+	}
+	return nil
+}
+
+// Close closes the underlying Kafka readers and writer.
+func (c *KafkaConsumer) Close() error {
+	return c.consumer.Close()
 }
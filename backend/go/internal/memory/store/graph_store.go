@@ -5,6 +5,10 @@ import (
 	"Jarvis_2.0/backend/go/internal/models"
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+
+	neo4jdriver "github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 // GraphStore defines the interface for storing and retrieving graph data.
@@ -74,4 +78,57 @@ func (s *Neo4jStore) GetRelations(ctx context.Context, userID string) ([]*models
 	}
 
 	return relations, nil
-}
\ No newline at end of file
+}
+
+// GetRelatedRelations retrieves the relations of a user's graph on paths of at most maxHops relations starting at
+// the entities with the given names, which are matched case-insensitively. Relations closer to the entities come
+// first, and at most limit relations are returned.
+func (s *Neo4jStore) GetRelatedRelations(ctx context.Context, userID string, entities []string, maxHops, limit int) ([]*models.Relation, error) {
+	if len(entities) == 0 || maxHops < 1 || limit < 1 {
+		return nil, nil
+	}
+	names := make([]string, len(entities))
+	for i, entity := range entities {
+		names[i] = strings.ToLower(entity)
+	}
+
+	// The length of a variable-length pattern cannot be a parameter.
+	query := `
+	MATCH (entity {user_id: $user_id})
+	WHERE toLower(entity.name) IN $names
+	MATCH path = (entity)-[*1..` + strconv.Itoa(maxHops) + `]-()
+	WHERE all(n IN nodes(path) WHERE n.user_id = $user_id)
+	UNWIND relationships(path) AS r
+	WITH r, min(length(path)) AS hops
+	ORDER BY hops
+	LIMIT $limit
+	RETURN startNode(r).name AS source, type(r) AS type, endNode(r).name AS target
+	`
+	params := map[string]interface{}{
+		"user_id": userID,
+		"names":   names,
+		"limit":   limit,
+	}
+	// The records are collected before the session is closed, which discards unread results.
+	result, err := neo4jdriver.ExecuteQuery(ctx, s.client.Driver, query, params, neo4jdriver.EagerResultTransformer,
+		neo4jdriver.ExecuteQueryWithReadersRouting())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get related relations from neo4j: %w", err)
+	}
+
+	relations := make([]*models.Relation, 0, len(result.Records))
+	for _, record := range result.Records {
+		source, _ := record.Get("source")
+		target, _ := record.Get("target")
+		relType, _ := record.Get("type")
+
+		relations = append(relations, &models.Relation{
+			Source: fmt.Sprint(source),
+			Target: fmt.Sprint(target),
+			Type:   fmt.Sprint(relType),
+			UserID: userID,
+		})
+	}
+
+	return relations, nil
+}
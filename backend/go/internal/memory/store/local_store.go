@@ -0,0 +1,172 @@
+package store
+
+import (
+	"Jarvis_2.0/backend/go/internal/embedding"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"Jarvis_2.0/backend/go/pkg/util"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// localFactLimit 是 GetFacts 返回的最大事实数量，与 MilvusStore 保持一致。
+const localFactLimit = 10
+
+// snapshotDelay 是写入快照前收集修改的时间，连续添加多条事实只写一次快照。
+const snapshotDelay = 5 * time.Second
+
+// LocalStore 是 Store 接口的嵌入式实现，向量保存在进程内，无需 Milvus，适用于离线部署和测试。
+// 如果配置了快照路径，创建时会从快照加载，修改后由后台在 snapshotDelay 后重写快照，Close 时写入最终快照。
+type LocalStore struct {
+	index        *util.FlatIndex
+	embedder     embedding.Embedding
+	snapshotPath string
+	log          *logger.Logger
+	changed      chan struct{} // 通知后台写入快照；带缓冲，未处理的通知涵盖之后的修改
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewLocalStore 创建一个新的 LocalStore。snapshotPath 为空时仅保存在内存中。
+func NewLocalStore(embedder embedding.Embedding, snapshotPath string, log *logger.Logger) (*LocalStore, error) {
+	s := &LocalStore{index: util.NewFlatIndex(), embedder: embedder, snapshotPath: snapshotPath, log: log}
+	if snapshotPath == "" {
+		return s, nil
+	}
+
+	index, err := util.NewFlatIndexFromFile(snapshotPath)
+	switch {
+	case err == nil:
+		s.index = index
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to load fact snapshot: %w", err)
+	}
+
+	s.changed = make(chan struct{}, 1)
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.writeSnapshots()
+	return s, nil
+}
+
+// GetFacts 返回该用户与查询最相似的事实。
+func (s *LocalStore) GetFacts(ctx context.Context, userID string, query string) ([]*models.Fact, error) {
+	queryVector, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := s.index.Search(queryVector, localFactLimit, func(fields map[string]string) bool {
+		return fields["user_id"] == userID
+	})
+
+	facts := make([]*models.Fact, 0, len(hits))
+	for _, hit := range hits {
+		fact := &models.Fact{
+			ID:      hit.ID,
+			UserID:  hit.Fields["user_id"],
+			Content: hit.Fields["content"],
+			Source:  hit.Fields["source"],
+		}
+		startTime, _ := strconv.ParseInt(hit.Fields["start_time"], 10, 64)
+		fact.StartTime = time.Unix(startTime, 0)
+		if endTime, _ := strconv.ParseInt(hit.Fields["end_time"], 10, 64); endTime != 0 {
+			t := time.Unix(endTime, 0)
+			fact.EndTime = &t
+		}
+		facts = append(facts, fact)
+	}
+	return facts, nil
+}
+
+// AddFact 为事实内容生成嵌入向量并保存。
+func (s *LocalStore) AddFact(ctx context.Context, fact *models.Fact) error {
+	vector, err := s.embedder.Embed(ctx, fact.Content)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"user_id":    fact.UserID,
+		"content":    fact.Content,
+		"source":     fact.Source,
+		"start_time": strconv.FormatInt(fact.StartTime.Unix(), 10),
+	}
+	if fact.EndTime != nil {
+		fields["end_time"] = strconv.FormatInt(fact.EndTime.Unix(), 10)
+	}
+
+	s.index.Upsert(fact.ID, vector, fields)
+	s.markChanged()
+	return nil
+}
+
+// UpdateFact 更新事实。相同 ID 的事实会被直接覆盖。
+func (s *LocalStore) UpdateFact(ctx context.Context, fact *models.Fact) error {
+	return s.AddFact(ctx, fact)
+}
+
+// DeleteFact 删除事实。
+func (s *LocalStore) DeleteFact(ctx context.Context, factID string) error {
+	if s.index.Delete(factID) > 0 {
+		s.markChanged()
+	}
+	return nil
+}
+
+// markChanged 在配置了快照路径时安排写入快照。
+func (s *LocalStore) markChanged() {
+	if s.changed == nil {
+		return
+	}
+	select {
+	case s.changed <- struct{}{}:
+	default: // 已安排的快照会包含这次修改
+	}
+}
+
+// writeSnapshots 在每次修改 snapshotDelay 后写入快照，直到 Close。
+func (s *LocalStore) writeSnapshots() {
+	defer close(s.stopped)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.changed:
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(snapshotDelay):
+		}
+		if err := s.persist(); err != nil {
+			s.log.Error(fmt.Sprintf("Failed to write fact snapshot, retrying on the next change: %v", err))
+		}
+	}
+}
+
+// Close 写入最终快照并停止后台快照写入。
+func (s *LocalStore) Close() error {
+	if s.stop == nil {
+		return nil
+	}
+	close(s.stop)
+	<-s.stopped
+	return s.persist()
+}
+
+// persist 写入快照。
+func (s *LocalStore) persist() error {
+	if err := s.index.WriteToFile(s.snapshotPath); err != nil {
+		return fmt.Errorf("failed to write fact snapshot: %w", err)
+	}
+	return nil
+}
+
+// compile-time check to ensure LocalStore implements the Store interface
+var _ Store = (*LocalStore)(nil)
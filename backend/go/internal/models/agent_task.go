@@ -25,6 +25,10 @@ type AgentTask struct {
 	CreatedAt      time.Time    `json:"createdAt"`                // 任务创建时间。
 	TimeoutSeconds int          `json:"timeoutSeconds,omitempty"` // 任务超时（秒）。
 	RetryPolicy    *RetryPolicy `json:"retryPolicy,omitempty"`    // 失败时的重试策略。
+
+	// --- 用户上下文 ---
+	UserID    string   `json:"userID,omitempty"`    // 提交原始请求的用户 ID，沿任务链传递给子 Agent。
+	FolderIDs []string `json:"folderIDs,omitempty"` // 知识库检索范围内的文件夹 ID，为空表示该用户的全部文件夹。
 }
 
 // RetryPolicy 定义了任务失败后的重试策略。
@@ -193,6 +193,8 @@ func ConvertModelsToProtoTask(parentTask *v1.AgentTask, newContent Content, targ
 		CreatedAt:     timestamppb.New(time.Now()),
 		TimeoutSeconds: parentTask.TimeoutSeconds,
 		RetryPolicy:    parentTask.RetryPolicy,
+		UserId:         parentTask.UserId,
+		FolderIds:      parentTask.FolderIds,
 	}
 
 	return subTask, nil
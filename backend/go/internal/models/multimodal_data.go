@@ -59,6 +59,8 @@ type GenerateContentResponse struct {
 	CreateTime   time.Time `json:"createTime,omitempty"`   // 响应创建时间。
 	ResponseID   string    `json:"respinseId,omitempty"`   // 响应ID。
 	ModelVersion string    `json:"modelVersion,omitempty"` // 模型版本。
+	// 仅用于流式响应：流因错误中断时，通道中的最后一个响应只携带该错误，随后通道关闭。正常结束时为 nil。
+	Err error `json:"-"`
 }
 
 // Part 定义了消息的单个部分，可以包含文本、内联数据、文件数据等。
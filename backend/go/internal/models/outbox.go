@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxStatus 定义了 outbox 条目的发布状态
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusPublished OutboxStatus = "published"
+)
+
+// OutboxEntry 代表一条待发布到 Kafka 的消息，与业务数据在同一事务中写入 (transactional outbox)
+type OutboxEntry struct {
+	ID          string       `bson:"_id"`          // 条目唯一ID
+	AggregateID string       `bson:"aggregate_id"` // 关联的业务实体ID (例如任务ID)，同时作为 Kafka 消息 key
+	Payload     string       `bson:"payload"`      // 要发布的 JSON 消息体
+	Status      OutboxStatus `bson:"status"`       // 发布状态
+	Attempts    int          `bson:"attempts"`     // 已尝试发布的次数
+	LastError   string       `bson:"last_error"`   // 最近一次发布失败的错误信息
+	LockedUntil time.Time    `bson:"locked_until"` // relay 认领该条目的租约到期时间，避免多个副本重复发布
+	CreatedAt   time.Time    `bson:"created_at"`   // 创建时间
+	PublishedAt time.Time    `bson:"published_at"` // 发布成功的时间
+}
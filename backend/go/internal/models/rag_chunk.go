@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// RagChunkFingerprint records the fingerprints of a chunk stored for a RAG folder. Chunks indexed into the folder
+// later are compared against them to detect duplicates.
+type RagChunkFingerprint struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      string `gorm:"index:idx_chunk_fingerprint_scope;not null;size:255"`
+	FolderID    string `gorm:"index:idx_chunk_fingerprint_scope;not null;size:64"`
+	ChunkID     string `gorm:"uniqueIndex;not null;size:64"`
+	Source      string `gorm:"not null;size:2048"`     // Source of the document that owns the chunk
+	ContentHash string `gorm:"index;not null;size:64"` // SHA-256 of the normalized chunk text
+	// SimHash of the chunk text, and its four 16-bit bands used to look up near-duplicate candidates.
+	SimHash   int64
+	Band0     int  `gorm:"index"`
+	Band1     int  `gorm:"index"`
+	Band2     int  `gorm:"index"`
+	Band3     int  `gorm:"index"`
+	Near      bool // Whether the chunk is long enough to be matched as a near-duplicate, not only exactly
+	CreatedAt time.Time
+}
+
+// RagChunkRef records that a chunk of a document duplicates a chunk stored for another document of the same folder.
+// The document references the stored chunk instead of storing a copy of its own.
+type RagChunkRef struct {
+	ID         uint   `gorm:"primaryKey"`
+	DocumentID uint   `gorm:"index;not null"`
+	ChunkID    string `gorm:"index;not null;size:64"` // ID of the stored chunk
+	CreatedAt  time.Time
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// RagDocumentStatus is the indexing status of a RAG document.
+type RagDocumentStatus string
+
+const (
+	RagDocumentStatusIndexing RagDocumentStatus = "indexing"
+	RagDocumentStatusIndexed  RagDocumentStatus = "indexed"
+	RagDocumentStatusFailed   RagDocumentStatus = "failed"
+)
+
+// RagDocument records a file or URL indexed into a RAG folder and the chunks it produced.
+// It is used to skip unchanged documents on re-index and to purge chunks when a document is removed.
+type RagDocument struct {
+	ID          uint              `gorm:"primaryKey"`
+	UserID      string            `gorm:"index:idx_user_folder_doc;not null;size:255"`
+	FolderID    string            `gorm:"index:idx_user_folder_doc;not null;size:64"`
+	Source      string            `gorm:"not null;size:2048"`              // File path, URL or minio://bucket/key the document was loaded from
+	ContentHash string            `gorm:"size:64"`                         // SHA-256 of the loaded content
+	ChunkIDs    []string          `gorm:"serializer:json;type:mediumtext"` // IDs of the chunks in the vector store and the doc store
+	Status      RagDocumentStatus `gorm:"not null;size:32"`
+	Error       string            `gorm:"type:text"`                 // Error of the last failed indexing attempt
+	Crawl       *RagCrawlOptions  `gorm:"serializer:json;type:text"` // Set if the URL is crawled as a website, so that re-indexing crawls it again
+	SyncVersion string            `gorm:"size:255"`                  // Modification time and size, or ETag, of the file when a folder sync last indexed it
+	IndexedAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RagCrawlOptions limits which pages of a website are crawled when a URL is indexed as a site.
+type RagCrawlOptions struct {
+	MaxDepth     int      `json:"max_depth"`
+	MaxPages     int      `json:"max_pages"`
+	PathPrefix   string   `json:"path_prefix,omitempty"`
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+}
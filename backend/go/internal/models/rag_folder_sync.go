@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// RagFolderSyncSource is the kind of source a RAG folder is kept in sync with.
+type RagFolderSyncSource string
+
+const (
+	RagFolderSyncSourceDirectory RagFolderSyncSource = "directory"
+	RagFolderSyncSourceMinIO     RagFolderSyncSource = "minio"
+)
+
+// RagFolderSyncStatus is the status of a RAG folder's sync.
+type RagFolderSyncStatus string
+
+const (
+	RagFolderSyncStatusIdle    RagFolderSyncStatus = "idle"
+	RagFolderSyncStatusRunning RagFolderSyncStatus = "running"
+	RagFolderSyncStatusFailed  RagFolderSyncStatus = "failed"
+)
+
+// RagFolderSync binds a RAG folder to a local directory or a MinIO bucket prefix that is polled for changes.
+// A folder has at most one sync source. The counts record the outcome of the last run.
+type RagFolderSync struct {
+	ID              uint                `gorm:"primaryKey"`
+	UserID          string              `gorm:"index;not null;size:255"`
+	FolderID        string              `gorm:"uniqueIndex;not null;size:64"`
+	Source          RagFolderSyncSource `gorm:"not null;size:32"`
+	Directory       string              `gorm:"size:2048"` // Set for directory sources
+	Bucket          string              `gorm:"size:255"`  // Set for MinIO sources
+	Prefix          string              `gorm:"size:1024"` // Set for MinIO sources; empty syncs the whole bucket
+	IntervalSeconds int                 `gorm:"not null"`
+	Status          RagFolderSyncStatus `gorm:"not null;size:32"`
+	LastRunAt       *time.Time
+	LastError       string `gorm:"type:text"`
+	Added           int
+	Updated         int
+	Deleted         int
+	Unchanged       int
+	Failed          int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Due reports whether the source should be polled again.
+func (s *RagFolderSync) Due(now time.Time) bool {
+	return s.LastRunAt == nil || !now.Before(s.LastRunAt.Add(time.Duration(s.IntervalSeconds)*time.Second))
+}
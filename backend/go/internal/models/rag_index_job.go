@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// RagIndexJobStatus is the status of an asynchronous RAG indexing job.
+type RagIndexJobStatus string
+
+const (
+	RagIndexJobStatusQueued    RagIndexJobStatus = "queued"
+	RagIndexJobStatusRunning   RagIndexJobStatus = "running"
+	RagIndexJobStatusCompleted RagIndexJobStatus = "completed"
+	RagIndexJobStatusFailed    RagIndexJobStatus = "failed"
+)
+
+// RagIndexJobFileStatus is the status of one file of a RAG indexing job.
+type RagIndexJobFileStatus string
+
+const (
+	RagIndexJobFileStatusPending  RagIndexJobFileStatus = "pending"
+	RagIndexJobFileStatusIndexing RagIndexJobFileStatus = "indexing"
+	RagIndexJobFileStatusIndexed  RagIndexJobFileStatus = "indexed"
+	RagIndexJobFileStatusSkipped  RagIndexJobFileStatus = "skipped"
+	RagIndexJobFileStatusFailed   RagIndexJobFileStatus = "failed"
+)
+
+// Done reports whether the file has been processed.
+func (s RagIndexJobFileStatus) Done() bool {
+	return s == RagIndexJobFileStatusIndexed || s == RagIndexJobFileStatusSkipped || s == RagIndexJobFileStatusFailed
+}
+
+// RagIndexJob is a request to index files into a RAG folder that is processed in the background.
+type RagIndexJob struct {
+	ID         string            `gorm:"primaryKey;size:36"` // UUID, also the key of the job's Kafka message
+	UserID     string            `gorm:"index;not null;size:255"`
+	FolderID   string            `gorm:"not null;size:64"`
+	Force      bool              // Re-index files even if their content is unchanged
+	Crawl      *RagCrawlOptions  `gorm:"serializer:json;type:text"` // Crawl URLs as websites
+	Status     RagIndexJobStatus `gorm:"not null;size:32"`
+	Error      string            `gorm:"type:text"` // Why the job could not be processed
+	Files      []RagIndexJobFile `gorm:"foreignKey:JobID"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// RagIndexJobFile records the result of indexing one path of a RagIndexJob.
+type RagIndexJobFile struct {
+	ID         uint                  `gorm:"primaryKey"`
+	JobID      string                `gorm:"index;not null;size:36"`
+	Path       string                `gorm:"not null;size:2048"`
+	Status     RagIndexJobFileStatus `gorm:"not null;size:32"`
+	Error      string                `gorm:"type:text"`
+	ChunkCount int
+	UpdatedAt  time.Time
+}
+
+// RagIndexJobMessage is the Kafka message that hands a queued job to the indexing workers.
+type RagIndexJobMessage struct {
+	JobID  string `json:"job_id"`
+	UserID string `json:"user_id"`
+}
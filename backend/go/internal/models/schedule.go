@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Schedule 代表一个按 cron 表达式或固定间隔周期性提交的任务计划
+type Schedule struct {
+	ID              string    `bson:"_id"`              // 计划唯一ID (UUID string)
+	UserID          string    `bson:"user_id"`          // 计划所属的用户ID
+	Name            string    `bson:"name"`             // 计划的显示名称
+	Content         string    `bson:"content"`          // 每次触发时提交的任务内容
+	CronExpr        string    `bson:"cron_expr"`        // 标准 5 段 cron 表达式，与 IntervalSeconds 二选一
+	IntervalSeconds int64     `bson:"interval_seconds"` // 固定触发间隔（秒），与 CronExpr 二选一
+	Timezone        string    `bson:"timezone"`         // cron 表达式使用的时区，为空时使用 UTC
+	Enabled         bool      `bson:"enabled"`          // 计划是否启用
+	NextRunAt       time.Time `bson:"next_run_at"`      // 下一次应触发的时间
+	LastRunAt       time.Time `bson:"last_run_at"`      // 上一次触发的时间
+	CreatedAt       time.Time `bson:"created_at"`       // 计划创建时间
+	UpdatedAt       time.Time `bson:"updated_at"`       // 计划最后更新时间
+}
+
+// ScheduleRun 代表某个计划的一次触发记录，并关联到其生成的 TaskRecord
+type ScheduleRun struct {
+	ID         string    `bson:"_id"`         // 触发记录唯一ID (UUID string)
+	ScheduleID string    `bson:"schedule_id"` // 所属计划ID
+	UserID     string    `bson:"user_id"`     // 计划所属的用户ID
+	TaskID     string    `bson:"task_id"`     // 本次触发生成的 TaskRecord ID，提交失败时为空
+	Error      string    `bson:"error"`       // 提交任务失败时的错误信息
+	FiredAt    time.Time `bson:"fired_at"`    // 触发时间
+}
@@ -12,8 +12,14 @@ const (
 	TaskStatusRunning   TaskStatus = "running"
 	TaskStatusSuccess   TaskStatus = "success"
 	TaskStatusFailed    TaskStatus = "failed"
+	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
+// IsTerminal 判断任务状态是否为终态（成功、失败或已取消）
+func (s TaskStatus) IsTerminal() bool {
+	return s == TaskStatusSuccess || s == TaskStatusFailed || s == TaskStatusCancelled
+}
+
 // TaskRecord 代表一个持久化的 agent 任务记录
 type TaskRecord struct {
 	ID          string      `bson:"_id"`         // 任务唯一ID (使用 a UUID string)
@@ -22,6 +28,7 @@ type TaskRecord struct {
 	Payload     interface{} `bson:"payload"`     // 任务的输入/内容
 	Result      interface{} `bson:"result"`      // 任务成功后的输出结果
 	Error       string      `bson:"error"`       // 任务失败时的错误信息
+	IdempotencyKey string   `bson:"idempotency_key,omitempty"` // 客户端提供的幂等键，同一用户下唯一
 	SubmittedAt time.Time   `bson:"submitted_at"`// 任务提交时间
 	CompletedAt time.Time   `bson:"completed_at"`// 任务完成时间
 }
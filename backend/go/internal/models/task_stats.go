@@ -0,0 +1,8 @@
+package models
+
+// TaskStats 是某个用户在一段时间内任务的聚合统计
+type TaskStats struct {
+	Total              int64                `json:"total"`                // 任务总数
+	ByStatus           map[TaskStatus]int64 `json:"by_status"`            // 按状态统计的任务数
+	AvgDurationSeconds float64              `json:"avg_duration_seconds"` // 已完成任务从提交到完成的平均耗时（秒）
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// WebhookEvent 定义了可以触发 webhook 的任务事件类型
+type WebhookEvent string
+
+const (
+	WebhookEventTaskSucceeded WebhookEvent = "task.succeeded"
+	WebhookEventTaskFailed    WebhookEvent = "task.failed"
+	WebhookEventTaskCancelled WebhookEvent = "task.cancelled"
+)
+
+// WebhookDeliveryStatus 定义了一次 webhook 投递的状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookEndpoint 代表用户注册的一个 webhook 接收端点
+type WebhookEndpoint struct {
+	ID        string         `bson:"_id"`        // 端点唯一ID (UUID string)
+	UserID    string         `bson:"user_id"`    // 端点所属的用户ID
+	URL       string         `bson:"url"`        // 接收 POST 请求的地址
+	Secret    string         `bson:"secret"`     // 用于 HMAC-SHA256 签名的密钥
+	TaskID    string         `bson:"task_id"`    // 仅关注某个任务时填写，为空表示该用户的所有任务
+	Events    []WebhookEvent `bson:"events"`     // 订阅的事件，为空表示订阅所有事件
+	Enabled   bool           `bson:"enabled"`    // 端点是否启用
+	CreatedAt time.Time      `bson:"created_at"` // 端点创建时间
+}
+
+// WebhookDelivery 代表一次 webhook 投递及其结果，用于投递日志与手动重投
+type WebhookDelivery struct {
+	ID            string                `bson:"_id"`             // 投递唯一ID (UUID string)，同时作为请求头中的投递ID
+	EndpointID    string                `bson:"endpoint_id"`     // 目标端点ID
+	UserID        string                `bson:"user_id"`         // 端点所属的用户ID
+	TaskID        string                `bson:"task_id"`         // 触发本次投递的任务ID
+	Event         WebhookEvent          `bson:"event"`           // 触发本次投递的事件
+	Payload       string                `bson:"payload"`         // 发送的 JSON 请求体
+	Status        WebhookDeliveryStatus `bson:"status"`          // 投递状态
+	Attempts      int                   `bson:"attempts"`        // 已尝试的次数
+	ResponseCode  int                   `bson:"response_code"`   // 最后一次尝试的 HTTP 状态码
+	Error         string                `bson:"error"`           // 最后一次尝试的错误信息
+	CreatedAt     time.Time             `bson:"created_at"`      // 投递创建时间
+	LastAttemptAt time.Time             `bson:"last_attempt_at"` // 最后一次尝试时间
+}
@@ -0,0 +1,220 @@
+package components
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/dedup"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/embeddings"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/llms"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/rerankers"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/docstore"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/embeddingcache"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/keywordstore"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/objectstore"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/database/milvus"
+	"Jarvis_2.0/backend/go/internal/database/minio"
+	"Jarvis_2.0/backend/go/internal/database/mongo"
+	"Jarvis_2.0/backend/go/internal/database/neo4j"
+	"Jarvis_2.0/backend/go/internal/database/redis"
+	"Jarvis_2.0/backend/go/internal/embedding"
+	"Jarvis_2.0/backend/go/internal/llm"
+	"Jarvis_2.0/backend/go/internal/memory/store"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"Jarvis_2.0/backend/go/pkg/ratelimiter"
+)
+
+// NewVectorStore creates the chunk vector store selected by rag.vector_store.backend. The local backend runs in
+// process, so indexing and retrieval work without a Milvus deployment.
+func NewVectorStore(cfg *config.AppConfig, log *logger.Logger) (interfaces.VectorStore, error) {
+	vectorStoreCfg := cfg.RAG.VectorStore
+	switch vectorStoreCfg.Backend {
+	case "milvus", "":
+		milvusClient, err := milvus.GetClient(context.Background(), &cfg.Databases.Milvus)
+		if err != nil {
+			return nil, err
+		}
+		return vectorstore.NewMilvusStore(milvusClient, cfg.Databases.Milvus.Schema.CollectionName, *log)
+	case "local":
+		return vectorstore.NewLocalStore(vectorStoreCfg.SnapshotPath, *log)
+	default:
+		return nil, fmt.Errorf("unsupported vector store backend %q", vectorStoreCfg.Backend)
+	}
+}
+
+// NewDeduplicator creates the duplicate chunk detector configured under rag.dedup, keeping fingerprints in the
+// given store. It returns nil if deduplication is disabled.
+func NewDeduplicator(ctx context.Context, cfg *config.AppConfig, store dedup.Store, log *logger.Logger) (*dedup.Deduplicator, error) {
+	dedupCfg := cfg.RAG.Dedup
+	if !dedupCfg.Enabled {
+		return nil, nil
+	}
+	return dedup.NewDeduplicator(ctx, store, dedup.Config{
+		MaxDistance:  dedupCfg.MaxDistance,
+		SnapshotPath: dedupCfg.SnapshotPath,
+		Capacity:     dedupCfg.Capacity,
+		ErrorRate:    dedupCfg.ErrorRate,
+	}, *log)
+}
+
+// NewMediaExtractor creates the image and table extractor configured under rag.media, which captions images with
+// the given Gemini client and stores their originals in MinIO. It returns nil if the extraction is disabled.
+func NewMediaExtractor(ctx context.Context, cfg *config.AppConfig, geminiLLM *llm.Gemini, log *logger.Logger) (*pipeline.MediaExtractor, error) {
+	mediaCfg := cfg.RAG.Media
+	if !mediaCfg.Enabled {
+		return nil, nil
+	}
+	minioClient, err := minio.GetClient(&cfg.Databases.MinIO)
+	if err != nil {
+		return nil, err
+	}
+	images, err := objectstore.NewMinIOStore(ctx, minioClient, mediaCfg.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	// Images are captioned concurrently and independently of each other, so no chat history is kept.
+	return pipeline.NewMediaExtractor(geminiLLM.Stateless(), images, pipeline.MediaConfig{
+		MinImageSize: mediaCfg.MinImageSize,
+		Concurrency:  mediaCfg.Concurrency,
+	}, *log), nil
+}
+
+// NewGraphRetriever creates the knowledge graph expansion configured under rag.graph, which reads the entity graph
+// the memory service builds in Neo4j. It returns nil if graph retrieval is disabled.
+func NewGraphRetriever(ctx context.Context, cfg *config.AppConfig) (*pipeline.GraphRetriever, error) {
+	graphCfg := cfg.RAG.Graph
+	if !graphCfg.Enabled {
+		return nil, nil
+	}
+	if graphCfg.MaxHops < 0 || graphCfg.MaxHops > 2 {
+		return nil, fmt.Errorf("max_hops must be 1 or 2, got %d", graphCfg.MaxHops)
+	}
+	neo4jClient, err := neo4j.GetClient(ctx, &cfg.Databases.Neo4j)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.NewGraphRetriever(store.NewNeo4jStore(neo4jClient), graphCfg.MaxHops, graphCfg.MaxFacts), nil
+}
+
+// NewDocStore creates the persistent chunk store configured under rag.doc_store.
+func NewDocStore(cfg *config.AppConfig) (interfaces.DocStore, error) {
+	docStoreCfg := cfg.RAG.DocStore
+	switch docStoreCfg.Backend {
+	case "mongo", "":
+		mongoClient, err := mongo.GetClient(&cfg.Databases.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		store := docstore.NewMongoDocStore(mongoClient.Database(cfg.Databases.MongoDB.Database), docStoreCfg.Collection)
+		if err := store.EnsureIndexes(context.Background()); err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "redis":
+		redisClient, err := redis.GetClient(&cfg.Databases.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return docstore.NewRedisDocStore(redisClient, docStoreCfg.KeyPrefix), nil
+	case "memory":
+		return docstore.NewInMemoryDocStore(), nil
+	default:
+		return nil, fmt.Errorf("unsupported doc store backend %q", docStoreCfg.Backend)
+	}
+}
+
+// NewKeywordIndex creates the BM25 keyword index configured under rag.keyword_index.
+// It returns nil if keyword search is disabled. The index is an io.Closer that must be closed to write its final
+// snapshot.
+func NewKeywordIndex(cfg *config.AppConfig, log *logger.Logger) (interfaces.KeywordIndex, error) {
+	if !cfg.RAG.KeywordIndex.Enabled {
+		return nil, nil
+	}
+	return keywordstore.NewBM25Index(cfg.RAG.KeywordIndex.SnapshotPath, *log)
+}
+
+// NewEmbedder wraps the Gemini embedding model as configured under rag.embeddings: texts are embedded in batches of
+// the provider's size, concurrently under a rate limit and with retries, behind a vector cache.
+func NewEmbedder(cfg *config.AppConfig, geminiEmbedding *embedding.GoogleModel, log *logger.Logger) (interfaces.EmbeddingModel, error) {
+	embeddingsCfg := cfg.RAG.Embeddings
+	batchingCfg := embeddings.BatchingConfig{
+		BatchSize:   embeddingsCfg.BatchSize,
+		Concurrency: embeddingsCfg.Concurrency,
+		MaxRetries:  embeddingsCfg.MaxRetries,
+		Retryable:   embedding.IsRetryable,
+	}
+	if batchingCfg.BatchSize <= 0 {
+		batchingCfg.BatchSize = embedding.DefaultBatchSize(embedding.Google)
+	}
+	if embeddingsCfg.InitialBackoff != "" {
+		backoff, err := time.ParseDuration(embeddingsCfg.InitialBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid initial_backoff: %w", err)
+		}
+		batchingCfg.InitialBackoff = backoff
+	}
+	if rateLimit := embeddingsCfg.RateLimit; rateLimit.Rate > 0 {
+		batchingCfg.Limiter = ratelimiter.NewTokenBucket(rateLimit.Rate, max(rateLimit.Capacity, 1))
+	}
+	var embedder interfaces.EmbeddingModel = embeddings.NewBatchingEmbedder(embeddings.NewGenaiAdapter(geminiEmbedding), batchingCfg)
+
+	var cache interfaces.EmbeddingCache
+	switch cacheCfg := embeddingsCfg.Cache; cacheCfg.Backend {
+	case "none", "":
+		return embedder, nil
+	case "redis":
+		var ttl time.Duration
+		if cacheCfg.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(cacheCfg.TTL); err != nil {
+				return nil, fmt.Errorf("invalid embedding cache ttl: %w", err)
+			}
+		}
+		redisClient, err := redis.GetClient(&cfg.Databases.Redis)
+		if err != nil {
+			return nil, err
+		}
+		cache = embeddingcache.NewRedisCache(redisClient, cacheCfg.KeyPrefix, ttl)
+	case "disk":
+		diskCache, err := embeddingcache.NewDiskCache(cacheCfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		cache = diskCache
+	default:
+		return nil, fmt.Errorf("unsupported embedding cache backend %q", cacheCfg.Backend)
+	}
+	return embeddings.NewCachedEmbedder(embedder, cache, cfg.Embedding.Gemini.Model, *log), nil
+}
+
+// NewReranker creates the reranker configured under rag.reranker. It returns nil for "none", which disables reranking.
+func NewReranker(rerankerCfg config.RerankerConfig, embedder interfaces.EmbeddingModel, geminiLLM *llm.Gemini) (interfaces.Reranker, error) {
+	switch rerankerCfg.Type {
+	case "none", "":
+		return nil, nil
+	case "cohere":
+		// The Cohere API key is read from the environment to keep it out of the config file.
+		cohereAPIKey := os.Getenv("COHERE_API_KEY")
+		if cohereAPIKey == "" {
+			return nil, fmt.Errorf("COHERE_API_KEY environment variable is required for the cohere reranker")
+		}
+		return rerankers.NewCohereReranker(cohereAPIKey, rerankerCfg.CohereModel, rerankerCfg.TopN), nil
+	case "llm":
+		return rerankers.NewLLMReranker(llms.NewGeminiAdapter(geminiLLM.Stateless()), rerankerCfg.TopN), nil
+	case "embedding":
+		return rerankers.NewEmbeddingReranker(embedder, rerankerCfg.TopN), nil
+	case "mmr":
+		if rerankerCfg.MMRLambda < 0 || rerankerCfg.MMRLambda > 1 {
+			return nil, fmt.Errorf("mmr_lambda must be between 0 and 1, got %v", rerankerCfg.MMRLambda)
+		}
+		return rerankers.NewMMRReranker(embedder, rerankerCfg.MMRLambda, rerankerCfg.TopN), nil
+	default:
+		return nil, fmt.Errorf("unsupported reranker type %q", rerankerCfg.Type)
+	}
+}
@@ -0,0 +1,70 @@
+package consumer
+
+import (
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/database/kafka"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/rag_service/service"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// IndexJobRunner runs queued indexing jobs. It is implemented by service.Server.
+type IndexJobRunner interface {
+	RunIndexJob(ctx context.Context, userID, jobID string) error
+}
+
+// IndexJobConsumer consumes queued RAG indexing jobs from Kafka and runs them on the RAG service.
+// Jobs are processed one at a time; the files of a job are indexed concurrently by the service's workers.
+type IndexJobConsumer struct {
+	consumer   *kafka.Consumer
+	ragService IndexJobRunner
+	logger     *logger.Logger
+}
+
+// NewIndexJobConsumer creates a new IndexJobConsumer.
+func NewIndexJobConsumer(brokers []string, topic, groupID string, retryCfg config.KafkaRetryConfig, ragService IndexJobRunner, logger *logger.Logger) (*IndexJobConsumer, error) {
+	consumer, err := kafka.NewConsumer(brokers, topic, groupID, retryCfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &IndexJobConsumer{
+		consumer:   consumer,
+		ragService: ragService,
+		logger:     logger,
+	}, nil
+}
+
+// Start starts the Kafka consumer.
+func (c *IndexJobConsumer) Start(ctx context.Context) {
+	c.consumer.Start(ctx, c.handle)
+}
+
+// handle runs the job referenced by a message. Running a job again resumes it, so redelivered messages are safe.
+// Malformed messages and unknown jobs are not retried and go straight to the dead-letter topic.
+func (c *IndexJobConsumer) handle(ctx context.Context, msg kafkago.Message) error {
+	var jobMsg models.RagIndexJobMessage
+	if err := json.Unmarshal(msg.Value, &jobMsg); err != nil {
+		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("failed to unmarshal index job message")
+		return fmt.Errorf("%w: %v", kafka.ErrNonRetryable, err)
+	}
+
+	if err := c.ragService.RunIndexJob(ctx, jobMsg.UserID, jobMsg.JobID); err != nil {
+		c.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"job_id": jobMsg.JobID}).Error("failed to run index job")
+		if errors.Is(err, service.ErrIndexJobNotFound) {
+			return fmt.Errorf("%w: %v", kafka.ErrNonRetryable, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Close closes the underlying Kafka readers and writer.
+func (c *IndexJobConsumer) Close() error {
+	return c.consumer.Close()
+}
@@ -0,0 +1,57 @@
+package consumer
+
+import (
+	"Jarvis_2.0/backend/go/internal/database/kafka"
+	"Jarvis_2.0/backend/go/internal/rag_service/service"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// fakeRunner records the jobs it runs and fails them with err.
+type fakeRunner struct {
+	ran []string
+	err error
+}
+
+func (r *fakeRunner) RunIndexJob(_ context.Context, userID, jobID string) error {
+	r.ran = append(r.ran, userID+"/"+jobID)
+	return r.err
+}
+
+func TestIndexJobConsumerHandle(t *testing.T) {
+	interrupted := errors.New("index job j1 interrupted: context canceled")
+	tests := []struct {
+		name             string
+		value            string
+		runErr           error
+		wantRan          bool
+		wantErr          bool
+		wantNonRetryable bool
+	}{
+		{"success", `{"job_id":"j1","user_id":"u1"}`, nil, true, false, false},
+		{"interrupted job is retried", `{"job_id":"j1","user_id":"u1"}`, interrupted, true, true, false},
+		{"unknown job is poisoned", `{"job_id":"j1","user_id":"u1"}`, fmt.Errorf("%w: j1", service.ErrIndexJobNotFound), true, true, true},
+		{"malformed message is poisoned", `{"job_id":`, nil, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{err: tt.runErr}
+			c := &IndexJobConsumer{ragService: runner, logger: logger.New("test", "", "")}
+			err := c.handle(context.Background(), kafkago.Message{Value: []byte(tt.value)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, kafka.ErrNonRetryable) != tt.wantNonRetryable {
+				t.Errorf("handle() error = %v, want non-retryable %v", err, tt.wantNonRetryable)
+			}
+			if ran := len(runner.ran) == 1 && runner.ran[0] == "u1/j1"; ran != tt.wantRan {
+				t.Errorf("ran %v, want the job run %v", runner.ran, tt.wantRan)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package publisher
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// IndexJobPublisher is responsible for publishing queued RAG indexing jobs to Kafka.
+type IndexJobPublisher struct {
+	writer *kafka.Writer
+	logger *logger.Logger
+}
+
+// NewIndexJobPublisher creates a new IndexJobPublisher.
+func NewIndexJobPublisher(brokers []string, topic string, logger *logger.Logger) *IndexJobPublisher {
+	writer := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:  brokers,
+		Topic:    topic,
+		Balancer: &kafka.LeastBytes{},
+	})
+	return &IndexJobPublisher{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+// Publish sends a job message to the Kafka topic, keyed by the job ID.
+func (p *IndexJobPublisher) Publish(ctx context.Context, job *models.RagIndexJob) error {
+	msgBytes, err := json.Marshal(models.RagIndexJobMessage{JobID: job.ID, UserID: job.UserID})
+	if err != nil {
+		p.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to marshal index job for Kafka")
+		return err
+	}
+
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(job.ID),
+		Value: msgBytes,
+	})
+	if err != nil {
+		p.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"topic": p.writer.Topic}).Error("Failed to write index job message to Kafka")
+		return err
+	}
+	return nil
+}
+
+// Close closes the underlying Kafka writer.
+func (p *IndexJobPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,150 @@
+package dal
+
+import (
+	"context"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"gorm.io/gorm"
+)
+
+// fingerprintBatchSize is the number of fingerprints inserted or loaded per statement.
+const fingerprintBatchSize = 500
+
+// ChunkDAL provides data access methods for the fingerprints of stored chunks and the references of documents to
+// chunks stored for other documents.
+type ChunkDAL struct {
+	db *gorm.DB
+}
+
+// NewChunkDAL creates a new ChunkDAL.
+func NewChunkDAL(db *gorm.DB) *ChunkDAL {
+	return &ChunkDAL{db: db}
+}
+
+// FindByContentHash retrieves the oldest fingerprint of the folder with the given content hash, ignoring chunks of
+// the excluded source. It returns nil if there is none.
+func (dal *ChunkDAL) FindByContentHash(ctx context.Context, userID, folderID, excludeSource, contentHash string) (*models.RagChunkFingerprint, error) {
+	var fingerprints []*models.RagChunkFingerprint
+	result := dal.db.WithContext(ctx).
+		Where("user_id = ? AND folder_id = ? AND source <> ? AND content_hash = ?", userID, folderID, excludeSource, contentHash).
+		Order("id").Limit(1).Find(&fingerprints)
+	if result.Error != nil || len(fingerprints) == 0 {
+		return nil, result.Error
+	}
+	return fingerprints[0], nil
+}
+
+// FindByBands retrieves the fingerprints of the folder's near-duplicate candidates that share at least one SimHash
+// band with the given bands, ignoring chunks of the excluded source.
+func (dal *ChunkDAL) FindByBands(ctx context.Context, userID, folderID, excludeSource string, bands [4]uint16) ([]*models.RagChunkFingerprint, error) {
+	var fingerprints []*models.RagChunkFingerprint
+	result := dal.db.WithContext(ctx).
+		Where("user_id = ? AND folder_id = ? AND source <> ? AND near = ?", userID, folderID, excludeSource, true).
+		Where("band0 = ? OR band1 = ? OR band2 = ? OR band3 = ?", bands[0], bands[1], bands[2], bands[3]).
+		Order("id").Find(&fingerprints)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return fingerprints, nil
+}
+
+// SaveFingerprints stores the fingerprints of newly stored chunks.
+func (dal *ChunkDAL) SaveFingerprints(ctx context.Context, fingerprints []*models.RagChunkFingerprint) error {
+	if len(fingerprints) == 0 {
+		return nil
+	}
+	return dal.db.WithContext(ctx).CreateInBatches(fingerprints, fingerprintBatchSize).Error
+}
+
+// ForEachFingerprint calls fn with every stored fingerprint, loading them in batches.
+func (dal *ChunkDAL) ForEachFingerprint(ctx context.Context, fn func(*models.RagChunkFingerprint)) error {
+	var batch []*models.RagChunkFingerprint
+	return dal.db.WithContext(ctx).FindInBatches(&batch, fingerprintBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, fingerprint := range batch {
+			fn(fingerprint)
+		}
+		return nil
+	}).Error
+}
+
+// SetDocumentRefs replaces the chunks a document references with the given chunk IDs.
+// An ID appears once for every chunk of the document that duplicates it.
+func (dal *ChunkDAL) SetDocumentRefs(ctx context.Context, documentID uint, chunkIDs []string) error {
+	return dal.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("document_id = ?", documentID).Delete(&models.RagChunkRef{}).Error; err != nil {
+			return err
+		}
+		if len(chunkIDs) == 0 {
+			return nil
+		}
+		refs := make([]*models.RagChunkRef, len(chunkIDs))
+		for i, id := range chunkIDs {
+			refs[i] = &models.RagChunkRef{DocumentID: documentID, ChunkID: id}
+		}
+		return tx.CreateInBatches(refs, fingerprintBatchSize).Error
+	})
+}
+
+// DeleteDocumentRefs deletes the references of a document.
+func (dal *ChunkDAL) DeleteDocumentRefs(ctx context.Context, documentID uint) error {
+	return dal.db.WithContext(ctx).Where("document_id = ?", documentID).Delete(&models.RagChunkRef{}).Error
+}
+
+// ReleaseChunks gives up the ownership of stored chunks, e.g. because their document is deleted or re-indexed.
+// A chunk that other documents still reference is handed over to the one that referenced it first: it is added to
+// that document's chunk IDs, which no longer references it. The IDs of the chunks nobody references are returned;
+// their fingerprints are deleted and the caller has to remove them from the stores.
+func (dal *ChunkDAL) ReleaseChunks(ctx context.Context, chunkIDs []string) ([]string, error) {
+	if len(chunkIDs) == 0 {
+		return nil, nil
+	}
+	var orphaned []string
+	err := dal.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var refs []*models.RagChunkRef
+		if err := tx.Where("chunk_id IN ?", chunkIDs).Order("id").Find(&refs).Error; err != nil {
+			return err
+		}
+		heirs := make(map[string]uint, len(refs))
+		for _, ref := range refs {
+			if _, ok := heirs[ref.ChunkID]; !ok {
+				heirs[ref.ChunkID] = ref.DocumentID
+			}
+		}
+
+		for _, id := range chunkIDs {
+			documentID, ok := heirs[id]
+			if !ok {
+				orphaned = append(orphaned, id)
+				continue
+			}
+			if err := handOverChunk(tx, id, documentID); err != nil {
+				return err
+			}
+		}
+
+		if len(orphaned) == 0 {
+			return nil
+		}
+		return tx.Where("chunk_id IN ?", orphaned).Delete(&models.RagChunkFingerprint{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orphaned, nil
+}
+
+// handOverChunk makes a document that references a chunk its owner.
+func handOverChunk(tx *gorm.DB, chunkID string, documentID uint) error {
+	var doc models.RagDocument
+	if err := tx.First(&doc, documentID).Error; err != nil {
+		return err
+	}
+	doc.ChunkIDs = append(doc.ChunkIDs, chunkID)
+	if err := tx.Model(&doc).Select("ChunkIDs").Updates(&models.RagDocument{ChunkIDs: doc.ChunkIDs}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("document_id = ? AND chunk_id = ?", documentID, chunkID).Delete(&models.RagChunkRef{}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.RagChunkFingerprint{}).Where("chunk_id = ?", chunkID).Update("source", doc.Source).Error
+}
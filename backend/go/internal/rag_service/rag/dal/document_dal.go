@@ -0,0 +1,72 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"gorm.io/gorm"
+)
+
+// DocumentDAL provides data access methods for the registry of indexed RAG documents.
+type DocumentDAL struct {
+	db *gorm.DB
+}
+
+// NewDocumentDAL creates a new DocumentDAL.
+func NewDocumentDAL(db *gorm.DB) *DocumentDAL {
+	return &DocumentDAL{db: db}
+}
+
+// GetDocument retrieves a document by its ID, ensuring that it belongs to the user.
+// It returns nil if the document does not exist or belongs to another user.
+func (dal *DocumentDAL) GetDocument(ctx context.Context, userID string, documentID uint) (*models.RagDocument, error) {
+	var doc models.RagDocument
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, documentID).First(&doc)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &doc, nil
+}
+
+// GetDocumentBySource retrieves the document indexed from a source into a folder.
+// It returns nil if the source has not been indexed into the folder yet.
+func (dal *DocumentDAL) GetDocumentBySource(ctx context.Context, userID, folderID, source string) (*models.RagDocument, error) {
+	var doc models.RagDocument
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND folder_id = ? AND source = ?", userID, folderID, source).First(&doc)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &doc, nil
+}
+
+// ListDocumentsByFolder retrieves all documents of a user's folder.
+func (dal *DocumentDAL) ListDocumentsByFolder(ctx context.Context, userID, folderID string) ([]*models.RagDocument, error) {
+	var docs []*models.RagDocument
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND folder_id = ?", userID, folderID).Order("id").Find(&docs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return docs, nil
+}
+
+// SaveDocument creates the document if it has no ID yet, otherwise updates it.
+func (dal *DocumentDAL) SaveDocument(ctx context.Context, doc *models.RagDocument) error {
+	return dal.db.WithContext(ctx).Save(doc).Error
+}
+
+// SetSyncVersion records the version of the file a document was last synced from.
+func (dal *DocumentDAL) SetSyncVersion(ctx context.Context, documentID uint, version string) error {
+	return dal.db.WithContext(ctx).Model(&models.RagDocument{}).Where("id = ?", documentID).Update("sync_version", version).Error
+}
+
+// DeleteDocument deletes a document from the registry.
+func (dal *DocumentDAL) DeleteDocument(ctx context.Context, userID string, documentID uint) error {
+	return dal.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, documentID).Delete(&models.RagDocument{}).Error
+}
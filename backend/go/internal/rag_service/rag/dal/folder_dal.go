@@ -8,6 +8,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrFolderNotFound is returned when a folder does not exist or belongs to another user.
+var ErrFolderNotFound = errors.New("folder not found or user does not have permission to delete it")
+
 // FolderDAL provides data access methods for RAG folders.
 type FolderDAL struct {
 	db *gorm.DB
@@ -35,6 +38,20 @@ func (dal *FolderDAL) CreateFolder(ctx context.Context, userID, folderName strin
 	return folder, nil
 }
 
+// GetFolder retrieves a folder by its ID, ensuring that it belongs to the user.
+// It returns nil if the folder does not exist or belongs to another user.
+func (dal *FolderDAL) GetFolder(ctx context.Context, userID string, folderID uint) (*models.RagFolder, error) {
+	var folder models.RagFolder
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, folderID).First(&folder)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &folder, nil
+}
+
 // ListFoldersByUser retrieves all folders for a given user.
 func (dal *FolderDAL) ListFoldersByUser(ctx context.Context, userID string) ([]*models.RagFolder, error) {
 	var folders []*models.RagFolder
@@ -55,7 +72,7 @@ func (dal *FolderDAL) DeleteFolder(ctx context.Context, userID string, folderID
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("folder not found or user does not have permission to delete it")
+		return ErrFolderNotFound
 	}
 
 	return nil
@@ -0,0 +1,75 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"gorm.io/gorm"
+)
+
+// FolderSyncDAL provides data access methods for the sync sources of RAG folders.
+type FolderSyncDAL struct {
+	db *gorm.DB
+}
+
+// NewFolderSyncDAL creates a new FolderSyncDAL.
+func NewFolderSyncDAL(db *gorm.DB) *FolderSyncDAL {
+	return &FolderSyncDAL{db: db}
+}
+
+// GetSync retrieves the sync source of a user's folder.
+// It returns nil if the folder has no sync source.
+func (dal *FolderSyncDAL) GetSync(ctx context.Context, userID, folderID string) (*models.RagFolderSync, error) {
+	var sync models.RagFolderSync
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND folder_id = ?", userID, folderID).First(&sync)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &sync, nil
+}
+
+// ListSyncsByUser retrieves the sync sources of all of a user's folders.
+func (dal *FolderSyncDAL) ListSyncsByUser(ctx context.Context, userID string) ([]*models.RagFolderSync, error) {
+	var syncs []*models.RagFolderSync
+	result := dal.db.WithContext(ctx).Where("user_id = ?", userID).Find(&syncs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return syncs, nil
+}
+
+// ListSyncs retrieves the sync sources of all folders.
+func (dal *FolderSyncDAL) ListSyncs(ctx context.Context) ([]*models.RagFolderSync, error) {
+	var syncs []*models.RagFolderSync
+	result := dal.db.WithContext(ctx).Order("id").Find(&syncs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return syncs, nil
+}
+
+// SaveSync creates the sync source if it has no ID yet, otherwise updates it.
+func (dal *FolderSyncDAL) SaveSync(ctx context.Context, sync *models.RagFolderSync) error {
+	return dal.db.WithContext(ctx).Save(sync).Error
+}
+
+// UpdateSyncStatus updates the status and the results of the last run of a sync source, leaving the source itself
+// untouched in case it was changed while the sync was running.
+func (dal *FolderSyncDAL) UpdateSyncStatus(ctx context.Context, sync *models.RagFolderSync) error {
+	return dal.db.WithContext(ctx).Model(sync).
+		Select("Status", "LastRunAt", "LastError", "Added", "Updated", "Deleted", "Unchanged", "Failed").
+		Updates(sync).Error
+}
+
+// DeleteSync deletes the sync source of a user's folder. It returns false if the folder had none.
+func (dal *FolderSyncDAL) DeleteSync(ctx context.Context, userID, folderID string) (bool, error) {
+	result := dal.db.WithContext(ctx).Where("user_id = ? AND folder_id = ?", userID, folderID).Delete(&models.RagFolderSync{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
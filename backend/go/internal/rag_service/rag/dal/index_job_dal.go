@@ -0,0 +1,55 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"gorm.io/gorm"
+)
+
+// IndexJobDAL provides data access methods for asynchronous RAG indexing jobs.
+type IndexJobDAL struct {
+	db *gorm.DB
+}
+
+// NewIndexJobDAL creates a new IndexJobDAL.
+func NewIndexJobDAL(db *gorm.DB) *IndexJobDAL {
+	return &IndexJobDAL{db: db}
+}
+
+// CreateJob creates a job together with its files.
+func (dal *IndexJobDAL) CreateJob(ctx context.Context, job *models.RagIndexJob) error {
+	return dal.db.WithContext(ctx).Create(job).Error
+}
+
+// GetJob retrieves a job with its files, ensuring that it belongs to the user.
+// It returns nil if the job does not exist or belongs to another user.
+func (dal *IndexJobDAL) GetJob(ctx context.Context, userID, jobID string) (*models.RagIndexJob, error) {
+	var job models.RagIndexJob
+	result := dal.db.WithContext(ctx).
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("user_id = ? AND id = ?", userID, jobID).
+		First(&job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// UpdateJob saves the status fields of a job. Its files are not touched.
+func (dal *IndexJobDAL) UpdateJob(ctx context.Context, job *models.RagIndexJob) error {
+	return dal.db.WithContext(ctx).Model(job).
+		Select("Status", "Error", "StartedAt", "FinishedAt").
+		Updates(job).Error
+}
+
+// UpdateFile saves the result of a file of a job.
+func (dal *IndexJobDAL) UpdateFile(ctx context.Context, file *models.RagIndexJobFile) error {
+	return dal.db.WithContext(ctx).Model(file).
+		Select("Status", "Error", "ChunkCount").
+		Updates(file).Error
+}
@@ -0,0 +1,385 @@
+// Package dedup detects chunks that duplicate chunks already stored for a RAG folder, so that indexing overlapping
+// documents does not store the same text many times.
+//
+// Chunks are compared by the SHA-256 of their normalized text, which finds exact duplicates, and by the SimHash of
+// their word bigrams, which finds near-duplicates that differ in a few words. Fingerprints are kept in a Store; a
+// scalable Bloom filter over them answers most lookups of new chunks without querying the store.
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"Jarvis_2.0/backend/go/pkg/util"
+)
+
+// MaxDistance is the largest supported Hamming distance between the SimHashes of near-duplicates. Up to this
+// distance two fingerprints share at least one of their four bands, which the store looks candidates up by.
+const MaxDistance = 3
+
+const (
+	// shingleSize is the number of consecutive tokens that form a SimHash feature. Longer shingles make the SimHash
+	// of a chunk change more with every edited word.
+	shingleSize = 2
+	// minShingles is the number of shingles a chunk needs for near-duplicate detection. The SimHash of shorter texts
+	// is too coarse, so they are only deduplicated if they are identical.
+	minShingles = 8
+)
+
+// Default parameters of the Bloom filter.
+const (
+	defaultCapacity  = 100000
+	defaultErrorRate = 0.01
+)
+
+// Store persists the fingerprints of stored chunks. It is implemented by dal.ChunkDAL.
+type Store interface {
+	FindByContentHash(ctx context.Context, userID, folderID, excludeSource, contentHash string) (*models.RagChunkFingerprint, error)
+	FindByBands(ctx context.Context, userID, folderID, excludeSource string, bands [4]uint16) ([]*models.RagChunkFingerprint, error)
+	SaveFingerprints(ctx context.Context, fingerprints []*models.RagChunkFingerprint) error
+	ForEachFingerprint(ctx context.Context, fn func(*models.RagChunkFingerprint)) error
+}
+
+// Config configures a Deduplicator.
+type Config struct {
+	MaxDistance  int     // Largest Hamming distance of near-duplicates, at most MaxDistance; 0 only finds exact duplicates
+	SnapshotPath string  // File the Bloom filter is persisted to; if empty, it is rebuilt from the store on start
+	Capacity     uint    // Initial capacity of the Bloom filter, which grows beyond it
+	ErrorRate    float64 // False positive rate of the Bloom filter
+}
+
+// Deduplicator finds duplicate chunks within the scope of a user's folder.
+type Deduplicator struct {
+	store     Store
+	filter    *util.ScalableBloomFilter
+	cfg       Config
+	log       logger.Logger
+	persistMu sync.Mutex // Serializes writes of the snapshot
+}
+
+// NewDeduplicator creates a Deduplicator, loading its Bloom filter from the snapshot or, if there is none or it
+// cannot be read, rebuilding it from the fingerprints in the store.
+func NewDeduplicator(ctx context.Context, store Store, cfg Config, log logger.Logger) (*Deduplicator, error) {
+	if cfg.MaxDistance < 0 || cfg.MaxDistance > MaxDistance {
+		return nil, fmt.Errorf("max distance must be between 0 and %d, got %d", MaxDistance, cfg.MaxDistance)
+	}
+	if cfg.Capacity == 0 {
+		cfg.Capacity = defaultCapacity
+	}
+	if cfg.ErrorRate == 0 {
+		cfg.ErrorRate = defaultErrorRate
+	}
+	d := &Deduplicator{store: store, cfg: cfg, log: log}
+
+	if cfg.SnapshotPath != "" {
+		if err := os.MkdirAll(filepath.Dir(cfg.SnapshotPath), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create dedup snapshot directory: %w", err)
+		}
+		filter, err := util.NewScalableBloomFilterFromFile(cfg.SnapshotPath)
+		if err == nil {
+			d.filter = filter
+			return d, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn(fmt.Sprintf("Failed to load dedup snapshot, rebuilding it: %v", err))
+		}
+	}
+
+	if err := d.rebuild(ctx); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// rebuild fills a new Bloom filter with the fingerprints in the store.
+func (d *Deduplicator) rebuild(ctx context.Context) error {
+	filter, err := util.NewScalableBloomFilter(util.SBFConfig{
+		InitialCapacity:      d.cfg.Capacity,
+		ErrorRate:            d.cfg.ErrorRate,
+		GrowthFactor:         2,
+		ErrorTighteningRatio: 0.5,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create bloom filter: %w", err)
+	}
+	d.filter = filter
+
+	count := 0
+	err = d.store.ForEachFingerprint(ctx, func(fingerprint *models.RagChunkFingerprint) {
+		d.addToFilter(fingerprint)
+		count++
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load chunk fingerprints: %w", err)
+	}
+	d.log.Info(fmt.Sprintf("Rebuilt dedup filter from %d chunk fingerprints", count))
+	return d.persist()
+}
+
+// Result is the outcome of checking the chunks of a document for duplicates.
+type Result struct {
+	Unique []*schema.Document // The chunks to store
+	// DuplicateOf holds, for every duplicate chunk, the ID of the chunk it duplicates: a chunk stored for another
+	// document of the folder, or a chunk in Unique that occurs earlier in the document.
+	DuplicateOf []string
+
+	fingerprints []*models.RagChunkFingerprint // Of the chunks in Unique, recorded once they are stored
+}
+
+// fingerprintedChunk is a chunk of the checked document with its fingerprints.
+type fingerprintedChunk struct {
+	fingerprint *models.RagChunkFingerprint
+	simHash     uint64
+	bands       [4]uint16
+	near        bool // Whether the chunk is long enough for near-duplicate detection
+}
+
+// Check splits the chunks of a document into unique chunks and duplicates of chunks stored for the folder or of
+// earlier chunks of the document. Chunks of the document's own source are not matched, so that re-indexing a
+// document stores its chunks again. The fingerprints of the unique chunks have to be recorded with Record once
+// they are stored.
+func (d *Deduplicator) Check(ctx context.Context, userID, folderID, source string, chunks []*schema.Document) (*Result, error) {
+	result := &Result{}
+	// Unique chunks of the document so far, by content hash and by SimHash band.
+	byHash := make(map[string]*fingerprintedChunk)
+	var byBand [4]map[uint16][]*fingerprintedChunk
+	for i := range byBand {
+		byBand[i] = make(map[uint16][]*fingerprintedChunk)
+	}
+
+	for _, chunk := range chunks {
+		c := newFingerprintedChunk(userID, folderID, source, chunk)
+
+		duplicateOf := ""
+		if earlier, ok := byHash[c.fingerprint.ContentHash]; ok {
+			duplicateOf = earlier.fingerprint.ChunkID
+		}
+		if duplicateOf == "" {
+			stored, err := d.findExact(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			duplicateOf = stored
+		}
+		if duplicateOf == "" && c.near && d.cfg.MaxDistance > 0 {
+			duplicateOf = d.findNearInDocument(c, byBand)
+			if duplicateOf == "" {
+				stored, err := d.findNear(ctx, c)
+				if err != nil {
+					return nil, err
+				}
+				duplicateOf = stored
+			}
+		}
+		if duplicateOf != "" {
+			result.DuplicateOf = append(result.DuplicateOf, duplicateOf)
+			continue
+		}
+
+		result.Unique = append(result.Unique, chunk)
+		result.fingerprints = append(result.fingerprints, c.fingerprint)
+		byHash[c.fingerprint.ContentHash] = c
+		for i, band := range c.bands {
+			byBand[i][band] = append(byBand[i][band], c)
+		}
+	}
+	return result, nil
+}
+
+// findExact returns the ID of a stored chunk with the same content hash, or "" if there is none.
+func (d *Deduplicator) findExact(ctx context.Context, c *fingerprintedChunk) (string, error) {
+	fp := c.fingerprint
+	if !d.filter.Test(hashKey(fp.UserID, fp.FolderID, fp.ContentHash)) {
+		return "", nil
+	}
+	stored, err := d.store.FindByContentHash(ctx, fp.UserID, fp.FolderID, fp.Source, fp.ContentHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up duplicate chunks: %w", err)
+	}
+	if stored == nil {
+		return "", nil
+	}
+	return stored.ChunkID, nil
+}
+
+// findNear returns the ID of the stored chunk whose SimHash is closest to the chunk's within the maximum distance,
+// or "" if there is none.
+func (d *Deduplicator) findNear(ctx context.Context, c *fingerprintedChunk) (string, error) {
+	fp := c.fingerprint
+	mayExist := false
+	for i, band := range c.bands {
+		if d.filter.Test(bandKey(fp.UserID, fp.FolderID, i, band)) {
+			mayExist = true
+			break
+		}
+	}
+	if !mayExist {
+		return "", nil
+	}
+
+	candidates, err := d.store.FindByBands(ctx, fp.UserID, fp.FolderID, fp.Source, c.bands)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up near-duplicate chunks: %w", err)
+	}
+	closest, closestDistance := "", d.cfg.MaxDistance+1
+	for _, candidate := range candidates {
+		if !candidate.Near {
+			continue
+		}
+		if distance := util.HammingDistance(c.simHash, uint64(candidate.SimHash)); distance < closestDistance {
+			closest, closestDistance = candidate.ChunkID, distance
+		}
+	}
+	return closest, nil
+}
+
+// findNearInDocument returns the ID of the earlier unique chunk of the document whose SimHash is closest to the
+// chunk's within the maximum distance, or "" if there is none.
+func (d *Deduplicator) findNearInDocument(c *fingerprintedChunk, byBand [4]map[uint16][]*fingerprintedChunk) string {
+	closest, closestDistance := "", d.cfg.MaxDistance+1
+	for i, band := range c.bands {
+		for _, earlier := range byBand[i][band] {
+			if !earlier.near {
+				continue
+			}
+			if distance := util.HammingDistance(c.simHash, earlier.simHash); distance < closestDistance {
+				closest, closestDistance = earlier.fingerprint.ChunkID, distance
+			}
+		}
+	}
+	return closest
+}
+
+// Record stores the fingerprints of the unique chunks of a checked document once the chunks are stored, so that
+// later documents are checked against them.
+func (d *Deduplicator) Record(ctx context.Context, result *Result) error {
+	if len(result.fingerprints) == 0 {
+		return nil
+	}
+	if err := d.store.SaveFingerprints(ctx, result.fingerprints); err != nil {
+		return fmt.Errorf("failed to save chunk fingerprints: %w", err)
+	}
+	for _, fingerprint := range result.fingerprints {
+		d.addToFilter(fingerprint)
+	}
+	// The store is the source of truth; a stale snapshot only lets some duplicates through.
+	if err := d.persist(); err != nil {
+		d.log.Warn(fmt.Sprintf("Failed to write dedup snapshot: %v", err))
+	}
+	return nil
+}
+
+// addToFilter adds the keys of a fingerprint to the Bloom filter. The bands of chunks that are too short for
+// near-duplicate detection are left out, as they are never matched.
+func (d *Deduplicator) addToFilter(fp *models.RagChunkFingerprint) {
+	d.filter.Add(hashKey(fp.UserID, fp.FolderID, fp.ContentHash))
+	if !fp.Near {
+		return
+	}
+	for i, band := range util.SimHashBands(uint64(fp.SimHash)) {
+		d.filter.Add(bandKey(fp.UserID, fp.FolderID, i, band))
+	}
+}
+
+// persist writes the Bloom filter to the snapshot, if one is configured.
+func (d *Deduplicator) persist() error {
+	if d.cfg.SnapshotPath == "" {
+		return nil
+	}
+	d.persistMu.Lock()
+	defer d.persistMu.Unlock()
+	return d.filter.WriteToFile(d.cfg.SnapshotPath)
+}
+
+// newFingerprintedChunk computes the fingerprints of a chunk.
+func newFingerprintedChunk(userID, folderID, source string, chunk *schema.Document) *fingerprintedChunk {
+	normalized := normalize(chunk.Text)
+	sum := sha256.Sum256([]byte(normalized))
+	features := shingles(tokenize(normalized))
+	simHash := util.SimHash(features)
+	bands := util.SimHashBands(simHash)
+	near := len(features) >= minShingles
+	return &fingerprintedChunk{
+		fingerprint: &models.RagChunkFingerprint{
+			UserID:      userID,
+			FolderID:    folderID,
+			ChunkID:     chunk.ID,
+			Source:      source,
+			ContentHash: hex.EncodeToString(sum[:]),
+			SimHash:     int64(simHash),
+			Band0:       int(bands[0]),
+			Band1:       int(bands[1]),
+			Band2:       int(bands[2]),
+			Band3:       int(bands[3]),
+			Near:        near,
+		},
+		simHash: simHash,
+		bands:   bands,
+		near:    near,
+	}
+}
+
+// normalize lower-cases the text and collapses whitespace, so that chunks differing only in those are identical.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// tokenize splits text into words of letters and digits. Han characters, which are not separated by spaces, are
+// tokens of their own.
+func tokenize(text string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range text {
+		isHan := unicode.Is(unicode.Han, r)
+		if isHan || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if start >= 0 {
+				tokens = append(tokens, text[start:i])
+				start = -1
+			}
+			if isHan {
+				tokens = append(tokens, string(r))
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// shingles returns the sequences of shingleSize consecutive tokens, or the tokens themselves if there are fewer.
+func shingles(tokens []string) []string {
+	if len(tokens) < shingleSize {
+		return tokens
+	}
+	out := make([]string, 0, len(tokens)-shingleSize+1)
+	for i := 0; i+shingleSize <= len(tokens); i++ {
+		out = append(out, strings.Join(tokens[i:i+shingleSize], " "))
+	}
+	return out
+}
+
+// hashKey is the Bloom filter key of a content hash within a folder.
+func hashKey(userID, folderID, contentHash string) []byte {
+	return []byte(userID + "\x00" + folderID + "\x00h:" + contentHash)
+}
+
+// bandKey is the Bloom filter key of a SimHash band within a folder.
+func bandKey(userID, folderID string, band int, value uint16) []byte {
+	return []byte(fmt.Sprintf("%s\x00%s\x00b%d:%d", userID, folderID, band, value))
+}
@@ -0,0 +1,174 @@
+package dedup
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// memoryStore is a Store keeping fingerprints in memory.
+type memoryStore struct {
+	fingerprints []*models.RagChunkFingerprint
+}
+
+func (s *memoryStore) FindByContentHash(_ context.Context, userID, folderID, excludeSource, contentHash string) (*models.RagChunkFingerprint, error) {
+	for _, fp := range s.fingerprints {
+		if fp.UserID == userID && fp.FolderID == folderID && fp.Source != excludeSource && fp.ContentHash == contentHash {
+			return fp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) FindByBands(_ context.Context, userID, folderID, excludeSource string, bands [4]uint16) ([]*models.RagChunkFingerprint, error) {
+	var found []*models.RagChunkFingerprint
+	for _, fp := range s.fingerprints {
+		if fp.UserID != userID || fp.FolderID != folderID || fp.Source == excludeSource || !fp.Near {
+			continue
+		}
+		if fp.Band0 == int(bands[0]) || fp.Band1 == int(bands[1]) || fp.Band2 == int(bands[2]) || fp.Band3 == int(bands[3]) {
+			found = append(found, fp)
+		}
+	}
+	return found, nil
+}
+
+func (s *memoryStore) SaveFingerprints(_ context.Context, fingerprints []*models.RagChunkFingerprint) error {
+	s.fingerprints = append(s.fingerprints, fingerprints...)
+	return nil
+}
+
+func (s *memoryStore) ForEachFingerprint(_ context.Context, fn func(*models.RagChunkFingerprint)) error {
+	for _, fp := range s.fingerprints {
+		fn(fp)
+	}
+	return nil
+}
+
+const paragraph = "The quarterly report summarizes revenue growth across all regions, highlights the increase in " +
+	"operating costs, and outlines the hiring plan for the next two quarters. Sales in the northern region grew by " +
+	"twelve percent, driven by the new product line, while the southern region stayed flat after the loss of two " +
+	"large customers. Marketing spend was reduced in favor of partnerships with regional distributors. The board " +
+	"approved the budget for the new warehouse, which is expected to open in the spring and to cut delivery times " +
+	"in half. Risks include rising material prices and delays in the certification of the new product line."
+
+// nearCopy is the paragraph with a word changed.
+var nearCopy = strings.Replace(paragraph, "in half.", "by half.", 1)
+
+func chunk(id, text string) *schema.Document {
+	return &schema.Document{ID: id, Text: text}
+}
+
+// checkAndRecord checks the chunks of a source and records the unique ones, as the indexing pipeline does.
+func checkAndRecord(t *testing.T, d *Deduplicator, userID, folderID, source string, chunks ...*schema.Document) *Result {
+	t.Helper()
+	result, err := d.Check(context.Background(), userID, folderID, source, chunks)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if err := d.Record(context.Background(), result); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	return result
+}
+
+func uniqueIDs(result *Result) []string {
+	ids := make([]string, len(result.Unique))
+	for i, doc := range result.Unique {
+		ids[i] = doc.ID
+	}
+	return ids
+}
+
+func TestCheck(t *testing.T) {
+	d, err := NewDeduplicator(context.Background(), &memoryStore{}, Config{MaxDistance: 3}, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewDeduplicator() error = %v", err)
+	}
+	checkAndRecord(t, d, "u1", "1", "a.pdf", chunk("a1", paragraph), chunk("a2", "Appendix: glossary of terms."))
+
+	// The same text under another name, once reformatted and once with a word changed.
+	result := checkAndRecord(t, d, "u1", "1", "copy.pdf",
+		chunk("b1", "  THE"+strings.Replace(paragraph[3:], " across", "\n across", 1)),
+		chunk("b2", "Appendix: glossary of terms."),
+		chunk("b3", nearCopy),
+		chunk("b4", "A completely different paragraph about the office relocation schedule and parking."),
+		chunk("b5", "A completely different paragraph about the office relocation schedule and parking."),
+	)
+	if got := uniqueIDs(result); !slices.Equal(got, []string{"b4"}) {
+		t.Errorf("Unique = %v, want [b4]", got)
+	}
+	if want := []string{"a1", "a2", "a1", "b4"}; !slices.Equal(result.DuplicateOf, want) {
+		t.Errorf("DuplicateOf = %v, want %v", result.DuplicateOf, want)
+	}
+
+	// Other folders and users, and the source itself when it is re-indexed, are not deduplicated against.
+	for _, scope := range []struct{ userID, folderID, source string }{{"u1", "2", "a.pdf"}, {"u2", "1", "a.pdf"}, {"u1", "1", "a.pdf"}} {
+		result, err := d.Check(context.Background(), scope.userID, scope.folderID, scope.source, []*schema.Document{chunk("c1", paragraph)})
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if len(result.DuplicateOf) != 0 {
+			t.Errorf("%+v: DuplicateOf = %v, want none", scope, result.DuplicateOf)
+		}
+	}
+}
+
+func TestCheckExactOnly(t *testing.T) {
+	d, err := NewDeduplicator(context.Background(), &memoryStore{}, Config{}, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewDeduplicator() error = %v", err)
+	}
+	checkAndRecord(t, d, "u1", "1", "a.pdf", chunk("a1", paragraph))
+	result := checkAndRecord(t, d, "u1", "1", "b.pdf", chunk("b1", paragraph), chunk("b2", nearCopy))
+	if got := uniqueIDs(result); !slices.Equal(got, []string{"b2"}) {
+		t.Errorf("Unique = %v, want [b2]", got)
+	}
+}
+
+func TestCheckShortStoredChunk(t *testing.T) {
+	// A stored chunk too short for near-duplicate detection, whose SimHash happens to equal the paragraph's.
+	long := newFingerprintedChunk("u1", "1", "a.pdf", chunk("a1", paragraph)).fingerprint
+	short := newFingerprintedChunk("u1", "1", "a.pdf", chunk("a1", "Appendix: glossary of terms.")).fingerprint
+	short.SimHash, short.Band0, short.Band1, short.Band2, short.Band3 = long.SimHash, long.Band0, long.Band1, long.Band2, long.Band3
+	if short.Near {
+		t.Fatal("short chunk is eligible for near-duplicate detection")
+	}
+
+	store := &memoryStore{fingerprints: []*models.RagChunkFingerprint{short}}
+	d, err := NewDeduplicator(context.Background(), store, Config{MaxDistance: 3}, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewDeduplicator() error = %v", err)
+	}
+	result := checkAndRecord(t, d, "u1", "1", "b.pdf", chunk("b1", paragraph))
+	if got := uniqueIDs(result); !slices.Equal(got, []string{"b1"}) {
+		t.Errorf("Unique = %v, want [b1]", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryStore{}
+	cfg := Config{MaxDistance: 3, SnapshotPath: filepath.Join(t.TempDir(), "dedup.sbf")}
+	d, err := NewDeduplicator(ctx, store, cfg, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewDeduplicator() error = %v", err)
+	}
+	checkAndRecord(t, d, "u1", "1", "a.pdf", chunk("a1", paragraph))
+
+	// A restarted deduplicator loads the filter from the snapshot instead of rebuilding it from the store.
+	reloaded, err := NewDeduplicator(ctx, &memoryStore{}, cfg, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewDeduplicator() error = %v", err)
+	}
+	fingerprint := store.fingerprints[0]
+	if !reloaded.filter.Test(hashKey("u1", "1", fingerprint.ContentHash)) {
+		t.Error("reloaded filter does not contain the recorded chunk")
+	}
+}
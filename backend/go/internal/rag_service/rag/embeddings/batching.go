@@ -0,0 +1,124 @@
+package embeddings
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"Jarvis_2.0/backend/go/pkg/ratelimiter"
+	"golang.org/x/sync/errgroup"
+)
+
+// limiterPollInterval is how often a call waiting for the rate limiter asks it again.
+const limiterPollInterval = 20 * time.Millisecond
+
+// BatchingConfig controls how a BatchingEmbedder calls the underlying model.
+type BatchingConfig struct {
+	BatchSize      int                     // Maximum number of texts per call; 0 sends all texts in one call
+	Concurrency    int                     // Maximum number of calls in flight; defaults to 1
+	Limiter        ratelimiter.RateLimiter // Optional; each call, including retries, takes one permit
+	MaxRetries     int                     // Retries of a call that failed with a retryable error
+	InitialBackoff time.Duration           // Wait before the first retry, doubled for each further retry
+	Retryable      func(error) bool        // Reports whether an error is worth retrying, e.g. embedding.IsRetryable
+}
+
+// BatchingEmbedder implements the EmbeddingModel interface on top of another model, splitting the texts into batches
+// the provider accepts, embedding the batches concurrently under a rate limit, and retrying calls that fail with
+// transient errors such as rate limiting or server errors.
+type BatchingEmbedder struct {
+	model interfaces.EmbeddingModel
+	cfg   BatchingConfig
+}
+
+// NewBatchingEmbedder creates a new BatchingEmbedder around model.
+func NewBatchingEmbedder(model interfaces.EmbeddingModel, cfg BatchingConfig) *BatchingEmbedder {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.Retryable == nil {
+		cfg.Retryable = func(error) bool { return false }
+	}
+	return &BatchingEmbedder{model: model, cfg: cfg}
+}
+
+// Embed embeds the texts, returning their vectors in the same order. It fails if any batch fails.
+func (e *BatchingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	batchSize := e.cfg.BatchSize
+	if batchSize <= 0 || batchSize > len(texts) {
+		batchSize = len(texts)
+	}
+	vectors := make([][]float32, len(texts))
+	if len(texts) == 0 {
+		return vectors, nil
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(e.cfg.Concurrency)
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		g.Go(func() error {
+			batch, err := e.embedBatch(gCtx, texts[start:end])
+			if err != nil {
+				return err
+			}
+			copy(vectors[start:end], batch)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
+
+// embedBatch embeds one batch, retrying it with exponential backoff while it fails with a retryable error.
+func (e *BatchingEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	backoff := e.cfg.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		if err := e.waitForPermit(ctx); err != nil {
+			return nil, err
+		}
+		vectors, err := e.model.Embed(ctx, texts)
+		if err == nil {
+			if len(vectors) != len(texts) {
+				return nil, fmt.Errorf("embedding model returned %d embeddings for %d texts", len(vectors), len(texts))
+			}
+			return vectors, nil
+		}
+		if attempt >= e.cfg.MaxRetries || !e.cfg.Retryable(err) {
+			return nil, err
+		}
+
+		// Jitter keeps concurrent batches that failed together from retrying in lockstep.
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// waitForPermit blocks until the rate limiter allows another call.
+func (e *BatchingEmbedder) waitForPermit(ctx context.Context) error {
+	if e.cfg.Limiter == nil {
+		return nil
+	}
+	for !e.cfg.Limiter.Allow() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(limiterPollInterval):
+		}
+	}
+	return nil
+}
+
+// compile-time check to ensure BatchingEmbedder implements the EmbeddingModel interface
+var _ interfaces.EmbeddingModel = (*BatchingEmbedder)(nil)
@@ -0,0 +1,148 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errRateLimited = errors.New("rate limited")
+
+// fakeModel embeds each text, a number, as a one-dimensional vector of that number. The first failures calls fail
+// with err.
+type fakeModel struct {
+	mu       sync.Mutex
+	calls    [][]string
+	failures int
+	err      error
+}
+
+func (m *fakeModel) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, texts)
+	if m.failures > 0 {
+		m.failures--
+		return nil, m.err
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, err
+		}
+		vectors[i] = []float32{float32(n)}
+	}
+	return vectors, nil
+}
+
+func numbers(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = strconv.Itoa(i)
+	}
+	return texts
+}
+
+// checkVectors checks that the vector of every text is its number.
+func checkVectors(t *testing.T, texts []string, vectors [][]float32) {
+	t.Helper()
+	if len(vectors) != len(texts) {
+		t.Fatalf("Embed() returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	for i, text := range texts {
+		if want, _ := strconv.Atoi(text); len(vectors[i]) != 1 || vectors[i][0] != float32(want) {
+			t.Errorf("vector %d = %v, want [%d]", i, vectors[i], want)
+		}
+	}
+}
+
+func TestBatchingEmbedderBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		texts     int
+		wantSizes []int
+	}{
+		{"uneven batches", 3, 8, []int{2, 3, 3}},
+		{"even batches", 4, 8, []int{4, 4}},
+		{"one batch", 0, 5, []int{5}},
+		{"batch larger than texts", 10, 5, []int{5}},
+		{"no texts", 3, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &fakeModel{}
+			texts := numbers(tt.texts)
+			vectors, err := NewBatchingEmbedder(model, BatchingConfig{BatchSize: tt.batchSize, Concurrency: 3}).Embed(context.Background(), texts)
+			if err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			checkVectors(t, texts, vectors)
+
+			var sizes []int
+			for _, call := range model.calls {
+				sizes = append(sizes, len(call))
+			}
+			slices.Sort(sizes)
+			if !slices.Equal(sizes, tt.wantSizes) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestBatchingEmbedderRetries(t *testing.T) {
+	permanent := errors.New("invalid input")
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   error
+	}{
+		{"success", 0, nil, 1, nil},
+		{"retryable error is retried", 2, errRateLimited, 3, nil},
+		{"retries are exhausted", 3, errRateLimited, 3, errRateLimited},
+		{"permanent error is not retried", 1, permanent, 1, permanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &fakeModel{failures: tt.failures, err: tt.err}
+			e := NewBatchingEmbedder(model, BatchingConfig{
+				MaxRetries:     2,
+				InitialBackoff: time.Millisecond,
+				Retryable:      func(err error) bool { return errors.Is(err, errRateLimited) },
+			})
+			texts := numbers(4)
+			vectors, err := e.Embed(context.Background(), texts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Embed() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				checkVectors(t, texts, vectors)
+			}
+			if len(model.calls) != tt.wantCalls {
+				t.Errorf("model called %d times, want %d", len(model.calls), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestBatchingEmbedderCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	model := &fakeModel{failures: 1, err: errRateLimited}
+	e := NewBatchingEmbedder(model, BatchingConfig{
+		MaxRetries:     2,
+		InitialBackoff: time.Hour,
+		Retryable:      func(err error) bool { return errors.Is(err, errRateLimited) },
+	})
+	if _, err := e.Embed(ctx, numbers(2)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Embed() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,89 @@
+package embeddings
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// CachedEmbedder implements the EmbeddingModel interface on top of another model, caching vectors by model name and
+// a hash of the text, so that text that was embedded before, such as the unchanged chunks of a re-indexed document,
+// is never sent to the model again. Cache failures are logged and fall back to the model.
+type CachedEmbedder struct {
+	model     interfaces.EmbeddingModel
+	cache     interfaces.EmbeddingCache
+	modelName string
+	log       logger.Logger
+}
+
+// NewCachedEmbedder creates a new CachedEmbedder. modelName must identify the model and its version, as vectors of
+// different models are not interchangeable.
+func NewCachedEmbedder(model interfaces.EmbeddingModel, cache interfaces.EmbeddingCache, modelName string, log logger.Logger) *CachedEmbedder {
+	return &CachedEmbedder{model: model, cache: cache, modelName: modelName, log: log}
+}
+
+// Embed returns cached vectors where available and embeds the remaining texts with the model.
+func (e *CachedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = e.cacheKey(text)
+	}
+
+	vectors, err := e.cache.Get(ctx, keys)
+	if err != nil || len(vectors) != len(texts) {
+		e.log.Warn(fmt.Sprintf("Embedding cache lookup failed, embedding all %d texts: %v", len(texts), err))
+		vectors = make([][]float32, len(texts))
+	}
+
+	// Embed each missing text once, even if it occurs several times.
+	var missing []string
+	missingIndex := make(map[string]int)
+	for i, vector := range vectors {
+		if vector != nil {
+			continue
+		}
+		if _, ok := missingIndex[keys[i]]; !ok {
+			missingIndex[keys[i]] = len(missing)
+			missing = append(missing, texts[i])
+		}
+	}
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := e.model.Embed(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missing) {
+		return nil, fmt.Errorf("embedding model returned %d embeddings for %d texts", len(embedded), len(missing))
+	}
+	newVectors := make(map[string][]float32, len(missing))
+	for i, vector := range vectors {
+		if vector == nil {
+			j := missingIndex[keys[i]]
+			vectors[i] = embedded[j]
+			newVectors[keys[i]] = embedded[j]
+		}
+	}
+	if err := e.cache.Set(ctx, newVectors); err != nil {
+		e.log.Warn(fmt.Sprintf("Failed to cache %d embeddings: %v", len(newVectors), err))
+	}
+	return vectors, nil
+}
+
+// cacheKey identifies the vector of a text by the model name and the SHA-256 of the text.
+func (e *CachedEmbedder) cacheKey(text string) string {
+	h := sha256.New()
+	h.Write([]byte(e.modelName))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// compile-time check to ensure CachedEmbedder implements the EmbeddingModel interface
+var _ interfaces.EmbeddingModel = (*CachedEmbedder)(nil)
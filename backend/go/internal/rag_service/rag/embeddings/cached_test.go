@@ -0,0 +1,112 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// memoryCache is an EmbeddingCache keeping vectors in a map. If err is set, Get and Set fail with it.
+type memoryCache struct {
+	vectors map[string][]float32
+	err     error
+}
+
+func (c *memoryCache) Get(_ context.Context, keys []string) ([][]float32, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	vectors := make([][]float32, len(keys))
+	for i, key := range keys {
+		vectors[i] = c.vectors[key]
+	}
+	return vectors, nil
+}
+
+func (c *memoryCache) Set(_ context.Context, vectors map[string][]float32) error {
+	if c.err != nil {
+		return c.err
+	}
+	for key, vector := range vectors {
+		c.vectors[key] = vector
+	}
+	return nil
+}
+
+func TestCachedEmbedder(t *testing.T) {
+	model := &fakeModel{}
+	cache := &memoryCache{vectors: make(map[string][]float32)}
+	e := NewCachedEmbedder(model, cache, "model-v1", *logger.New("test", "", ""))
+
+	texts := []string{"1", "2", "1"}
+	vectors, err := e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	checkVectors(t, texts, vectors)
+	if len(model.calls) != 1 || !slices.Equal(model.calls[0], []string{"1", "2"}) {
+		t.Errorf("model calls = %v, want [[1 2]]", model.calls)
+	}
+	if len(cache.vectors) != 2 {
+		t.Errorf("cached %d vectors, want 2", len(cache.vectors))
+	}
+
+	// Only the texts missing from the cache are embedded, each once.
+	texts = []string{"2", "3", "1", "3"}
+	vectors, err = e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	checkVectors(t, texts, vectors)
+	if len(model.calls) != 2 || !slices.Equal(model.calls[1], []string{"3"}) {
+		t.Errorf("model calls = %v, want [3] embedded last", model.calls)
+	}
+
+	// Fully cached texts are not sent to the model.
+	if _, err := e.Embed(context.Background(), []string{"3", "2"}); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(model.calls) != 2 {
+		t.Errorf("model called %d times, want 2", len(model.calls))
+	}
+
+	// Vectors of another model are not reused.
+	other := NewCachedEmbedder(model, cache, "model-v2", *logger.New("test", "", ""))
+	if _, err := other.Embed(context.Background(), []string{"1"}); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(model.calls) != 3 {
+		t.Errorf("model called %d times, want 3", len(model.calls))
+	}
+}
+
+func TestCachedEmbedderCacheFailure(t *testing.T) {
+	model := &fakeModel{}
+	cache := &memoryCache{err: errors.New("cache unavailable")}
+	e := NewCachedEmbedder(model, cache, "model-v1", *logger.New("test", "", ""))
+
+	texts := []string{"1", "2", "2"}
+	vectors, err := e.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	checkVectors(t, texts, vectors)
+	if len(model.calls) != 1 || !slices.Equal(model.calls[0], []string{"1", "2"}) {
+		t.Errorf("model calls = %v, want [[1 2]]", model.calls)
+	}
+}
+
+func TestCachedEmbedderModelError(t *testing.T) {
+	model := &fakeModel{failures: 1, err: errRateLimited}
+	cache := &memoryCache{vectors: make(map[string][]float32)}
+	e := NewCachedEmbedder(model, cache, "model-v1", *logger.New("test", "", ""))
+	if _, err := e.Embed(context.Background(), []string{"1"}); !errors.Is(err, errRateLimited) {
+		t.Fatalf("Embed() error = %v, want %v", err, errRateLimited)
+	}
+	if len(cache.vectors) != 0 {
+		t.Errorf("cached %d vectors after a failed call, want none", len(cache.vectors))
+	}
+}
@@ -0,0 +1,117 @@
+package evaluation
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTopK is the number of chunks retrieved per question when the dataset does not set top_k.
+const DefaultTopK = 10
+
+// Dataset is a golden set of questions about the documents of one user's folders.
+type Dataset struct {
+	Name      string   `yaml:"name"`
+	UserID    string   `yaml:"user_id"`
+	FolderIDs []string `yaml:"folder_ids"` // Folders searched for every question; empty searches all folders
+	TopK      int      `yaml:"top_k"`
+	// Expansion is the query expansion to retrieve with: "none" (the default), "multi_query" or "hyde".
+	Expansion string `yaml:"expansion"`
+	// VectorWeight and KeywordWeight set the hybrid fusion weights; both zero uses equal weights.
+	VectorWeight  float64    `yaml:"vector_weight"`
+	KeywordWeight float64    `yaml:"keyword_weight"`
+	Questions     []Question `yaml:"questions"`
+}
+
+// Question is a question with the sources that should be retrieved for it and/or a reference answer.
+type Question struct {
+	ID       string `yaml:"id"`
+	Question string `yaml:"question"`
+	// FolderIDs overrides the dataset's folders for this question.
+	FolderIDs []string `yaml:"folder_ids"`
+	// ExpectedSources are the documents that answer the question, given as the file name or URL of their chunks.
+	// A path matches the file of the same name.
+	ExpectedSources []string `yaml:"expected_sources"`
+	ReferenceAnswer string   `yaml:"reference_answer"`
+}
+
+// LoadDataset reads and validates a YAML dataset.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	var dataset Dataset
+	if err := yaml.Unmarshal(data, &dataset); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset: %w", err)
+	}
+	if err := dataset.validate(); err != nil {
+		return nil, fmt.Errorf("invalid dataset %s: %w", path, err)
+	}
+	return &dataset, nil
+}
+
+// validate checks the dataset and fills in defaults.
+func (d *Dataset) validate() error {
+	if d.UserID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if d.TopK < 0 {
+		return fmt.Errorf("top_k must not be negative")
+	}
+	if d.TopK == 0 {
+		d.TopK = DefaultTopK
+	}
+	if _, err := d.queryExpansion(); err != nil {
+		return err
+	}
+	if d.VectorWeight < 0 || d.KeywordWeight < 0 {
+		return fmt.Errorf("vector_weight and keyword_weight must not be negative")
+	}
+	if len(d.Questions) == 0 {
+		return fmt.Errorf("no questions")
+	}
+
+	seen := make(map[string]bool, len(d.Questions))
+	for i := range d.Questions {
+		q := &d.Questions[i]
+		if q.ID == "" {
+			q.ID = fmt.Sprintf("q%d", i+1)
+		}
+		if seen[q.ID] {
+			return fmt.Errorf("duplicate question id %q", q.ID)
+		}
+		seen[q.ID] = true
+		if q.Question == "" {
+			return fmt.Errorf("question %q has no text", q.ID)
+		}
+		if len(q.ExpectedSources) == 0 && q.ReferenceAnswer == "" {
+			return fmt.Errorf("question %q needs expected_sources or a reference_answer", q.ID)
+		}
+	}
+	return nil
+}
+
+// queryExpansion returns the pipeline setting for the dataset's expansion.
+func (d *Dataset) queryExpansion() (pipeline.QueryExpansion, error) {
+	switch d.Expansion {
+	case "", "none":
+		return pipeline.ExpansionNone, nil
+	case "multi_query":
+		return pipeline.ExpansionMultiQuery, nil
+	case "hyde":
+		return pipeline.ExpansionHyDE, nil
+	default:
+		return 0, fmt.Errorf("unknown expansion %q, must be none, multi_query or hyde", d.Expansion)
+	}
+}
+
+// hybridWeights returns the fusion weights of the dataset, defaulting to equal weights when neither is set.
+func (d *Dataset) hybridWeights() pipeline.HybridWeights {
+	if d.VectorWeight == 0 && d.KeywordWeight == 0 {
+		return pipeline.DefaultHybridWeights
+	}
+	return pipeline.HybridWeights{Vector: d.VectorWeight, Keyword: d.KeywordWeight}
+}
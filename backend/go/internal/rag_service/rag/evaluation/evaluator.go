@@ -0,0 +1,115 @@
+package evaluation
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"fmt"
+	"time"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// defaultQueryVariants is the number of paraphrases retrieved with for the multi_query expansion, as in the service.
+const defaultQueryVariants = 3
+
+// Evaluator runs a dataset through the retrieval and QA pipelines and scores the results.
+type Evaluator struct {
+	retrieval *pipeline.RetrievalPipeline
+	qa        *pipeline.QAPipeline // Optional; nil skips answer generation and judging
+	judge     *Judge               // Optional; nil skips judging
+	log       logger.Logger
+}
+
+// NewEvaluator creates a new Evaluator. The QA pipeline and the judge can be nil to evaluate retrieval only.
+func NewEvaluator(retrieval *pipeline.RetrievalPipeline, qa *pipeline.QAPipeline, judge *Judge, log logger.Logger) *Evaluator {
+	return &Evaluator{retrieval: retrieval, qa: qa, judge: judge, log: log}
+}
+
+// Run evaluates every question of the dataset. A question that fails is recorded with its error and does not stop
+// the run; only cancellation of ctx does.
+func (e *Evaluator) Run(ctx context.Context, dataset *Dataset) (*Report, error) {
+	report := &Report{Dataset: dataset.Name, RunAt: time.Now().UTC(), TopK: dataset.TopK}
+	for i := range dataset.Questions {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		q := &dataset.Questions[i]
+		e.log.Info(fmt.Sprintf("Evaluating question %d/%d: %s", i+1, len(dataset.Questions), q.ID))
+		result := e.evaluate(ctx, dataset, q)
+		if result.Error != "" {
+			e.log.Warn(fmt.Sprintf("Question %s failed: %s", q.ID, result.Error))
+		}
+		report.Questions = append(report.Questions, *result)
+	}
+	report.summarize()
+	return report, nil
+}
+
+// evaluate retrieves for a single question, scores retrieval against its expected sources, and generates and
+// judges an answer if a QA pipeline is configured.
+func (e *Evaluator) evaluate(ctx context.Context, dataset *Dataset, q *Question) *QuestionResult {
+	result := &QuestionResult{ID: q.ID, Question: q.Question, RetrievedSources: []string{}}
+
+	folderIDs := q.FolderIDs
+	if len(folderIDs) == 0 {
+		folderIDs = dataset.FolderIDs
+	}
+	expansion, _ := dataset.queryExpansion() // validated when the dataset was loaded
+	retrieved, err := e.retrieval.Run(ctx, pipeline.RetrievalRequest{
+		Query:       q.Question,
+		UserID:      dataset.UserID,
+		FolderIDs:   folderIDs,
+		TopK:        dataset.TopK,
+		Weights:     dataset.hybridWeights(),
+		Expansion:   expansion,
+		NumVariants: defaultQueryVariants,
+	})
+	if err != nil {
+		result.Error = fmt.Sprintf("retrieval failed: %v", err)
+		return result
+	}
+	result.RetrievedSources = RankedSources(retrieved.Documents)
+	if len(q.ExpectedSources) > 0 {
+		metrics := ScoreRetrieval(retrieved.Documents, q.ExpectedSources, dataset.TopK)
+		result.Retrieval = &metrics
+	}
+
+	if e.qa == nil {
+		return result
+	}
+	answer, err := e.qa.Run(ctx, retrieved.StandaloneQuery, retrieved.Documents)
+	if err != nil {
+		result.Error = fmt.Sprintf("answer generation failed: %v", err)
+		return result
+	}
+	result.Answer = answer.Text
+
+	if e.judge == nil {
+		return result
+	}
+	if err := e.judgeAnswer(ctx, q, answer.Text, retrieved.Documents, result); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// judgeAnswer grades the answer's faithfulness to the retrieved documents and, if the question has a reference
+// answer, its correctness.
+func (e *Evaluator) judgeAnswer(ctx context.Context, q *Question, answer string, docs []*schema.Document, result *QuestionResult) error {
+	faithfulness, err := e.judge.Faithfulness(ctx, q.Question, answer, docs)
+	if err != nil {
+		return fmt.Errorf("faithfulness judging failed: %w", err)
+	}
+	result.Faithfulness = faithfulness
+
+	if q.ReferenceAnswer == "" {
+		return nil
+	}
+	correctness, err := e.judge.Correctness(ctx, q.Question, answer, q.ReferenceAnswer)
+	if err != nil {
+		return fmt.Errorf("correctness judging failed: %w", err)
+	}
+	result.Correctness = correctness
+	return nil
+}
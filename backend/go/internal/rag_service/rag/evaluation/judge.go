@@ -0,0 +1,104 @@
+package evaluation
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// faithfulnessPrompt asks the model whether every claim of the answer is supported by the context.
+const faithfulnessPrompt = `You are grading the faithfulness of an answer produced by a retrieval-augmented assistant.
+An answer is faithful if every claim it makes is supported by the context below. Claims that are correct but not
+found in the context are unfaithful. An answer that says the context does not contain the information is faithful
+if that is true.
+
+Context:
+%s
+Question: %s
+
+Answer: %s
+
+Rate the faithfulness from 0 (mostly unsupported) to 10 (fully supported).
+Respond with only a JSON object like {"score": 7, "reason": "one sentence"}.`
+
+// correctnessPrompt asks the model whether the answer agrees with the reference answer.
+const correctnessPrompt = `You are grading an answer against a reference answer.
+The answer is correct if it conveys the facts of the reference answer without contradicting it; wording may differ
+and extra correct detail is fine.
+
+Question: %s
+
+Reference answer: %s
+
+Answer: %s
+
+Rate the correctness from 0 (wrong or missing) to 10 (fully correct).
+Respond with only a JSON object like {"score": 7, "reason": "one sentence"}.`
+
+// maxJudgedContextChars bounds the length of each context chunk shown to the judge to keep the prompt small.
+const maxJudgedContextChars = 2000
+
+// Judgement is a judge's score, normalised to [0, 1], with its explanation.
+type Judgement struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// Judge uses an LLM to grade generated answers.
+type Judge struct {
+	llm interfaces.LLM
+}
+
+// NewJudge creates a new Judge.
+func NewJudge(llm interfaces.LLM) *Judge {
+	return &Judge{llm: llm}
+}
+
+// Faithfulness grades how well the answer is supported by the context documents it was generated from.
+func (j *Judge) Faithfulness(ctx context.Context, question, answer string, docs []*schema.Document) (*Judgement, error) {
+	var contextText strings.Builder
+	for i, doc := range docs {
+		text := doc.Text
+		if len(text) > maxJudgedContextChars {
+			text = strings.ToValidUTF8(text[:maxJudgedContextChars], "")
+		}
+		fmt.Fprintf(&contextText, "[%d] %s\n\n", i+1, text)
+	}
+	return j.judge(ctx, fmt.Sprintf(faithfulnessPrompt, contextText.String(), question, answer))
+}
+
+// Correctness grades how well the answer agrees with the reference answer.
+func (j *Judge) Correctness(ctx context.Context, question, answer, reference string) (*Judgement, error) {
+	return j.judge(ctx, fmt.Sprintf(correctnessPrompt, question, reference, answer))
+}
+
+func (j *Judge) judge(ctx context.Context, prompt string) (*Judgement, error) {
+	answer, err := j.llm.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("llm judge failed: %w", err)
+	}
+	return parseJudgement(answer)
+}
+
+// parseJudgement extracts the JSON object from the model's answer, tolerating surrounding text such as markdown
+// code fences, and normalises its 0-10 score to [0, 1].
+func parseJudgement(answer string) (*Judgement, error) {
+	start := strings.Index(answer, "{")
+	end := strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("llm judge returned no judgement: %q", answer)
+	}
+
+	var judgement Judgement
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &judgement); err != nil {
+		return nil, fmt.Errorf("failed to parse llm judgement: %w", err)
+	}
+	if judgement.Score < 0 || judgement.Score > 10 {
+		return nil, fmt.Errorf("llm judge returned score %v outside 0-10", judgement.Score)
+	}
+	judgement.Score /= 10
+	return &judgement, nil
+}
@@ -0,0 +1,95 @@
+package evaluation
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// RetrievalMetrics measures how well the retrieved chunks cover a question's expected sources.
+// Chunks are ranked at document level: each source counts once, at the rank of its best chunk.
+type RetrievalMetrics struct {
+	Recall float64 `json:"recall"` // Fraction of the expected sources among the top k chunks
+	MRR    float64 `json:"mrr"`    // Reciprocal rank of the first expected source, 0 if none was retrieved
+	NDCG   float64 `json:"ndcg"`   // Normalised discounted cumulative gain with binary relevance
+}
+
+// ScoreRetrieval computes the retrieval metrics of the ranked documents against the expected sources.
+// Only the first k documents are considered; k <= 0 considers all of them.
+func ScoreRetrieval(docs []*schema.Document, expected []string, k int) RetrievalMetrics {
+	relevant := make(map[string]bool, len(expected))
+	for _, source := range expected {
+		relevant[normalizeSource(source)] = true
+	}
+	if len(relevant) == 0 {
+		return RetrievalMetrics{}
+	}
+	if k <= 0 {
+		k = len(docs)
+	}
+	if len(docs) > k {
+		docs = docs[:k]
+	}
+
+	var metrics RetrievalMetrics
+	var dcg float64
+	found := 0
+	for i, source := range RankedSources(docs) {
+		if !relevant[source] {
+			continue
+		}
+		found++
+		rank := float64(i + 1)
+		if metrics.MRR == 0 {
+			metrics.MRR = 1 / rank
+		}
+		dcg += 1 / math.Log2(rank+1)
+	}
+
+	// The ideal ranking puts every expected source first.
+	var idcg float64
+	for i := 1; i <= min(len(relevant), k); i++ {
+		idcg += 1 / math.Log2(float64(i)+1)
+	}
+	metrics.Recall = float64(found) / float64(len(relevant))
+	if idcg > 0 {
+		metrics.NDCG = dcg / idcg
+	}
+	return metrics
+}
+
+// RankedSources returns the distinct sources of the documents in order of their first chunk.
+func RankedSources(docs []*schema.Document) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		source := Source(doc)
+		if source == "" || seen[source] {
+			continue
+		}
+		seen[source] = true
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// Source identifies the document a chunk was retrieved from: the URL of a web page or the name of a file.
+func Source(doc *schema.Document) string {
+	if url, ok := doc.Metadata[schema.MetadataKeySourceURL]; ok && url != nil {
+		return fmt.Sprintf("%v", url)
+	}
+	if fileName, ok := doc.Metadata[schema.MetadataKeyFileName]; ok && fileName != nil {
+		return fmt.Sprintf("%v", fileName)
+	}
+	return ""
+}
+
+// normalizeSource reduces an expected source to the form chunks record: URLs are kept, paths become file names.
+func normalizeSource(source string) string {
+	if strings.Contains(source, "://") {
+		return source
+	}
+	return filepath.Base(source)
+}
@@ -0,0 +1,101 @@
+package evaluation
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"math"
+	"testing"
+)
+
+func chunksFrom(fileNames ...string) []*schema.Document {
+	docs := make([]*schema.Document, len(fileNames))
+	for i, name := range fileNames {
+		docs[i] = &schema.Document{Metadata: map[string]interface{}{schema.MetadataKeyFileName: name}}
+	}
+	return docs
+}
+
+func TestScoreRetrieval(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     []*schema.Document
+		expected []string
+		k        int
+		want     RetrievalMetrics
+	}{
+		{
+			name:     "perfect ranking",
+			docs:     chunksFrom("a.pdf", "a.pdf", "b.md", "c.txt"),
+			expected: []string{"a.pdf", "b.md"},
+			k:        10,
+			want:     RetrievalMetrics{Recall: 1, MRR: 1, NDCG: 1},
+		},
+		{
+			// Sources rank by their first chunk: c.txt is 1st, a.pdf 2nd.
+			name:     "relevant source second",
+			docs:     chunksFrom("c.txt", "a.pdf"),
+			expected: []string{"docs/a.pdf", "b.md"},
+			k:        10,
+			want:     RetrievalMetrics{Recall: 0.5, MRR: 0.5, NDCG: (1 / math.Log2(3)) / (1 + 1/math.Log2(3))},
+		},
+		{
+			name:     "beyond k",
+			docs:     chunksFrom("c.txt", "c.txt", "a.pdf"),
+			expected: []string{"a.pdf"},
+			k:        2,
+			want:     RetrievalMetrics{},
+		},
+		{
+			name:     "web page",
+			docs:     []*schema.Document{{Metadata: map[string]interface{}{schema.MetadataKeySourceURL: "https://example.com/docs/intro"}}},
+			expected: []string{"https://example.com/docs/intro"},
+			k:        10,
+			want:     RetrievalMetrics{Recall: 1, MRR: 1, NDCG: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScoreRetrieval(tt.docs, tt.expected, tt.k)
+			if math.Abs(got.Recall-tt.want.Recall) > 1e-9 || math.Abs(got.MRR-tt.want.MRR) > 1e-9 || math.Abs(got.NDCG-tt.want.NDCG) > 1e-9 {
+				t.Errorf("ScoreRetrieval() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJudgement(t *testing.T) {
+	judgement, err := parseJudgement("```json\n{\"score\": 8, \"reason\": \"mostly supported\"}\n```")
+	if err != nil {
+		t.Fatalf("parseJudgement() error = %v", err)
+	}
+	if judgement.Score != 0.8 || judgement.Reason != "mostly supported" {
+		t.Errorf("parseJudgement() = %+v", judgement)
+	}
+	if _, err := parseJudgement(`{"score": 11}`); err == nil {
+		t.Error("parseJudgement() accepted a score above 10")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	previous := &Report{Questions: []QuestionResult{
+		{ID: "q1", Retrieval: &RetrievalMetrics{Recall: 1, MRR: 1, NDCG: 1}},
+		{ID: "q2", Retrieval: &RetrievalMetrics{Recall: 0, MRR: 0, NDCG: 0}, Faithfulness: &Judgement{Score: 0.5}},
+	}}
+	current := &Report{Questions: []QuestionResult{
+		{ID: "q1", Retrieval: &RetrievalMetrics{Recall: 1, MRR: 0.5, NDCG: 0.97}},
+		{ID: "q2", Retrieval: &RetrievalMetrics{Recall: 0, MRR: 0, NDCG: 0}, Faithfulness: &Judgement{Score: 0.9}},
+		{ID: "q3", Retrieval: &RetrievalMetrics{Recall: 1, MRR: 1, NDCG: 1}},
+	}}
+	previous.summarize()
+	current.summarize()
+
+	comparison := Compare(previous, current, 0.05)
+	if len(comparison.Regressions) != 1 || comparison.Regressions[0].ID != "q1" || comparison.Regressions[0].Metric != "mrr" {
+		t.Errorf("Regressions = %+v, want only q1 mrr", comparison.Regressions)
+	}
+	if len(comparison.Improvements) != 1 || comparison.Improvements[0].Metric != "faithfulness" {
+		t.Errorf("Improvements = %+v, want only q2 faithfulness", comparison.Improvements)
+	}
+	if recall := comparison.Summary[0]; *recall.Previous != 0.5 || *recall.Current != 2.0/3 {
+		t.Errorf("recall delta = %v -> %v, want 0.5 -> 0.667", *recall.Previous, *recall.Current)
+	}
+}
@@ -0,0 +1,225 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Report is the result of evaluating a dataset, written as JSON so later runs can be compared against it.
+type Report struct {
+	Dataset   string           `json:"dataset"`
+	RunAt     time.Time        `json:"run_at"`
+	TopK      int              `json:"top_k"`
+	Summary   Summary          `json:"summary"`
+	Questions []QuestionResult `json:"questions"`
+}
+
+// QuestionResult is the outcome of a single question. Metrics that do not apply to the question are omitted.
+type QuestionResult struct {
+	ID               string            `json:"id"`
+	Question         string            `json:"question"`
+	RetrievedSources []string          `json:"retrieved_sources"`
+	Retrieval        *RetrievalMetrics `json:"retrieval,omitempty"` // Set if the question has expected sources
+	Answer           string            `json:"answer,omitempty"`
+	Faithfulness     *Judgement        `json:"faithfulness,omitempty"`
+	Correctness      *Judgement        `json:"correctness,omitempty"` // Set if the question has a reference answer
+	Error            string            `json:"error,omitempty"`
+}
+
+// Summary holds the mean of every metric over the questions it was measured for.
+type Summary struct {
+	Questions    int      `json:"questions"`
+	Failed       int      `json:"failed"`
+	Recall       *float64 `json:"recall,omitempty"`
+	MRR          *float64 `json:"mrr,omitempty"`
+	NDCG         *float64 `json:"ndcg,omitempty"`
+	Faithfulness *float64 `json:"faithfulness,omitempty"`
+	Correctness  *float64 `json:"correctness,omitempty"`
+}
+
+// Metric is a named per-question measurement, in [0, 1] with higher being better.
+type Metric struct {
+	Name  string
+	value func(*QuestionResult) (float64, bool)
+}
+
+// Metrics lists the metrics a report summarises, in display order.
+var Metrics = []Metric{
+	{"recall", func(q *QuestionResult) (float64, bool) {
+		return retrievalValue(q, func(m *RetrievalMetrics) float64 { return m.Recall })
+	}},
+	{"mrr", func(q *QuestionResult) (float64, bool) {
+		return retrievalValue(q, func(m *RetrievalMetrics) float64 { return m.MRR })
+	}},
+	{"ndcg", func(q *QuestionResult) (float64, bool) {
+		return retrievalValue(q, func(m *RetrievalMetrics) float64 { return m.NDCG })
+	}},
+	{"faithfulness", func(q *QuestionResult) (float64, bool) { return judgementValue(q.Faithfulness) }},
+	{"correctness", func(q *QuestionResult) (float64, bool) { return judgementValue(q.Correctness) }},
+}
+
+// Value returns the metric of the question, or nil if it was not measured.
+func (m Metric) Value(q *QuestionResult) *float64 {
+	v, ok := m.value(q)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func retrievalValue(q *QuestionResult, get func(*RetrievalMetrics) float64) (float64, bool) {
+	if q.Retrieval == nil {
+		return 0, false
+	}
+	return get(q.Retrieval), true
+}
+
+func judgementValue(j *Judgement) (float64, bool) {
+	if j == nil {
+		return 0, false
+	}
+	return j.Score, true
+}
+
+// Value returns the summary value of the metric, or nil if no question measured it.
+func (s *Summary) Value(name string) *float64 {
+	switch name {
+	case "recall":
+		return s.Recall
+	case "mrr":
+		return s.MRR
+	case "ndcg":
+		return s.NDCG
+	case "faithfulness":
+		return s.Faithfulness
+	case "correctness":
+		return s.Correctness
+	default:
+		return nil
+	}
+}
+
+// summarize computes the summary of the report's questions.
+func (r *Report) summarize() {
+	r.Summary = Summary{Questions: len(r.Questions)}
+	for _, q := range r.Questions {
+		if q.Error != "" {
+			r.Summary.Failed++
+		}
+	}
+	means := make(map[string]*float64, len(Metrics))
+	for _, metric := range Metrics {
+		var sum float64
+		var n int
+		for i := range r.Questions {
+			if v, ok := metric.value(&r.Questions[i]); ok {
+				sum += v
+				n++
+			}
+		}
+		if n > 0 {
+			mean := sum / float64(n)
+			means[metric.Name] = &mean
+		}
+	}
+	r.Summary.Recall = means["recall"]
+	r.Summary.MRR = means["mrr"]
+	r.Summary.NDCG = means["ndcg"]
+	r.Summary.Faithfulness = means["faithfulness"]
+	r.Summary.Correctness = means["correctness"]
+}
+
+// WriteReport writes the report as indented JSON, creating the directory if needed.
+func WriteReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads a report written by WriteReport.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// MetricDelta is the change of a summary metric between two runs. A nil value was not measured in that run.
+type MetricDelta struct {
+	Metric   string
+	Previous *float64
+	Current  *float64
+}
+
+// QuestionDelta is a change of a question's metric between two runs.
+type QuestionDelta struct {
+	ID       string
+	Metric   string
+	Previous float64
+	Current  float64
+}
+
+// Comparison is the difference between a previous and a current run of a dataset.
+type Comparison struct {
+	Summary []MetricDelta
+	// Regressions and Improvements are the per-question changes larger than the comparison's threshold,
+	// for questions present in both runs.
+	Regressions  []QuestionDelta
+	Improvements []QuestionDelta
+}
+
+// Compare diffs the current report against a previous one. Per-question changes of at most threshold are ignored,
+// which keeps the noise of LLM-judged metrics out of the diff.
+func Compare(previous, current *Report, threshold float64) *Comparison {
+	comparison := &Comparison{}
+	for _, metric := range Metrics {
+		comparison.Summary = append(comparison.Summary, MetricDelta{
+			Metric:   metric.Name,
+			Previous: previous.Summary.Value(metric.Name),
+			Current:  current.Summary.Value(metric.Name),
+		})
+	}
+
+	previousByID := make(map[string]*QuestionResult, len(previous.Questions))
+	for i := range previous.Questions {
+		previousByID[previous.Questions[i].ID] = &previous.Questions[i]
+	}
+	for i := range current.Questions {
+		cur := &current.Questions[i]
+		prev, ok := previousByID[cur.ID]
+		if !ok {
+			continue
+		}
+		for _, metric := range Metrics {
+			prevValue, okPrev := metric.value(prev)
+			curValue, okCur := metric.value(cur)
+			if !okPrev || !okCur || math.Abs(curValue-prevValue) <= threshold {
+				continue
+			}
+			delta := QuestionDelta{ID: cur.ID, Metric: metric.Name, Previous: prevValue, Current: curValue}
+			if curValue < prevValue {
+				comparison.Regressions = append(comparison.Regressions, delta)
+			} else {
+				comparison.Improvements = append(comparison.Improvements, delta)
+			}
+		}
+	}
+	return comparison
+}
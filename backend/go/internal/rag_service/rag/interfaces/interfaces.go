@@ -3,6 +3,8 @@ package interfaces
 import (
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
 	"context"
+
+	"Jarvis_2.0/backend/go/internal/models"
 )
 
 // Loader is the interface for loading data from a source (e.g., file, URL)
@@ -26,7 +28,17 @@ type DocStore interface {
 // VectorStore is the interface for storing and querying document vectors.
 type VectorStore interface {
 	Add(ctx context.Context, docs []*schema.Document) error // Add is multi-tenant via metadata in docs
-	Query(ctx context.Context, embedding []float32, topK int, filters map[string]interface{}) ([]*schema.Document, error)
+	Query(ctx context.Context, embedding []float32, topK int, filters []schema.Filter) ([]*schema.Document, error)
+	Delete(ctx context.Context, ids []string) error
+}
+
+// KeywordIndex is the interface for a lexical index over chunk text, used alongside the VectorStore for hybrid retrieval.
+type KeywordIndex interface {
+	Add(ctx context.Context, userID, folderID string, docs []*schema.Document) error
+	Delete(ctx context.Context, userID string, ids []string) error
+	// Search returns the user's best matching chunks in descending order of relevance. An empty folderIDs searches all folders.
+	// Only chunks matching all filters are returned.
+	Search(ctx context.Context, userID string, folderIDs []string, query string, topK int, filters []schema.Filter) ([]*schema.Document, error)
 }
 
 // Reranker is the interface for re-ordering a list of retrieved documents to improve relevance.
@@ -39,7 +51,37 @@ type EmbeddingModel interface {
 	Embed(ctx context.Context, texts []string) ([][]float32, error)
 }
 
+// EmbeddingCache stores embedding vectors by a key derived from the model and the embedded text.
+type EmbeddingCache interface {
+	// Get returns the vectors for the keys, in the same order, with nil for keys that are not cached.
+	Get(ctx context.Context, keys []string) ([][]float32, error)
+	Set(ctx context.Context, vectors map[string][]float32) error
+}
+
+// KnowledgeGraph is the interface for a per-user graph of entities and their relations, such as the one the memory
+// service builds in Neo4j.
+type KnowledgeGraph interface {
+	// GetRelatedRelations returns at most limit relations on paths of up to maxHops relations from the named
+	// entities, closest first.
+	GetRelatedRelations(ctx context.Context, userID string, entities []string, maxHops, limit int) ([]*models.Relation, error)
+}
+
+// ObjectStore is the interface for storing binary originals, such as images extracted from documents, that chunks
+// refer to.
+type ObjectStore interface {
+	// Put stores data under key, replacing any previous object, and returns the URI it can be fetched from.
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+}
+
 // LLM is the interface for a large language model that can generate text.
 type LLM interface {
 	Generate(ctx context.Context, prompt string) (string, error)
 }
+
+// StreamingLLM is an LLM that can deliver its output incrementally.
+type StreamingLLM interface {
+	LLM
+	// GenerateStream calls onToken with each piece of text as it is generated and returns the full text.
+	// Generation stops with onToken's error if it returns one.
+	GenerateStream(ctx context.Context, prompt string, onToken func(token string) error) (string, error)
+}
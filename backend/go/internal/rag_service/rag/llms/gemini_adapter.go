@@ -4,6 +4,7 @@ import (
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
 	"context"
 	"fmt"
+	"strings"
 
 	"Jarvis_2.0/backend/go/internal/llm"
 	"Jarvis_2.0/backend/go/internal/models"
@@ -11,11 +12,12 @@ import (
 
 // GeminiAdapter adapts the existing project-specific Gemini client to the generic LLM interface.
 type GeminiAdapter struct {
-	client *llm.Gemini
+	client llm.LLM
 }
 
-// NewGeminiAdapter creates a new adapter.
-func NewGeminiAdapter(client *llm.Gemini) *GeminiAdapter {
+// NewGeminiAdapter creates a new adapter. Prompts of different users must not share a chat history, so pass a
+// stateless client such as the one returned by llm.Gemini.Stateless.
+func NewGeminiAdapter(client llm.LLM) *GeminiAdapter {
 	return &GeminiAdapter{client: client}
 }
 
@@ -49,5 +51,59 @@ func (a *GeminiAdapter) Generate(ctx context.Context, prompt string) (string, er
 	return "", fmt.Errorf("gemini response was empty or in an unexpected format")
 }
 
-// compile-time check to ensure GeminiAdapter implements the LLM interface
-var _ interfaces.LLM = (*GeminiAdapter)(nil)
+// GenerateStream streams the answer from the existing Gemini client, calling onToken for every text part received.
+// A stream the client ends with an error returns that error with the text received so far, so callers report the
+// failure instead of finishing with a truncated answer.
+func (a *GeminiAdapter) GenerateStream(ctx context.Context, prompt string, onToken func(token string) error) (string, error) {
+	req := &models.GenerateContentRequest{
+		Content: []models.Content{
+			{
+				Parts: []*models.Part{
+					{Text: prompt},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := a.client.GenerateContentStream(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("gemini client failed to start streaming: %w", err)
+	}
+	// The client's goroutine blocks until its channel is drained, so drain it if we stop early.
+	defer func() {
+		go func() {
+			for range stream {
+			}
+		}()
+	}()
+
+	var sb strings.Builder
+	for resp := range stream {
+		if resp.Err != nil {
+			return sb.String(), fmt.Errorf("gemini stream failed: %w", resp.Err)
+		}
+		for _, content := range resp.Content {
+			for _, part := range content.Parts {
+				if part == nil || part.Text == "" {
+					continue
+				}
+				sb.WriteString(part.Text)
+				if err := onToken(part.Text); err != nil {
+					return sb.String(), err
+				}
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return sb.String(), err
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("gemini stream was empty")
+	}
+	return sb.String(), nil
+}
+
+// compile-time check to ensure GeminiAdapter implements the StreamingLLM interface
+var _ interfaces.StreamingLLM = (*GeminiAdapter)(nil)
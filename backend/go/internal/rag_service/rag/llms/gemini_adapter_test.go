@@ -0,0 +1,69 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"Jarvis_2.0/backend/go/internal/models"
+)
+
+// fakeStreamLLM streams the given responses.
+type fakeStreamLLM struct {
+	responses []*models.GenerateContentResponse
+}
+
+func (f *fakeStreamLLM) GenerateContent(context.Context, *models.GenerateContentRequest) (*models.GenerateContentResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeStreamLLM) GenerateContentStream(context.Context, *models.GenerateContentRequest) (<-chan *models.GenerateContentResponse, error) {
+	ch := make(chan *models.GenerateContentResponse)
+	go func() {
+		defer close(ch)
+		for _, resp := range f.responses {
+			ch <- resp
+		}
+	}()
+	return ch, nil
+}
+
+func textResponse(text string) *models.GenerateContentResponse {
+	return &models.GenerateContentResponse{Content: []models.Content{{Parts: []*models.Part{{Text: text}}}}}
+}
+
+func TestGenerateStream(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		responses []*models.GenerateContentResponse
+		want      string
+		wantErr   error
+	}{
+		{"complete", []*models.GenerateContentResponse{textResponse("Hello, "), textResponse("world")}, "Hello, world", nil},
+		{"interrupted", []*models.GenerateContentResponse{textResponse("Hello, "), {Err: streamErr}}, "Hello, ", streamErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens []string
+			got, err := NewGeminiAdapter(&fakeStreamLLM{responses: tt.responses}).GenerateStream(context.Background(), "prompt", func(token string) error {
+				tokens = append(tokens, token)
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GenerateStream() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GenerateStream() = %q, want %q", got, tt.want)
+			}
+			if strings.Join(tokens, "") != tt.want {
+				t.Errorf("onToken called with %q, want the parts of %q", tokens, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewGeminiAdapter(&fakeStreamLLM{}).GenerateStream(context.Background(), "prompt", func(string) error { return nil }); err == nil {
+		t.Error("GenerateStream() of an empty stream succeeded")
+	}
+}
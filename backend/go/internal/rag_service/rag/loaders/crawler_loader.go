@@ -0,0 +1,321 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pkghttp "Jarvis_2.0/backend/go/pkg/http"
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+)
+
+// Crawler defaults, used for zero values in CrawlerConfig.
+const (
+	DefaultCrawlMaxPages  = 100
+	DefaultCrawlDelay     = time.Second
+	DefaultCrawlUserAgent = "JarvisRAGCrawler/1.0"
+
+	maxPageBytes = 10 << 20 // Larger pages are truncated
+)
+
+// publicClient fetches user-supplied URLs. It refuses to connect to loopback, private and other non-public
+// addresses, also when a redirect or a DNS answer leads there, so indexing a URL cannot reach internal services.
+var publicClient = &http.Client{Timeout: 30 * time.Second, Transport: pkghttp.NewPublicTransport()}
+
+// CrawlerConfig controls which pages a CrawlerLoader visits and how fast.
+type CrawlerConfig struct {
+	// MaxDepth is the number of links followed from the start page; 0 only loads the start page.
+	MaxDepth int
+	// MaxPages is the maximum number of pages loaded.
+	MaxPages int
+	// AllowedHosts are the hosts links may lead to. Defaults to the host of the start URL.
+	AllowedHosts []string
+	// PathPrefix restricts links to URL paths starting with it. Defaults to the directory of the start URL,
+	// e.g. "/docs/" for "https://example.com/docs/intro"; "/" allows the whole site.
+	PathPrefix string
+	// Delay is the minimum time between two requests. A longer Crawl-delay in robots.txt takes precedence.
+	Delay time.Duration
+	// UserAgent is sent with every request and selects the robots.txt rules that apply.
+	UserAgent string
+	// Client is the HTTP client used for all requests. Defaults to a client that only connects to public addresses.
+	Client *http.Client
+}
+
+// CrawlerLoader implements the Loader interface by crawling a website from a start URL.
+// It follows links breadth-first within the configured hosts and path prefix, honours robots.txt and
+// the robots meta tag, and returns each page converted to Markdown as a Document with its URL and title.
+type CrawlerLoader struct {
+	cfg CrawlerConfig
+}
+
+// NewCrawlerLoader creates a new CrawlerLoader, filling in defaults for unset config fields.
+func NewCrawlerLoader(cfg CrawlerConfig) *CrawlerLoader {
+	if cfg.MaxPages <= 0 {
+		cfg.MaxPages = DefaultCrawlMaxPages
+	}
+	if cfg.MaxDepth < 0 {
+		cfg.MaxDepth = 0
+	}
+	if cfg.Delay <= 0 {
+		cfg.Delay = DefaultCrawlDelay
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultCrawlUserAgent
+	}
+	if cfg.Client == nil {
+		cfg.Client = publicClient
+	}
+	return &CrawlerLoader{cfg: cfg}
+}
+
+// crawlTarget is a queued URL and its link distance from the start page.
+type crawlTarget struct {
+	url   *url.URL
+	depth int
+}
+
+// crawledPage is a fetched HTML page.
+type crawledPage struct {
+	url      *url.URL // The final URL after redirects
+	title    string
+	markdown string
+	links    []*url.URL
+	noFollow bool
+	noIndex  bool
+}
+
+// Load crawls the site reachable from startURL. Pages that cannot be fetched or are not HTML are skipped,
+// except for the start page, whose failure fails the crawl.
+func (l *CrawlerLoader) Load(ctx context.Context, startURL string) ([]*schema.Document, error) {
+	start, err := url.Parse(startURL)
+	if err != nil || (start.Scheme != "http" && start.Scheme != "https") || start.Host == "" {
+		return nil, fmt.Errorf("invalid crawl start URL %q", startURL)
+	}
+	start.Fragment = ""
+
+	hosts := l.cfg.AllowedHosts
+	if len(hosts) == 0 {
+		hosts = []string{start.Host}
+	}
+	prefix := l.cfg.PathPrefix
+	if prefix == "" {
+		prefix = "/"
+		if i := strings.LastIndexByte(start.Path, '/'); i >= 0 {
+			prefix = start.Path[:i+1]
+		}
+	}
+	inScope := func(u *url.URL) bool {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return false
+		}
+		for _, host := range hosts {
+			if strings.EqualFold(u.Host, host) {
+				return strings.HasPrefix(u.Path, prefix) || u.Path+"/" == prefix
+			}
+		}
+		return false
+	}
+
+	robots := make(map[string]*robotsRules)
+	seen := map[string]bool{start.String(): true}
+	queue := []crawlTarget{{url: start}}
+	var documents []*schema.Document
+	var lastRequest time.Time
+
+	for len(queue) > 0 && len(documents) < l.cfg.MaxPages {
+		target := queue[0]
+		queue = queue[1:]
+
+		rules, ok := robots[target.url.Host]
+		if !ok {
+			if err := l.wait(ctx, lastRequest, 0); err != nil {
+				return nil, err
+			}
+			rules = l.fetchRobots(ctx, target.url)
+			lastRequest = time.Now()
+			robots[target.url.Host] = rules
+		}
+		if !rules.allowed(target.url.RequestURI()) {
+			continue
+		}
+
+		if err := l.wait(ctx, lastRequest, rules.crawlDelay); err != nil {
+			return nil, err
+		}
+		page, err := l.fetchPage(ctx, target.url)
+		lastRequest = time.Now()
+		if err != nil {
+			if target.depth == 0 {
+				return nil, err
+			}
+			continue
+		}
+		seen[page.url.String()] = true
+
+		if !page.noIndex && strings.TrimSpace(page.markdown) != "" {
+			documents = append(documents, &schema.Document{
+				ID:   uuid.New().String(),
+				Text: page.markdown,
+				Metadata: map[string]interface{}{
+					schema.MetadataKeySourceURL: page.url.String(),
+					schema.MetadataKeyTitle:     page.title,
+					schema.MetadataKeyFileName:  page.url.String(),
+					schema.MetadataKeyDocType:   "md",
+				},
+			})
+		}
+
+		if target.depth >= l.cfg.MaxDepth || page.noFollow {
+			continue
+		}
+		for _, link := range page.links {
+			if key := link.String(); !seen[key] && inScope(link) {
+				seen[key] = true
+				queue = append(queue, crawlTarget{url: link, depth: target.depth + 1})
+			}
+		}
+	}
+
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no pages could be crawled from %s", startURL)
+	}
+	return documents, nil
+}
+
+// wait blocks until the configured delay, or the longer crawlDelay, has passed since the last request.
+func (l *CrawlerLoader) wait(ctx context.Context, lastRequest time.Time, crawlDelay time.Duration) error {
+	if lastRequest.IsZero() {
+		return nil
+	}
+	delay := max(l.cfg.Delay, crawlDelay)
+	timer := time.NewTimer(time.Until(lastRequest.Add(delay)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// fetchRobots fetches the robots.txt rules of a URL's host. Following RFC 9309, a missing robots.txt allows
+// everything, while a server error or an unreachable host disallows everything.
+func (l *CrawlerLoader) fetchRobots(ctx context.Context, u *url.URL) *robotsRules {
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	resp, err := l.get(ctx, robotsURL)
+	if err != nil {
+		return &robotsRules{blockAll: true}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsRules{blockAll: true}
+	case resp.StatusCode != http.StatusOK:
+		return &robotsRules{}
+	default:
+		return parseRobots(io.LimitReader(resp.Body, 500<<10), l.cfg.UserAgent)
+	}
+}
+
+// fetchPage fetches an HTML page and converts it to Markdown, collecting its title and the links on it.
+func (l *CrawlerLoader) fetchPage(ctx context.Context, u *url.URL) (*crawledPage, error) {
+	resp, err := l.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", u, resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" {
+		return nil, fmt.Errorf("fetching %s: not an HTML page (%s)", u, mediaType)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", u, err)
+	}
+	root, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", u, err)
+	}
+
+	page := &crawledPage{url: resp.Request.URL}
+	page.url.Fragment = ""
+	base := page.url
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if page.title == "" && n.FirstChild != nil {
+					page.title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "base":
+				if href := attr(n, "href"); href != "" {
+					if u, err := page.url.Parse(href); err == nil {
+						base = u
+					}
+				}
+			case "meta":
+				if strings.EqualFold(attr(n, "name"), "robots") {
+					content := strings.ToLower(attr(n, "content"))
+					page.noIndex = page.noIndex || strings.Contains(content, "noindex") || strings.Contains(content, "none")
+					page.noFollow = page.noFollow || strings.Contains(content, "nofollow") || strings.Contains(content, "none")
+				}
+			case "a":
+				if href := attr(n, "href"); href != "" && !strings.Contains(attr(n, "rel"), "nofollow") {
+					if link, err := base.Parse(href); err == nil {
+						link.Fragment = ""
+						page.links = append(page.links, link)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	markdown, err := htmltomarkdown.ConvertNode(root, converter.WithDomain(base.String()))
+	if err != nil {
+		return nil, fmt.Errorf("converting %s to markdown: %w", u, err)
+	}
+	page.markdown = string(markdown)
+	return page, nil
+}
+
+func (l *CrawlerLoader) get(ctx context.Context, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", l.cfg.UserAgent)
+	return l.cfg.Client.Do(req)
+}
+
+// attr returns the value of an HTML attribute, or "" if the node does not have it.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// compile-time check to ensure CrawlerLoader implements the Loader interface
+var _ interfaces.Loader = (*CrawlerLoader)(nil)
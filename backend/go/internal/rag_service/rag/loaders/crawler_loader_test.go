@@ -0,0 +1,189 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pkghttp "Jarvis_2.0/backend/go/pkg/http"
+)
+
+// newTestSite serves a small documentation site:
+//
+//	/docs/        links to a, b, the blog, an external host and a PDF
+//	/docs/a       links to /docs/a/deep
+//	/docs/a/deep  leaf
+//	/docs/b       disallowed by robots.txt
+//	/blog/        outside the /docs/ prefix
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	page := func(title, body string) string {
+		return fmt.Sprintf("<html><head><title>%s</title></head><body><h1>%s</h1>%s</body></html>", title, title, body)
+	}
+	pages := map[string]string{
+		"/docs/": page("Index", `<a href="a">A</a> <a href="/docs/b#top">B</a> <a href="/blog/">Blog</a>
+			<a href="https://other.example/">Other</a> <a href="/docs/file.pdf">PDF</a>`),
+		"/docs/a":      page("Page A", `<p>Text of <b>A</b>.</p><a href="/docs/a/deep">Deep</a> <a href="/docs/">Back</a>`),
+		"/docs/a/deep": page("Deep", `<p>Deep text.</p>`),
+		"/docs/b":      page("Page B", `<p>Secret.</p>`),
+		"/blog/":       page("Blog", `<p>Blog.</p>`),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /docs/b\n")
+	})
+	mux.HandleFunc("/docs/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		fmt.Fprint(w, "%PDF-1.4")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, body)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func crawledTitles(t *testing.T, docs []*schema.Document) []string {
+	t.Helper()
+	var titles []string
+	for _, doc := range docs {
+		titles = append(titles, doc.Metadata[schema.MetadataKeyTitle].(string))
+	}
+	slices.Sort(titles)
+	return titles
+}
+
+func TestCrawlerLoaderScopeAndRobots(t *testing.T) {
+	server := newTestSite(t)
+	loader := NewCrawlerLoader(CrawlerConfig{MaxDepth: 5, Delay: time.Millisecond, Client: server.Client()})
+
+	docs, err := loader.Load(context.Background(), server.URL+"/docs/")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := crawledTitles(t, docs), []string{"Deep", "Index", "Page A"}; !slices.Equal(got, want) {
+		t.Errorf("crawled titles = %v, want %v", got, want)
+	}
+
+	for _, doc := range docs {
+		if doc.Metadata[schema.MetadataKeyTitle] != "Page A" {
+			continue
+		}
+		if got, want := doc.Metadata[schema.MetadataKeySourceURL], server.URL+"/docs/a"; got != want {
+			t.Errorf("source URL = %v, want %v", got, want)
+		}
+		if !strings.Contains(doc.Text, "# Page A") || !strings.Contains(doc.Text, "**A**") {
+			t.Errorf("page was not converted to Markdown: %q", doc.Text)
+		}
+	}
+}
+
+func TestCrawlerLoaderLimits(t *testing.T) {
+	server := newTestSite(t)
+
+	tests := []struct {
+		name string
+		cfg  CrawlerConfig
+		want []string
+	}{
+		{name: "depth 0", cfg: CrawlerConfig{MaxDepth: 0}, want: []string{"Index"}},
+		{name: "depth 1", cfg: CrawlerConfig{MaxDepth: 1}, want: []string{"Index", "Page A"}},
+		{name: "max pages", cfg: CrawlerConfig{MaxDepth: 5, MaxPages: 2}, want: []string{"Index", "Page A"}},
+		{name: "whole site", cfg: CrawlerConfig{MaxDepth: 1, PathPrefix: "/"}, want: []string{"Blog", "Index", "Page A"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Delay = time.Millisecond
+			tt.cfg.Client = server.Client()
+			docs, err := NewCrawlerLoader(tt.cfg).Load(context.Background(), server.URL+"/docs/")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := crawledTitles(t, docs); !slices.Equal(got, tt.want) {
+				t.Errorf("crawled titles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrawlerLoaderStartPageErrors(t *testing.T) {
+	server := newTestSite(t)
+	loader := NewCrawlerLoader(CrawlerConfig{Delay: time.Millisecond, Client: server.Client()})
+
+	for _, path := range []string{"/missing", "/docs/b", "/docs/file.pdf"} {
+		if _, err := loader.Load(context.Background(), server.URL+path); err == nil {
+			t.Errorf("Load(%s) succeeded, want error", path)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	robots := `
+User-agent: OtherBot
+Disallow: /
+
+User-agent: JarvisRAGCrawler
+User-agent: AnotherBot
+Disallow: /private
+Allow: /private/public
+Disallow: /*.json$
+Crawl-delay: 2
+
+User-agent: *
+Disallow: /
+`
+	rules := parseRobots(strings.NewReader(robots), DefaultCrawlUserAgent)
+	tests := map[string]bool{
+		"/":                    true,
+		"/private":             false,
+		"/private/secret":      false,
+		"/private/public/page": true,
+		"/data.json":           false,
+		"/data.json?x=1":       true,
+	}
+	for path, want := range tests {
+		if got := rules.allowed(path); got != want {
+			t.Errorf("allowed(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("crawlDelay = %v, want 2s", rules.crawlDelay)
+	}
+
+	if parseRobots(strings.NewReader(robots), "SomeBot/1.0").allowed("/page") {
+		t.Error("SomeBot should fall back to the * group and be disallowed")
+	}
+}
+
+func TestLoadersRefuseNonPublicAddresses(t *testing.T) {
+	server := newTestSite(t)
+	start, _ := url.Parse(server.URL + "/docs/")
+
+	// The default client only connects to public addresses, so the loopback test site is refused.
+	loader := NewCrawlerLoader(CrawlerConfig{Delay: time.Millisecond})
+	if _, err := loader.fetchPage(context.Background(), start); !errors.Is(err, pkghttp.ErrNonPublicAddress) {
+		t.Errorf("fetchPage() error = %v, want ErrNonPublicAddress", err)
+	}
+	if _, err := loader.Load(context.Background(), start.String()); err == nil {
+		t.Error("Load() of a loopback site succeeded")
+	}
+	if _, err := NewWebLoader().Load(context.Background(), start.String()); !errors.Is(err, pkghttp.ErrNonPublicAddress) {
+		t.Errorf("WebLoader.Load() error = %v, want ErrNonPublicAddress", err)
+	}
+}
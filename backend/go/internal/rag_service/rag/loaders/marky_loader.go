@@ -0,0 +1,155 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"Jarvis_2.0/backend/go/pkg/tools/readfile/converters"
+	"github.com/google/uuid"
+)
+
+// slideMarkerRegex matches the comment the PPTX converter puts at the start of every slide.
+var slideMarkerRegex = regexp.MustCompile(`<!-- Slide number: (\d+) -->`)
+
+// dataURIImageRegex matches Markdown images embedded as data URIs, capturing their alt text and the URI.
+var dataURIImageRegex = regexp.MustCompile(`!\[([^\]]*)\]\((data:[^)]*)\)`)
+
+// tableDelimiterRegex matches the delimiter row under the header of a Markdown table, e.g. "|---|:--:|".
+var tableDelimiterRegex = regexp.MustCompile(`^\s*\|[\s:|-]*-[\s:|-]*$`)
+
+// MarkyLoader implements the Loader interface with one of the readfile converters, which turn documents into
+// Markdown. The documents are marked with doc type "md" so that they are split by headings.
+// Presentations are returned as one Document per slide, labelled with the slide number.
+type MarkyLoader struct {
+	converter converters.Converter
+}
+
+// NewMarkyLoader creates a new MarkyLoader that converts files with the given converter.
+func NewMarkyLoader(converter converters.Converter) *MarkyLoader {
+	return &MarkyLoader{converter: converter}
+}
+
+// Load converts a file to Markdown and returns it as one Document per page, or a single Document if the format has
+// no pages. Embedded images are replaced by their alt text, as their base64 data is meaningless to embed; their
+// decoded data is kept under schema.MetadataKeyImage. Markdown tables are also kept as rows under
+// schema.MetadataKeyChart.
+func (l *MarkyLoader) Load(ctx context.Context, path string) ([]*schema.Document, error) {
+	markdown, err := l.converter.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc := func(text, pageLabel string) *schema.Document {
+		images := dataURIImages(text)
+		text = dataURIImageRegex.ReplaceAllString(text, "$1")
+		doc := &schema.Document{
+			ID:   uuid.New().String(),
+			Text: strings.TrimSpace(text),
+			Metadata: map[string]interface{}{
+				schema.MetadataKeyFileName: filepath.Base(path),
+				schema.MetadataKeyDocType:  "md",
+			},
+		}
+		if pageLabel != "" {
+			doc.Metadata[schema.MetadataKeyPageLabel] = pageLabel
+		}
+		if len(images) > 0 {
+			doc.Metadata[schema.MetadataKeyImage] = images
+		}
+		if tables := markdownTables(text); len(tables) > 0 {
+			doc.Metadata[schema.MetadataKeyChart] = tables
+		}
+		return doc
+	}
+
+	markers := slideMarkerRegex.FindAllStringSubmatchIndex(markdown, -1)
+	if len(markers) == 0 {
+		return []*schema.Document{newDoc(markdown, "")}, nil
+	}
+
+	var documents []*schema.Document
+	if text := markdown[:markers[0][0]]; strings.TrimSpace(text) != "" {
+		documents = append(documents, newDoc(text, ""))
+	}
+	for i, marker := range markers {
+		end := len(markdown)
+		if i+1 < len(markers) {
+			end = markers[i+1][0]
+		}
+		text := markdown[marker[1]:end]
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		documents = append(documents, newDoc(text, markdown[marker[2]:marker[3]]))
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("no content found in %s", path)
+	}
+	return documents, nil
+}
+
+// dataURIImages returns the decoded data of the images embedded in Markdown as base64 data URIs.
+func dataURIImages(markdown string) [][]byte {
+	var images [][]byte
+	for _, match := range dataURIImageRegex.FindAllStringSubmatch(markdown, -1) {
+		header, data, ok := strings.Cut(match[2], ",")
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			continue
+		}
+		image, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil || len(image) == 0 {
+			continue
+		}
+		images = append(images, image)
+	}
+	return images
+}
+
+// markdownTables returns the tables in Markdown as rows of cell texts. A header row whose cells are all empty, as
+// the DOCX converter writes, is dropped so that the first data row becomes the header.
+func markdownTables(markdown string) [][][]string {
+	lines := strings.Split(markdown, "\n")
+	var tables [][][]string
+	for i := 0; i+1 < len(lines); i++ {
+		if !isTableLine(lines[i]) || !tableDelimiterRegex.MatchString(lines[i+1]) {
+			continue
+		}
+		var rows [][]string
+		if header := tableCells(lines[i]); strings.Join(header, "") != "" {
+			rows = append(rows, header)
+		}
+		end := i + 2
+		for ; end < len(lines) && isTableLine(lines[end]); end++ {
+			rows = append(rows, tableCells(lines[end]))
+		}
+		if len(rows) > 1 {
+			tables = append(tables, rows)
+		}
+		i = end - 1
+	}
+	return tables
+}
+
+func isTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// tableCells splits a Markdown table row into its trimmed cell texts.
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// compile-time check to ensure MarkyLoader implements the Loader interface
+var _ interfaces.Loader = (*MarkyLoader)(nil)
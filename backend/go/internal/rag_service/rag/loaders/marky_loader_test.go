@@ -0,0 +1,118 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeConverter converts every file to the same Markdown.
+type fakeConverter struct {
+	markdown string
+}
+
+func (c *fakeConverter) AcceptedMimeTypes() []string  { return nil }
+func (c *fakeConverter) AcceptedExtensions() []string { return nil }
+func (c *fakeConverter) Load(string) (string, error)  { return c.markdown, nil }
+
+func TestMarkyLoaderSlides(t *testing.T) {
+	markdown := "Deck title\n" +
+		"<!-- Slide number: 1 -->\n# Agenda\n- Intro\n" +
+		"<!-- Slide number: 2 -->\n   \n" +
+		"<!-- Slide number: 3 -->\n# Results\n"
+	docs, err := NewMarkyLoader(&fakeConverter{markdown: markdown}).Load(context.Background(), "/tmp/deck.pptx")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Empty slides are skipped; text before the first slide has no label.
+	want := []struct{ text, label string }{{"Deck title", ""}, {"# Agenda\n- Intro", "1"}, {"# Results", "3"}}
+	if len(docs) != len(want) {
+		t.Fatalf("Load() returned %d documents, want %d", len(docs), len(want))
+	}
+	for i, doc := range docs {
+		if doc.Text != want[i].text {
+			t.Errorf("document %d text = %q, want %q", i, doc.Text, want[i].text)
+		}
+		label, _ := doc.Metadata[schema.MetadataKeyPageLabel].(string)
+		if label != want[i].label {
+			t.Errorf("document %d page label = %q, want %q", i, label, want[i].label)
+		}
+		if doc.Metadata[schema.MetadataKeyFileName] != "deck.pptx" || doc.Metadata[schema.MetadataKeyDocType] != "md" {
+			t.Errorf("document %d metadata = %v", i, doc.Metadata)
+		}
+	}
+}
+
+func TestMarkyLoaderEmptySlides(t *testing.T) {
+	markdown := "<!-- Slide number: 1 -->\n\n<!-- Slide number: 2 -->\n"
+	if _, err := NewMarkyLoader(&fakeConverter{markdown: markdown}).Load(context.Background(), "deck.pptx"); err == nil {
+		t.Error("Load() of a presentation without content succeeded")
+	}
+}
+
+func TestMarkyLoaderDocument(t *testing.T) {
+	markdown := "# Report\n\n![Sales chart](data:image/png;base64,aGVsbG8=)\n\n" +
+		"![Broken](data:image/png;base64,!!!)\n\n" +
+		"| Region | Sales |\n|---|---:|\n| North | 12 |\n| South | 7 |\n"
+	docs, err := NewMarkyLoader(&fakeConverter{markdown: markdown}).Load(context.Background(), "report.docx")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("Load() returned %d documents, want 1", len(docs))
+	}
+	doc := docs[0]
+
+	// Images are replaced by their alt text, and only the decodable ones are kept.
+	wantText := "# Report\n\nSales chart\n\nBroken\n\n| Region | Sales |\n|---|---:|\n| North | 12 |\n| South | 7 |"
+	if doc.Text != wantText {
+		t.Errorf("Text = %q, want %q", doc.Text, wantText)
+	}
+	if images := doc.Metadata[schema.MetadataKeyImage]; !reflect.DeepEqual(images, [][]byte{[]byte("hello")}) {
+		t.Errorf("images = %q, want [hello]", images)
+	}
+	wantTables := [][][]string{{{"Region", "Sales"}, {"North", "12"}, {"South", "7"}}}
+	if tables := doc.Metadata[schema.MetadataKeyChart]; !reflect.DeepEqual(tables, wantTables) {
+		t.Errorf("tables = %q, want %q", tables, wantTables)
+	}
+	if _, ok := doc.Metadata[schema.MetadataKeyPageLabel]; ok {
+		t.Error("document without pages has a page label")
+	}
+}
+
+func TestMarkdownTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     [][][]string
+	}{
+		{"no tables", "Text with a | pipe.\n| not a table |\n", nil},
+		{
+			"table",
+			"Intro\n\n| A | B |\n| :-- | --: |\n| 1 | 2 |\n|3|4|\nAfter",
+			[][][]string{{{"A", "B"}, {"1", "2"}, {"3", "4"}}},
+		},
+		{
+			"empty header is dropped",
+			"|  |  |\n|---|---|\n| Name | Age |\n| Alice | 30 |",
+			[][][]string{{{"Name", "Age"}, {"Alice", "30"}}},
+		},
+		{"header only", "| A | B |\n|---|---|\n\nText", nil},
+		{"empty header and one row", "| | |\n|---|---|\n| A | B |", nil},
+		{"empty cells are kept", "| A | B |\n|---|---|\n| | 2 |", [][][]string{{{"A", "B"}, {"", "2"}}}},
+		{
+			"two tables",
+			"| A |\n|---|\n| 1 |\n\n| B |\n|---|\n| 2 |",
+			[][][]string{{{"A"}, {"1"}}, {{"B"}, {"2"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownTables(tt.markdown); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("markdownTables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
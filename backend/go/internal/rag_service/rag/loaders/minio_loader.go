@@ -0,0 +1,76 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// MinIOScheme is the URI scheme of MinIO objects, which are addressed as "minio://bucket/key".
+const MinIOScheme = "minio"
+
+// MinIOURI returns the URI of a MinIO object.
+func MinIOURI(bucket, key string) string {
+	return MinIOScheme + "://" + bucket + "/" + key
+}
+
+// ParseMinIOURI splits the URI of a MinIO object into its bucket and key.
+func ParseMinIOURI(uri string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(uri, MinIOScheme+"://")
+	if !ok {
+		return "", "", fmt.Errorf("not a MinIO URI: %s", uri)
+	}
+	bucket, key, ok = strings.Cut(rest, "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("MinIO URI must have the form minio://bucket/key: %s", uri)
+	}
+	return bucket, key, nil
+}
+
+// MinIOLoader implements the Loader interface for objects in MinIO. It downloads an object to a temporary file
+// and loads it with the loader the registry picks for the file's type.
+type MinIOLoader struct {
+	client   *minio.Client
+	registry *Registry
+}
+
+// NewMinIOLoader creates a new MinIOLoader that downloads objects with client and loads them via registry.
+func NewMinIOLoader(client *minio.Client, registry *Registry) *MinIOLoader {
+	return &MinIOLoader{client: client, registry: registry}
+}
+
+// Load downloads the object at a "minio://bucket/key" URI and loads it.
+func (l *MinIOLoader) Load(ctx context.Context, uri string) ([]*schema.Document, error) {
+	bucket, key, err := ParseMinIOURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "rag-minio-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The file keeps the object's base name, which loaders record as the file name and use to tell formats apart.
+	localPath := filepath.Join(dir, path.Base(key))
+	if err := l.client.FGetObject(ctx, bucket, key, localPath, minio.GetObjectOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+
+	loader, err := l.registry.LoaderFor(localPath)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(ctx, localPath)
+}
+
+// compile-time check to ensure MinIOLoader implements the Loader interface
+var _ interfaces.Loader = (*MinIOLoader)(nil)
@@ -9,6 +9,7 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/unidoc/unipdf/v3/extractor"
@@ -23,7 +24,7 @@ func NewPdfLoader() *PdfLoader {
 	return &PdfLoader{}
 }
 
-// Load reads a PDF file, extracts text and images from each page,
+// Load reads a PDF file, extracts text, images and tables from each page,
 // and returns a Document for each page.
 func (l *PdfLoader) Load(ctx context.Context, path string) ([]*schema.Document, error) {
 	f, err := os.Open(path)
@@ -54,10 +55,11 @@ func (l *PdfLoader) Load(ctx context.Context, path string) ([]*schema.Document,
 			return nil, err
 		}
 
-		text, err := ex.ExtractText()
+		pageText, _, _, err := ex.ExtractPageText()
 		if err != nil {
 			return nil, err
 		}
+		text := pageText.Text()
 
 		pageImages, err := ex.ExtractPageImages(nil)
 		if err != nil {
@@ -93,6 +95,9 @@ func (l *PdfLoader) Load(ctx context.Context, path string) ([]*schema.Document,
 		if len(imagesData) > 0 {
 			doc.Metadata[schema.MetadataKeyImage] = imagesData
 		}
+		if tables := pdfTables(pageText.Tables()); len(tables) > 0 {
+			doc.Metadata[schema.MetadataKeyChart] = tables
+		}
 
 		documents = append(documents, doc)
 	}
@@ -100,5 +105,25 @@ func (l *PdfLoader) Load(ctx context.Context, path string) ([]*schema.Document,
 	return documents, nil
 }
 
+// pdfTables converts the tables detected on a page to rows of cell texts. Tables with less than two rows or columns
+// are mostly misdetected text layouts and are left out.
+func pdfTables(textTables []extractor.TextTable) [][][]string {
+	var tables [][][]string
+	for _, textTable := range textTables {
+		if textTable.W < 2 || textTable.H < 2 {
+			continue
+		}
+		rows := make([][]string, len(textTable.Cells))
+		for i, cells := range textTable.Cells {
+			rows[i] = make([]string, len(cells))
+			for j, cell := range cells {
+				rows[i][j] = strings.Join(strings.Fields(cell.Text), " ")
+			}
+		}
+		tables = append(tables, rows)
+	}
+	return tables
+}
+
 // compile-time check to ensure PdfLoader implements the Loader interface
 var _ interfaces.Loader = (*PdfLoader)(nil)
@@ -0,0 +1,102 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"Jarvis_2.0/backend/go/pkg/tools/readfile/converters"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// ErrUnsupportedType is returned by Registry.LoaderFor for files no loader is registered for.
+var ErrUnsupportedType = errors.New("unsupported file type")
+
+// registration maps a set of MIME types, optionally narrowed to some file extensions, to a loader.
+type registration struct {
+	loader     interfaces.Loader
+	mimeTypes  []string
+	extensions []string
+}
+
+// Registry picks the loader for a file by its MIME type, which is detected from the file content rather than
+// trusted from the extension. URLs are loaded with the web loader, and URIs of other schemes with the loader
+// registered for the scheme.
+type Registry struct {
+	webLoader     interfaces.Loader
+	schemes       map[string]interfaces.Loader
+	registrations []registration
+}
+
+// NewRegistry creates an empty Registry that loads URLs with webLoader.
+func NewRegistry(webLoader interfaces.Loader) *Registry {
+	return &Registry{webLoader: webLoader, schemes: make(map[string]interfaces.Loader)}
+}
+
+// NewDefaultRegistry creates a Registry for every format the RAG service can index: PDF and Excel workbooks with
+// their page and sheet boundaries, Markdown, plain text and CSV as they are, and Word, PowerPoint, EPUB, HTML and
+// Jupyter notebooks converted to Markdown by the readfile converters.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry(NewWebLoader())
+	r.Register(NewPdfLoader(), []string{"application/pdf"})
+	r.Register(NewXlsxLoader(), []string{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"})
+	r.Register(NewMarkyLoader(converters.NewDocxConverterWithDataURIs()), []string{"application/vnd.openxmlformats-officedocument.wordprocessingml.document"})
+	r.Register(NewMarkyLoader(converters.NewPptxConverter()), []string{"application/vnd.openxmlformats-officedocument.presentationml.presentation"})
+	r.Register(NewMarkyLoader(converters.NewEpubConverter()), []string{"application/epub+zip"})
+	r.Register(NewMarkyLoader(converters.NewHTMLConverter()), []string{"text/html"})
+	// Notebooks are detected as plain JSON.
+	r.Register(NewMarkyLoader(converters.NewIpynbConverter()), []string{"application/json"}, ".ipynb")
+	// CSV is kept raw so that the table splitter can repeat its header line in every chunk.
+	r.Register(NewTxtLoader(), []string{"text/csv"})
+	r.Register(NewMarkdownLoader(), []string{"text/plain"}, ".md", ".markdown")
+	r.Register(NewTxtLoader(), []string{"text/plain"})
+	return r
+}
+
+// Register adds a loader for files of the given MIME types. If extensions are given, the loader is only used for
+// files with one of these extensions, which distinguishes formats that share a MIME type, such as Markdown and
+// plain text. Registrations are tried in order, so more specific ones must come first.
+func (r *Registry) Register(loader interfaces.Loader, mimeTypes []string, extensions ...string) {
+	r.registrations = append(r.registrations, registration{loader: loader, mimeTypes: mimeTypes, extensions: extensions})
+}
+
+// RegisterScheme adds a loader for URIs of a scheme, such as "minio" for "minio://bucket/key".
+func (r *Registry) RegisterScheme(scheme string, loader interfaces.Loader) {
+	r.schemes[scheme] = loader
+}
+
+// LoaderFor returns the loader for a file path or URL. It returns an error wrapping ErrUnsupportedType if the file's
+// type has no loader.
+func (r *Registry) LoaderFor(path string) (interfaces.Loader, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return r.webLoader, nil
+	}
+	if scheme, _, ok := strings.Cut(path, "://"); ok {
+		if loader, ok := r.schemes[scheme]; ok {
+			return loader, nil
+		}
+		return nil, fmt.Errorf("%w: no loader for %s:// URIs", ErrUnsupportedType, scheme)
+	}
+
+	mtype, err := mimetype.DetectFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect type of %s: %w", path, err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+
+	for _, reg := range r.registrations {
+		if len(reg.extensions) > 0 && !slices.Contains(reg.extensions, ext) {
+			continue
+		}
+		// A detected type also matches the types it specialises, e.g. text/csv is a kind of text/plain.
+		for m := mtype; m != nil; m = m.Parent() {
+			if slices.ContainsFunc(reg.mimeTypes, m.Is) {
+				return reg.loader, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is %s", ErrUnsupportedType, filepath.Base(path), mtype.String())
+}
@@ -0,0 +1,140 @@
+package loaders
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// namedLoader is a Loader that only tells which registration it is.
+type namedLoader string
+
+func (l namedLoader) Load(context.Context, string) ([]*schema.Document, error) {
+	return nil, nil
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const (
+	pdfContent  = "%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n"
+	htmlContent = "<!DOCTYPE html><html><head><title>T</title></head><body><p>Text</p></body></html>"
+	csvContent  = "name,age,city\nalice,30,paris\nbob,25,berlin\n"
+	jsonContent = `{"cells": [], "metadata": {}, "nbformat": 4, "nbformat_minor": 5}`
+	pngContent  = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00"
+)
+
+func TestRegistryLoaderFor(t *testing.T) {
+	r := NewRegistry(namedLoader("web"))
+	r.Register(namedLoader("pdf"), []string{"application/pdf"})
+	r.Register(namedLoader("html"), []string{"text/html"})
+	r.Register(namedLoader("ipynb"), []string{"application/json"}, ".ipynb")
+	r.Register(namedLoader("csv"), []string{"text/csv"})
+	r.Register(namedLoader("markdown"), []string{"text/plain"}, ".md", ".markdown")
+	r.Register(namedLoader("text"), []string{"text/plain"})
+	r.RegisterScheme("minio", namedLoader("minio"))
+
+	tests := []struct {
+		name    string
+		file    string // Written with content unless path is set
+		content string
+		path    string
+		want    namedLoader
+	}{
+		{"pdf", "report.pdf", pdfContent, "", "pdf"},
+		{"type is detected from the content", "report.txt", pdfContent, "", "pdf"},
+		{"html", "page.html", htmlContent, "", "html"},
+		{"csv", "people.csv", csvContent, "", "csv"},
+		{"notebook", "analysis.ipynb", jsonContent, "", "ipynb"},
+		{"markdown", "README.md", "# Title\n\nSome text.\n", "", "markdown"},
+		{"extension is case-insensitive", "NOTES.MARKDOWN", "# Title\n", "", "markdown"},
+		{"plain text", "notes.txt", "Some text.\n", "", "text"},
+		{"plain text without extension", "notes", "Some text.\n", "", "text"},
+		{"http url", "", "", "http://example.com/page", "web"},
+		{"https url", "", "", "https://example.com/file.pdf", "web"},
+		{"registered scheme", "", "", "minio://bucket/key.pdf", "minio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = writeTestFile(t, tt.file, tt.content)
+			}
+			loader, err := r.LoaderFor(path)
+			if err != nil {
+				t.Fatalf("LoaderFor(%q) error = %v", path, err)
+			}
+			if loader != tt.want {
+				t.Errorf("LoaderFor(%q) = %v, want %v", path, loader, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryLoaderForUnsupported(t *testing.T) {
+	r := NewRegistry(namedLoader("web"))
+	r.Register(namedLoader("pdf"), []string{"application/pdf"})
+	r.Register(namedLoader("ipynb"), []string{"application/json"}, ".ipynb")
+	r.RegisterScheme("minio", namedLoader("minio"))
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"unknown type", writeTestFile(t, "image.png", pngContent)},
+		{"unknown type with a known extension", writeTestFile(t, "image.pdf", pngContent)},
+		{"extension does not match", writeTestFile(t, "data.json", jsonContent)},
+		{"unregistered scheme", "s3://bucket/key.pdf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if loader, err := r.LoaderFor(tt.path); !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("LoaderFor(%q) = %v, %v; want ErrUnsupportedType", tt.path, loader, err)
+			}
+		})
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.pdf")
+	if _, err := r.LoaderFor(missing); err == nil || errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("LoaderFor(%q) error = %v, want a detection error", missing, err)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := NewDefaultRegistry()
+	tests := []struct {
+		file    string
+		content string
+		want    string
+	}{
+		{"report.pdf", pdfContent, "*loaders.PdfLoader"},
+		{"page.html", htmlContent, "*loaders.MarkyLoader"},
+		{"analysis.ipynb", jsonContent, "*loaders.MarkyLoader"},
+		{"people.csv", csvContent, "*loaders.TxtLoader"},
+		{"README.md", "# Title\n", "*loaders.MarkdownLoader"},
+		{"notes.txt", "Some text.\n", "*loaders.TxtLoader"},
+	}
+	for _, tt := range tests {
+		loader, err := r.LoaderFor(writeTestFile(t, tt.file, tt.content))
+		if err != nil {
+			t.Errorf("LoaderFor(%q) error = %v", tt.file, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", loader); got != tt.want {
+			t.Errorf("LoaderFor(%q) = %s, want %s", tt.file, got, tt.want)
+		}
+	}
+	if _, err := r.LoaderFor(writeTestFile(t, "image.png", pngContent)); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("LoaderFor(image.png) error = %v, want ErrUnsupportedType", err)
+	}
+}
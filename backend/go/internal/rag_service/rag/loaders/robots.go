@@ -0,0 +1,141 @@
+package loaders
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsRules are the robots.txt rules that apply to one user agent on one host.
+type robotsRules struct {
+	allow        []*regexp.Regexp
+	disallow     []*regexp.Regexp
+	allowLens    []int // Length of each allow pattern, for longest-match precedence
+	disallowLens []int
+	crawlDelay   time.Duration
+	blockAll     bool // The host could not tell us its rules, so nothing may be crawled
+}
+
+// robotsGroup is a group of rules in a robots.txt file together with the user agents it applies to.
+type robotsGroup struct {
+	agents []string
+	rules  robotsRules
+}
+
+// parseRobots parses a robots.txt file as described in RFC 9309 and returns the rules for userAgent:
+// the group naming the longest matching product token, or else the "*" group.
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inAgents := false // Whether the previous line was a user-agent line, so consecutive agents share a group
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgents = true
+			continue
+		case "allow", "disallow":
+			if current != nil && value != "" {
+				pattern := robotsPattern(value)
+				if key == "allow" {
+					current.rules.allow = append(current.rules.allow, pattern)
+					current.rules.allowLens = append(current.rules.allowLens, len(value))
+				} else {
+					current.rules.disallow = append(current.rules.disallow, pattern)
+					current.rules.disallowLens = append(current.rules.disallowLens, len(value))
+				}
+			}
+		case "crawl-delay":
+			if current != nil {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					current.rules.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+		inAgents = false
+	}
+
+	// The product token is the user agent up to the first slash, e.g. "JarvisBot" for "JarvisBot/1.0".
+	product := strings.ToLower(strings.SplitN(userAgent, "/", 2)[0])
+	var best, wildcard *robotsGroup
+	bestLen := 0
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = group
+				}
+			case strings.HasPrefix(product, agent) && len(agent) > bestLen:
+				best, bestLen = group, len(agent)
+			}
+		}
+	}
+	if best != nil {
+		return &best.rules
+	}
+	if wildcard != nil {
+		return &wildcard.rules
+	}
+	return &robotsRules{}
+}
+
+// robotsPattern compiles a robots.txt path pattern, in which "*" matches any characters and a trailing "$" anchors
+// the pattern at the end of the path.
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed reports whether a path, including its query, may be crawled. The longest matching rule wins,
+// and allow wins a tie.
+func (r *robotsRules) allowed(path string) bool {
+	if r.blockAll {
+		return false
+	}
+	if path == "" {
+		path = "/"
+	}
+	allowLen, disallowLen := -1, -1
+	for i, pattern := range r.allow {
+		if pattern.MatchString(path) && r.allowLens[i] > allowLen {
+			allowLen = r.allowLens[i]
+		}
+	}
+	for i, pattern := range r.disallow {
+		if pattern.MatchString(path) && r.disallowLens[i] > disallowLen {
+			disallowLen = r.disallowLens[i]
+		}
+	}
+	return allowLen >= disallowLen
+}
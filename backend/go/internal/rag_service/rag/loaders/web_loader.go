@@ -27,7 +27,7 @@ func (l *WebLoader) Load(ctx context.Context, url string) ([]*schema.Document, e
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := publicClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (l *WebLoader) Load(ctx context.Context, url string) ([]*schema.Document, e
 		ID:   uuid.New().String(),
 		Text: text,
 		Metadata: map[string]interface{}{
-			"source_url": url,
+			schema.MetadataKeySourceURL: url,
 		},
 	}
 
@@ -65,8 +65,9 @@ func (l *XlsxLoader) Load(ctx context.Context, path string) ([]*schema.Document,
 			ID:   uuid.New().String(),
 			Text: mdBuilder.String(),
 			Metadata: map[string]interface{}{
-				schema.MetadataKeyFileName: filepath.Base(path),
-				"sheet_name":               sheetName,
+				schema.MetadataKeyFileName:  filepath.Base(path),
+				schema.MetadataKeyPageLabel: sheetName,
+				"sheet_name":                sheetName,
 			},
 		}
 
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// citationRegex matches inline citation markers such as "[2]" or "[1, 3]".
+var citationRegex = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// Citation links an inline marker in an answer to the context document it refers to.
+type Citation struct {
+	// Marker is the number used in the answer, e.g. 2 for "[2]". It is the 1-based position of the document
+	// in the context given to the LLM.
+	Marker   int
+	Document *schema.Document
+}
+
+// Answer is a generated answer together with the citations it actually uses.
+type Answer struct {
+	Text      string
+	Citations []Citation
+}
+
+// extractCitations validates the citation markers in text against the documents the answer was generated from.
+// Markers that do not refer to a document are removed from the text; valid ones are returned in order of first use,
+// each document at most once.
+func extractCitations(text string, documents []*schema.Document) *Answer {
+	answer := &Answer{}
+	cited := make(map[int]bool)
+
+	answer.Text = citationRegex.ReplaceAllStringFunc(text, func(marker string) string {
+		var valid []string
+		for _, part := range strings.Split(strings.Trim(marker, "[]"), ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || n < 1 || n > len(documents) {
+				continue
+			}
+			valid = append(valid, strconv.Itoa(n))
+			if !cited[n] {
+				cited[n] = true
+				answer.Citations = append(answer.Citations, Citation{Marker: n, Document: documents[n-1]})
+			}
+		}
+		if len(valid) == 0 {
+			return ""
+		}
+		return "[" + strings.Join(valid, ", ") + "]"
+	})
+	return answer
+}
+
+// sourceLabel describes where a context document comes from, e.g. "report.pdf, page 3, chunk 2".
+func sourceLabel(doc *schema.Document) string {
+	if doc.Metadata[schema.MetadataKeySourceType] == schema.SourceTypeKnowledgeGraph {
+		return "knowledge graph"
+	}
+	var parts []string
+	if fileName, ok := doc.Metadata[schema.MetadataKeyFileName]; ok {
+		parts = append(parts, fmt.Sprintf("%v", fileName))
+	}
+	if pageLabel, ok := doc.Metadata[schema.MetadataKeyPageLabel]; ok {
+		parts = append(parts, fmt.Sprintf("page %v", pageLabel))
+	}
+	if chunkNumber := MetadataInt(doc.Metadata, "chunk_number"); chunkNumber > 0 {
+		parts = append(parts, fmt.Sprintf("chunk %d", chunkNumber))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// MetadataInt reads an integer metadata value. Doc stores may return numbers as any numeric type or as strings
+// depending on their encoding, so all of them are accepted; missing or malformed values yield 0.
+func MetadataInt(metadata map[string]interface{}, key string) int {
+	switch v := metadata[key].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	default:
+		return 0
+	}
+}
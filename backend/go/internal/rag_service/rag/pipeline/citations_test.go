@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"slices"
+	"testing"
+)
+
+func TestExtractCitations(t *testing.T) {
+	documents := docsWithIDs("a", "b", "c")
+	tests := []struct {
+		name        string
+		text        string
+		wantText    string
+		wantMarkers []int
+	}{
+		{"no citations", "Plain answer.", "Plain answer.", nil},
+		{"single", "Go is fast [2].", "Go is fast [2].", []int{2}},
+		{"order of first use", "First [3], then [1], again [3].", "First [3], then [1], again [3].", []int{3, 1}},
+		{"list is normalized", "Both [1,2] and [2 , 3].", "Both [1, 2] and [2, 3].", []int{1, 2, 3}},
+		{"out of range is removed", "Made up [4] and [0].", "Made up  and .", nil},
+		{"invalid entries of a list are dropped", "Mixed [1, 7, 3].", "Mixed [1, 3].", []int{1, 3}},
+		{"overflow is removed", "Huge [99999999999999999999].", "Huge .", nil},
+		{"not a marker", "See [a] and [1a].", "See [a] and [1a].", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer := extractCitations(tt.text, documents)
+			if answer.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", answer.Text, tt.wantText)
+			}
+			var markers []int
+			for _, citation := range answer.Citations {
+				markers = append(markers, citation.Marker)
+				if citation.Document != documents[citation.Marker-1] {
+					t.Errorf("citation [%d] refers to document %s", citation.Marker, citation.Document.ID)
+				}
+			}
+			if !slices.Equal(markers, tt.wantMarkers) {
+				t.Errorf("markers = %v, want %v", markers, tt.wantMarkers)
+			}
+		})
+	}
+
+	if answer := extractCitations("Nothing to cite [1].", nil); answer.Text != "Nothing to cite ." || len(answer.Citations) != 0 {
+		t.Errorf("extractCitations() without documents = %+v", answer)
+	}
+}
+
+func TestSourceLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+	}{
+		{"empty", nil, ""},
+		{
+			"file, page and chunk",
+			map[string]interface{}{schema.MetadataKeyFileName: "report.pdf", schema.MetadataKeyPageLabel: "3", "chunk_number": 2},
+			"report.pdf, page 3, chunk 2",
+		},
+		{"chunk number as string", map[string]interface{}{"chunk_number": "4"}, "chunk 4"},
+		{
+			"knowledge graph",
+			map[string]interface{}{schema.MetadataKeySourceType: schema.SourceTypeKnowledgeGraph, schema.MetadataKeyFileName: "x"},
+			"knowledge graph",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourceLabel(&schema.Document{Metadata: tt.metadata}); got != tt.want {
+				t.Errorf("sourceLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataInt(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  int
+	}{
+		{3, 3},
+		{int32(3), 3},
+		{int64(3), 3},
+		{3.0, 3},
+		{"3", 3},
+		{"three", 0},
+		{nil, 0},
+		{true, 0},
+	}
+	for _, tt := range tests {
+		if got := MetadataInt(map[string]interface{}{"n": tt.value}, "n"); got != tt.want {
+			t.Errorf("MetadataInt(%#v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"sort"
+)
+
+// rrfK is the rank constant of reciprocal-rank fusion. 60 is the value from the original RRF paper;
+// it dampens the influence of the very top ranks so that agreement between result lists matters more.
+const rrfK = 60
+
+// HybridWeights controls how much each retriever contributes to reciprocal-rank fusion.
+// A weight of zero disables that retriever.
+type HybridWeights struct {
+	Vector  float64
+	Keyword float64
+}
+
+// DefaultHybridWeights gives vector and keyword search equal say.
+var DefaultHybridWeights = HybridWeights{Vector: 1, Keyword: 1}
+
+// rankedList is one retriever's results, best first, together with the weight of that retriever.
+type rankedList struct {
+	docs   []*schema.Document
+	weight float64
+}
+
+// reciprocalRankFusion merges ranked lists into one, scoring each document by the weighted sum of 1/(rrfK+rank)
+// over the lists it appears in, and stores that as its Score. Metadata from the first list containing a document wins.
+func reciprocalRankFusion(lists []rankedList, topK int) []*schema.Document {
+	scores := make(map[string]float64)
+	docs := make(map[string]*schema.Document)
+	var order []string
+
+	for _, list := range lists {
+		if list.weight <= 0 {
+			continue
+		}
+		for rank, doc := range list.docs {
+			if _, ok := docs[doc.ID]; !ok {
+				docs[doc.ID] = doc
+				order = append(order, doc.ID)
+			}
+			scores[doc.ID] += list.weight / float64(rrfK+rank+1)
+		}
+	}
+
+	// A stable sort keeps the first-seen order for ties, which favours the earlier lists.
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	if topK > 0 && len(order) > topK {
+		order = order[:topK]
+	}
+
+	fused := make([]*schema.Document, len(order))
+	for i, id := range order {
+		fused[i] = docs[id]
+		fused[i].Score = scores[id]
+	}
+	return fused
+}
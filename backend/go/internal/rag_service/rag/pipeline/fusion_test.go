@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"math"
+	"slices"
+	"testing"
+)
+
+func docsWithIDs(ids ...string) []*schema.Document {
+	docs := make([]*schema.Document, len(ids))
+	for i, id := range ids {
+		docs[i] = &schema.Document{ID: id}
+	}
+	return docs
+}
+
+func documentIDs(docs []*schema.Document) []string {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids
+}
+
+func TestReciprocalRankFusion(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists []rankedList
+		topK  int
+		want  []string
+	}{
+		{
+			name: "agreement beats a single top rank",
+			lists: []rankedList{
+				{docs: docsWithIDs("a", "b", "c"), weight: 1},
+				{docs: docsWithIDs("d", "b", "c"), weight: 1},
+			},
+			want: []string{"b", "c", "a", "d"},
+		},
+		{
+			name: "ties keep the order of the earlier list",
+			lists: []rankedList{
+				{docs: docsWithIDs("a"), weight: 1},
+				{docs: docsWithIDs("b"), weight: 1},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "weights",
+			lists: []rankedList{
+				{docs: docsWithIDs("a"), weight: 1},
+				{docs: docsWithIDs("b"), weight: 2},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "zero weight disables a list",
+			lists: []rankedList{
+				{docs: docsWithIDs("a", "b"), weight: 0},
+				{docs: docsWithIDs("c"), weight: 1},
+			},
+			want: []string{"c"},
+		},
+		{
+			name: "topK",
+			lists: []rankedList{
+				{docs: docsWithIDs("a", "b", "c"), weight: 1},
+			},
+			topK: 2,
+			want: []string{"a", "b"},
+		},
+		{name: "empty", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := documentIDs(reciprocalRankFusion(tt.lists, tt.topK)); !slices.Equal(got, tt.want) {
+				t.Errorf("reciprocalRankFusion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReciprocalRankFusionScore(t *testing.T) {
+	fused := reciprocalRankFusion([]rankedList{
+		{docs: docsWithIDs("a", "b"), weight: 1},
+		{docs: docsWithIDs("b"), weight: 0.5},
+	}, 0)
+	want := map[string]float64{"a": 1.0 / 61, "b": 1.0/62 + 0.5/61}
+	for _, doc := range fused {
+		if math.Abs(doc.Score-want[doc.ID]) > 1e-12 {
+			t.Errorf("Score of %s = %v, want %v", doc.ID, doc.Score, want[doc.ID])
+		}
+	}
+}
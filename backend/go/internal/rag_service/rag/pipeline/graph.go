@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Bounds of the graph expansion. Paths longer than two hops mostly lead to facts unrelated to the query.
+const (
+	defaultGraphHops  = 2
+	maxGraphHops      = 2
+	defaultGraphFacts = 30
+)
+
+// graphUserEntity is the name the memory service's graph extractor gives the user in their own graph.
+const graphUserEntity = "USER_ID"
+
+// GraphRetriever expands the entities of a query through a knowledge graph into facts that are added to the
+// retrieved documents as an extra source.
+type GraphRetriever struct {
+	graph    interfaces.KnowledgeGraph
+	maxHops  int
+	maxFacts int
+}
+
+// NewGraphRetriever creates a new GraphRetriever that follows relations up to maxHops (1 or 2) away from the
+// entities of a query and returns at most maxFacts of them. Zero values select the defaults.
+func NewGraphRetriever(graph interfaces.KnowledgeGraph, maxHops, maxFacts int) *GraphRetriever {
+	if maxHops <= 0 {
+		maxHops = defaultGraphHops
+	}
+	if maxFacts <= 0 {
+		maxFacts = defaultGraphFacts
+	}
+	return &GraphRetriever{graph: graph, maxHops: min(maxHops, maxGraphHops), maxFacts: maxFacts}
+}
+
+// withGraphFacts appends the knowledge graph facts about the entities of query to docs if req asks for them.
+// Failures are logged and docs are returned unchanged.
+func (p *RetrievalPipeline) withGraphFacts(ctx context.Context, req RetrievalRequest, query string, docs []*schema.Document) []*schema.Document {
+	if !req.Graph || p.graph == nil || p.llm == nil {
+		return docs
+	}
+	doc, err := p.graphFacts(ctx, req.UserID, query)
+	if err != nil {
+		p.log.Warn(fmt.Sprintf("%v. Retrieving without knowledge graph facts.", err))
+		return docs
+	}
+	if doc == nil {
+		p.log.Info("No knowledge graph facts found for the query.")
+		return docs
+	}
+	return append(docs, doc)
+}
+
+// graphFacts returns a document listing the relations of the user's knowledge graph around the entities of query,
+// or nil if the query names no entity of the graph.
+func (p *RetrievalPipeline) graphFacts(ctx context.Context, userID, query string) (*schema.Document, error) {
+	entities, err := p.queryEntities(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+
+	relations, err := p.graph.graph.GetRelatedRelations(ctx, userID, entities, p.graph.maxHops, p.graph.maxFacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query knowledge graph: %w", err)
+	}
+	if len(relations) == 0 {
+		return nil, nil
+	}
+	p.log.Info(fmt.Sprintf("Retrieved %d facts from the knowledge graph for entities %v", len(relations), entities))
+
+	entityName := func(name string) string {
+		if name == graphUserEntity {
+			return "the user"
+		}
+		return name
+	}
+	var sb strings.Builder
+	sb.WriteString("Facts from the user's knowledge graph:")
+	for _, rel := range relations {
+		sb.WriteString(fmt.Sprintf("\n- (%s) -[%s]-> (%s)", entityName(rel.Source), rel.Type, entityName(rel.Target)))
+	}
+	text := sb.String()
+
+	// The ID is derived from the facts so that the same facts are the same source across queries.
+	hash := sha256.Sum256([]byte(userID + "\x00" + text))
+	return &schema.Document{
+		ID:   "graph-" + hex.EncodeToString(hash[:8]),
+		Text: text,
+		Metadata: map[string]interface{}{
+			schema.MetadataKeySourceType: schema.SourceTypeKnowledgeGraph,
+			"entities":                   strings.Join(entities, ", "),
+		},
+	}, nil
+}
+
+// queryEntities asks the LLM for the names of the entities query is about.
+func (p *RetrievalPipeline) queryEntities(ctx context.Context, query string) ([]string, error) {
+	prompt := fmt.Sprintf("List the entities the following question is about, such as people, organizations, places, "+
+		"products, projects and concepts, with their names written as in the question. If the question refers to the "+
+		"person asking it (I, me, my), also list %s. Reply with one entity per line and nothing else, or with nothing "+
+		"if there is none.\n\nQuestion: %s", graphUserEntity, query)
+
+	response, err := p.llm.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract entities from query: %w", err)
+	}
+
+	var entities []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(response, "\n") {
+		entity := strings.TrimSpace(listMarkerRegex.ReplaceAllString(strings.TrimSpace(line), ""))
+		if entity == "" || seen[strings.ToLower(entity)] {
+			continue
+		}
+		seen[strings.ToLower(entity)] = true
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// fixedLLM answers every prompt with the same response.
+type fixedLLM string
+
+func (l fixedLLM) Generate(context.Context, string) (string, error) {
+	return string(l), nil
+}
+
+// fakeGraph returns the same relations for any lookup and records the last one.
+type fakeGraph struct {
+	relations []*models.Relation
+	err       error
+	entities  []string
+	maxHops   int
+}
+
+func (g *fakeGraph) GetRelatedRelations(_ context.Context, _ string, entities []string, maxHops, _ int) ([]*models.Relation, error) {
+	g.entities, g.maxHops = entities, maxHops
+	return g.relations, g.err
+}
+
+func TestWithGraphFacts(t *testing.T) {
+	graph := &fakeGraph{relations: []*models.Relation{
+		{Source: "USER_ID", Type: "WORKS_AT", Target: "Acme"},
+		{Source: "Acme", Type: "LOCATED_IN", Target: "Berlin"},
+	}}
+	p := NewRetrievalPipeline(nil, nil, nil, nil, nil, fixedLLM("1. Acme\n- my employer\nacme\n\nUSER_ID\n"),
+		NewGraphRetriever(graph, 5, 0), *logger.New("test", "", ""))
+	chunk := &schema.Document{ID: "c1", Text: "chunk"}
+
+	docs := p.withGraphFacts(context.Background(), RetrievalRequest{UserID: "u1", Graph: true}, "Where is my employer Acme?", []*schema.Document{chunk})
+	if len(docs) != 2 || docs[0] != chunk {
+		t.Fatalf("withGraphFacts() = %v, want the chunk followed by the facts", docs)
+	}
+	if want := []string{"Acme", "my employer", "USER_ID"}; !slices.Equal(graph.entities, want) {
+		t.Errorf("looked up entities %v, want %v", graph.entities, want)
+	}
+	if graph.maxHops != maxGraphHops {
+		t.Errorf("looked up %d hops, want %d", graph.maxHops, maxGraphHops)
+	}
+	facts := docs[1]
+	wantText := "Facts from the user's knowledge graph:\n- (the user) -[WORKS_AT]-> (Acme)\n- (Acme) -[LOCATED_IN]-> (Berlin)"
+	if facts.Text != wantText || facts.Metadata[schema.MetadataKeySourceType] != schema.SourceTypeKnowledgeGraph {
+		t.Errorf("facts = %q %v, want %q", facts.Text, facts.Metadata, wantText)
+	}
+	if label := sourceLabel(facts); label != "knowledge graph" {
+		t.Errorf("sourceLabel() = %q, want %q", label, "knowledge graph")
+	}
+
+	// Without the request flag, or if the graph fails, the chunks are returned as they are.
+	if docs := p.withGraphFacts(context.Background(), RetrievalRequest{UserID: "u1"}, "Where is Acme?", []*schema.Document{chunk}); len(docs) != 1 {
+		t.Errorf("withGraphFacts() without Graph returned %d documents, want 1", len(docs))
+	}
+	graph.err = errors.New("neo4j unavailable")
+	if docs := p.withGraphFacts(context.Background(), RetrievalRequest{UserID: "u1", Graph: true}, "Where is Acme?", []*schema.Document{chunk}); len(docs) != 1 {
+		t.Errorf("withGraphFacts() with a failing graph returned %d documents, want 1", len(docs))
+	}
+}
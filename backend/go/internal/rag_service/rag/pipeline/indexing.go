@@ -1,45 +1,77 @@
 package pipeline
 
 import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/dedup"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	ragv1 "Jarvis_2.0/api/proto/v1/rag"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"golang.org/x/sync/errgroup"
 )
 
+// embeddingProgressStep is the number of chunks embedded between progress updates. The embedder splits each step
+// into batches the provider accepts.
+const embeddingProgressStep = 500
+
 // IndexingPipeline orchestrates the process of loading, splitting, embedding, and storing documents.
 type IndexingPipeline struct {
-	splitter    interfaces.Splitter
-	embedder    interfaces.EmbeddingModel
-	docStore    interfaces.DocStore
-	vectorStore interfaces.VectorStore
-	log         logger.Logger
+	splitter     interfaces.Splitter
+	embedder     interfaces.EmbeddingModel
+	docStore     interfaces.DocStore
+	vectorStore  interfaces.VectorStore
+	keywordIndex interfaces.KeywordIndex // Optional keyword index for hybrid retrieval
+	dedup        *dedup.Deduplicator     // Optional; if nil, duplicate chunks are stored like any other
+	media        *MediaExtractor         // Optional; if nil, images and tables are not indexed on their own
+	log          logger.Logger
 }
 
 // NewIndexingPipeline creates a new IndexingPipeline.
+// The keyword index, the deduplicator and the media extractor are optional and can be nil.
 func NewIndexingPipeline(
 	splitter interfaces.Splitter,
 	embedder interfaces.EmbeddingModel,
 	docStore interfaces.DocStore,
 	vectorStore interfaces.VectorStore,
+	keywordIndex interfaces.KeywordIndex,
+	deduplicator *dedup.Deduplicator,
+	media *MediaExtractor,
 	log logger.Logger,
 ) *IndexingPipeline {
 	return &IndexingPipeline{
-		splitter:    splitter,
-		embedder:    embedder,
-		docStore:    docStore,
-		vectorStore: vectorStore,
-		log:         log,
+		splitter:     splitter,
+		embedder:     embedder,
+		docStore:     docStore,
+		vectorStore:  vectorStore,
+		keywordIndex: keywordIndex,
+		dedup:        deduplicator,
+		media:        media,
+		log:          log,
 	}
 }
 
+// IndexResult describes the outcome of indexing a single data source.
+type IndexResult struct {
+	ContentHash string   // SHA-256 of the loaded content
+	ChunkIDs    []string // IDs of the stored chunks; empty if the source was skipped
+	// DuplicateOf holds, for every chunk of the source that duplicates a chunk stored for another source of the
+	// folder, the ID of that chunk, which the source references instead of storing a copy.
+	DuplicateOf []string
+	Skipped     bool // True if the content hash matched previousHash and nothing was stored
+}
+
 // Run executes the entire indexing pipeline for a given data source and streams progress updates.
-func (p *IndexingPipeline) Run(ctx context.Context, loader interfaces.Loader, path, userID, folderID string, progressChan chan<- *ragv1.IndexResponse) error {
+// If previousHash is not empty and the loaded content hashes to the same value, the source is skipped.
+func (p *IndexingPipeline) Run(ctx context.Context, loader interfaces.Loader, path, userID, folderID, previousHash string, progressChan chan<- *ragv1.IndexResponse) (*IndexResult, error) {
 	defer close(progressChan)
 
 	p.log.Info(fmt.Sprintf("Starting indexing for path: %s, user: %s, folder: %s", path, userID, folderID))
@@ -49,43 +81,146 @@ func (p *IndexingPipeline) Run(ctx context.Context, loader interfaces.Loader, pa
 	initialDocs, err := loader.Load(ctx, path)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Failed to load data: %v", err))
-		return err
+		return nil, err
 	}
 	progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Loaded %d initial documents", len(initialDocs)), Progress: 10}
 
-	// 2. Split documents into chunks
+	result := &IndexResult{ContentHash: ContentHash(initialDocs)}
+	if previousHash != "" && previousHash == result.ContentHash {
+		p.log.Info(fmt.Sprintf("Content of %s is unchanged, skipping", path))
+		progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Content unchanged, skipped: %s", path), Progress: 100}
+		result.Skipped = true
+		return result, nil
+	}
+
+	// 2. Turn images and tables into documents of their own. Their raw data is not kept in the metadata either way,
+	// as it would be copied into every chunk of the page.
+	if p.media != nil {
+		mediaDocs, err := p.media.Extract(ctx, userID, initialDocs)
+		if err != nil {
+			p.log.Error(fmt.Sprintf("Failed to extract images and tables: %v", err))
+			return nil, err
+		}
+		initialDocs = append(initialDocs, mediaDocs...)
+		progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Extracted %d images and tables", len(mediaDocs)), Progress: 15}
+	} else {
+		for _, doc := range initialDocs {
+			delete(doc.Metadata, schema.MetadataKeyImage)
+			delete(doc.Metadata, schema.MetadataKeyChart)
+		}
+	}
+
+	// 3. Split documents into chunks. The doc type selects the splitter and is inherited by the chunks;
+	// loaders may set it themselves, otherwise it is derived from the path.
+	docType := DocType(path)
+	for _, doc := range initialDocs {
+		if doc.Metadata == nil {
+			doc.Metadata = make(map[string]interface{})
+		}
+		if _, ok := doc.Metadata[schema.MetadataKeyDocType]; !ok {
+			doc.Metadata[schema.MetadataKeyDocType] = docType
+		}
+	}
 	chunks, err := p.splitter.Split(ctx, initialDocs)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Failed to split documents: %v", err))
-		return err
+		return nil, err
 	}
 	progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Split into %d chunks", len(chunks)), Progress: 25}
 
-	// 3. Add multi-tenancy metadata to each chunk
+	// 4. Add multi-tenancy metadata to each chunk, with the numeric fields queries can filter by range
+	indexedAt := time.Now().Unix()
 	for _, chunk := range chunks {
 		if chunk.Metadata == nil {
 			chunk.Metadata = make(map[string]interface{})
 		}
 		chunk.Metadata[vectorstore.FieldUserID] = userID
 		chunk.Metadata[vectorstore.FieldFolderID] = folderID
+		chunk.Metadata[schema.MetadataKeyIndexedAt] = indexedAt
+		if label, ok := chunk.Metadata[schema.MetadataKeyPageLabel].(string); ok {
+			if page, err := strconv.ParseInt(label, 10, 64); err == nil && page > 0 {
+				chunk.Metadata[schema.MetadataKeyPageNumber] = page
+			}
+		}
 	}
 
-	// 4. Embed the chunks
-	texts := make([]string, len(chunks))
-	for i, chunk := range chunks {
-		texts[i] = chunk.Text
+	// 5. Drop chunks that duplicate chunks already stored for the folder or earlier chunks of the source
+	var dedupResult *dedup.Result
+	if p.dedup != nil {
+		dedupResult, err = p.dedup.Check(ctx, userID, folderID, path, chunks)
+		if err != nil {
+			p.log.Error(fmt.Sprintf("Failed to check chunks for duplicates: %v", err))
+			return nil, err
+		}
+		// Repeated chunks of the source itself are dropped; only duplicates of other sources become references.
+		uniqueIDs := make(map[string]bool, len(dedupResult.Unique))
+		for _, chunk := range dedupResult.Unique {
+			uniqueIDs[chunk.ID] = true
+		}
+		for _, id := range dedupResult.DuplicateOf {
+			if !uniqueIDs[id] {
+				result.DuplicateOf = append(result.DuplicateOf, id)
+			}
+		}
+		chunks = dedupResult.Unique
+		progressChan <- &ragv1.IndexResponse{
+			Message:  fmt.Sprintf("Skipped %d duplicate chunks, storing %d", len(dedupResult.DuplicateOf), len(chunks)),
+			Progress: 25,
+		}
 	}
-	embeddings, err := p.embedder.Embed(ctx, texts)
-	if err != nil {
-		p.log.Error(fmt.Sprintf("Failed to embed chunks: %v", err))
-		return err
+
+	// 6. Embed the chunks, reporting progress after every step
+	for start := 0; start < len(chunks); start += embeddingProgressStep {
+		batch := chunks[start:min(start+embeddingProgressStep, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, chunk := range batch {
+			texts[i] = chunk.Text
+		}
+		embeddings, err := p.embedder.Embed(ctx, texts)
+		if err != nil {
+			p.log.Error(fmt.Sprintf("Failed to embed chunks: %v", err))
+			return nil, err
+		}
+		if len(embeddings) != len(batch) {
+			return nil, fmt.Errorf("embedding model returned %d embeddings for %d chunks", len(embeddings), len(batch))
+		}
+		for i, chunk := range batch {
+			chunk.Embedding = embeddings[i]
+		}
+		embedded := start + len(batch)
+		progressChan <- &ragv1.IndexResponse{
+			Message:  fmt.Sprintf("Embedded %d of %d chunks", embedded, len(chunks)),
+			Progress: int32(25 + 35*embedded/len(chunks)),
+		}
 	}
+	progressChan <- &ragv1.IndexResponse{Message: "Successfully embedded all chunks", Progress: 60}
+
+	// 7. Store the chunks, and record their fingerprints so that later sources are checked against them
+	if len(chunks) > 0 {
+		if err := p.storeChunks(ctx, userID, folderID, chunks, progressChan); err != nil {
+			return nil, err
+		}
+	}
+	if dedupResult != nil {
+		if err := p.dedup.Record(ctx, dedupResult); err != nil {
+			// The chunks are stored; only later duplicates of them will not be detected.
+			p.log.Error(fmt.Sprintf("Failed to record chunk fingerprints: %v", err))
+		}
+	}
+
+	result.ChunkIDs = make([]string, len(chunks))
 	for i, chunk := range chunks {
-		chunk.Embedding = embeddings[i]
+		result.ChunkIDs[i] = chunk.ID
 	}
-	progressChan <- &ragv1.IndexResponse{Message: "Successfully embedded all chunks", Progress: 60}
 
-	// 5. Store the chunks concurrently
+	p.log.Info(fmt.Sprintf("Successfully finished indexing for: %s", path))
+	progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Successfully finished indexing for: %s", path), Progress: 100}
+	return result, nil
+}
+
+// storeChunks adds embedded chunks to the doc store, the vector store and the keyword index concurrently. If any of
+// them fails, the chunks are removed from the others.
+func (p *IndexingPipeline) storeChunks(ctx context.Context, userID, folderID string, chunks []*schema.Document, progressChan chan<- *ragv1.IndexResponse) error {
 	eg, gCtx := errgroup.WithContext(ctx)
 
 	// Goroutine for DocStore
@@ -114,11 +249,59 @@ func (p *IndexingPipeline) Run(ctx context.Context, loader interfaces.Loader, pa
 		return nil
 	})
 
+	// Goroutine for the keyword index
+	if p.keywordIndex != nil {
+		eg.Go(func() error {
+			p.log.Info("Adding chunks to keyword index...")
+			if err := p.keywordIndex.Add(gCtx, userID, folderID, chunks); err != nil {
+				p.log.Error(fmt.Sprintf("Failed to add chunks to keyword index: %v", err))
+				return err
+			}
+			return nil
+		})
+	}
+
 	if err := eg.Wait(); err != nil {
+		// Remove chunks that made it into any of the stores so no orphaned vectors or text are left behind.
+		ids := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			ids[i] = chunk.ID
+		}
+		if delErr := p.docStore.Delete(context.Background(), userID, ids); delErr != nil {
+			p.log.Error(fmt.Sprintf("Failed to roll back chunks from DocStore: %v", delErr))
+		}
+		if delErr := p.vectorStore.Delete(context.Background(), ids); delErr != nil {
+			p.log.Error(fmt.Sprintf("Failed to roll back chunks from VectorStore: %v", delErr))
+		}
+		if p.keywordIndex != nil {
+			if delErr := p.keywordIndex.Delete(context.Background(), userID, ids); delErr != nil {
+				p.log.Error(fmt.Sprintf("Failed to roll back chunks from keyword index: %v", delErr))
+			}
+		}
 		return err
 	}
-
-	p.log.Info(fmt.Sprintf("Successfully finished indexing for: %s", path))
-	progressChan <- &ragv1.IndexResponse{Message: fmt.Sprintf("Successfully finished indexing for: %s", path), Progress: 100}
 	return nil
 }
+
+// ContentHash returns the hex encoded SHA-256 of the text of the loaded documents.
+func ContentHash(docs []*schema.Document) string {
+	h := sha256.New()
+	for _, doc := range docs {
+		h.Write([]byte(doc.Text))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DocType derives the doc_type metadata of a source from its path: "web" for URLs, otherwise the
+// lower-cased file extension without the dot, e.g. "pdf".
+func DocType(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return "web"
+	}
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext == "" {
+		return "text"
+	}
+	return ext
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	ragv1 "Jarvis_2.0/api/proto/v1/rag"
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// memoryDocStore is a DocStore keeping chunks in a map.
+type memoryDocStore struct {
+	mu   sync.Mutex
+	docs map[string]*schema.Document
+}
+
+func (s *memoryDocStore) Add(_ context.Context, _ string, docs map[string]*schema.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, doc := range docs {
+		s.docs[id] = doc
+	}
+	return nil
+}
+
+func (s *memoryDocStore) Get(_ context.Context, _ string, ids []string) (map[string]*schema.Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]*schema.Document)
+	for _, id := range ids {
+		if doc, ok := s.docs[id]; ok {
+			result[id] = doc
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryDocStore) Delete(_ context.Context, _ string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+// failingKeywordIndex fails every Add.
+type failingKeywordIndex struct{}
+
+func (failingKeywordIndex) Add(context.Context, string, string, []*schema.Document) error {
+	return errors.New("keyword index unavailable")
+}
+
+func (failingKeywordIndex) Delete(context.Context, string, []string) error { return nil }
+
+func (failingKeywordIndex) Search(context.Context, string, []string, string, int, []schema.Filter) ([]*schema.Document, error) {
+	return nil, nil
+}
+
+func TestStoreChunksRollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	log := *logger.New("test", "", "")
+	docStore := &memoryDocStore{docs: make(map[string]*schema.Document)}
+	vectorStore, err := vectorstore.NewLocalStore("", log)
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	p := NewIndexingPipeline(nil, nil, docStore, vectorStore, failingKeywordIndex{}, nil, nil, log)
+
+	chunks := []*schema.Document{
+		{ID: "c1", Text: "alpha", Embedding: []float32{1, 0}},
+		{ID: "c2", Text: "beta", Embedding: []float32{0, 1}},
+	}
+	if err := p.storeChunks(ctx, "u1", "f1", chunks, make(chan *ragv1.IndexResponse, 10)); err == nil {
+		t.Fatal("storeChunks() succeeded, want the keyword index error")
+	}
+
+	if len(docStore.docs) != 0 {
+		t.Errorf("DocStore kept %d chunks after the rollback", len(docStore.docs))
+	}
+	if docs, _ := vectorStore.Query(ctx, []float32{1, 0}, 10, nil); len(docs) != 0 {
+		t.Errorf("VectorStore kept %d chunks after the rollback", len(docs))
+	}
+}
@@ -0,0 +1,247 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"net/http"
+	"strings"
+
+	"Jarvis_2.0/backend/go/internal/llm"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// Defaults of MediaConfig.
+const (
+	defaultMinImageSize       = 64
+	defaultCaptionConcurrency = 4
+)
+
+// Doc types of the documents created by the MediaExtractor. Tables are split by rows by the default splitter.
+const (
+	docTypeImage = "image"
+	docTypeTable = "table"
+)
+
+// imageExtensions maps the image types the multimodal model accepts to the extension of their stored originals.
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+const captionPrompt = `You are given an image extracted from a document. Describe it so that it can be found by a text search and understood without seeing it.
+- Transcribe all legible text in the image verbatim, including labels, legends and axis titles.
+- For charts and diagrams, state what is shown, the main values and the trends or relations between the parts.
+- For photos and illustrations, describe the subject and any details relevant to the document.
+Answer with the description only, as plain text in the language of the text in the image, or in English if it has no text.`
+
+// MediaConfig configures a MediaExtractor.
+type MediaConfig struct {
+	MinImageSize int // Images narrower or lower than this many pixels, such as icons and rules, are skipped
+	Concurrency  int // Number of images captioned concurrently
+}
+
+// MediaExtractor turns the images and tables loaders attach to documents, under schema.MetadataKeyImage and
+// schema.MetadataKeyChart, into documents of their own that are indexed as separate chunks. Images are captioned by a
+// multimodal LLM and their originals are stored in an ObjectStore; tables are rendered as Markdown. The new
+// documents keep the file name and page label of the document they were found in.
+type MediaExtractor struct {
+	captioner llm.LLM
+	images    interfaces.ObjectStore
+	cfg       MediaConfig
+	log       logger.Logger
+}
+
+// NewMediaExtractor creates a new MediaExtractor. The captioner may receive requests concurrently, so it must not
+// keep a conversation history.
+func NewMediaExtractor(captioner llm.LLM, images interfaces.ObjectStore, cfg MediaConfig, log logger.Logger) *MediaExtractor {
+	if cfg.MinImageSize <= 0 {
+		cfg.MinImageSize = defaultMinImageSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultCaptionConcurrency
+	}
+	return &MediaExtractor{captioner: captioner, images: images, cfg: cfg, log: log}
+}
+
+// imageJob is an image to caption and store.
+type imageJob struct {
+	parent      *schema.Document
+	data        []byte
+	contentType string
+	hash        string
+	doc         *schema.Document // Set once the image is captioned and stored
+}
+
+// Extract returns a document for every image and table attached to docs and removes them from the metadata of docs.
+// Images that appear more than once in the source are captioned once. An image the LLM fails to caption is skipped
+// with a warning; failing to store an image is an error.
+func (e *MediaExtractor) Extract(ctx context.Context, userID string, docs []*schema.Document) ([]*schema.Document, error) {
+	var jobs []*imageJob
+	var tableDocs []*schema.Document
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		images, _ := doc.Metadata[schema.MetadataKeyImage].([][]byte)
+		for _, data := range images {
+			hash := sha256.Sum256(data)
+			key := hex.EncodeToString(hash[:])
+			if seen[key] || !e.captionable(data) {
+				continue
+			}
+			seen[key] = true
+			jobs = append(jobs, &imageJob{parent: doc, data: data, contentType: http.DetectContentType(data), hash: key})
+		}
+
+		tables, _ := doc.Metadata[schema.MetadataKeyChart].([][][]string)
+		for _, table := range tables {
+			if text := markdownTable(table); text != "" {
+				tableDocs = append(tableDocs, mediaDocument(doc, text, docTypeTable))
+			}
+		}
+
+		delete(doc.Metadata, schema.MetadataKeyImage)
+		delete(doc.Metadata, schema.MetadataKeyChart)
+	}
+
+	eg, gCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(e.cfg.Concurrency)
+	for _, job := range jobs {
+		eg.Go(func() error {
+			return e.processImage(gCtx, userID, job)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	var mediaDocs []*schema.Document
+	for _, job := range jobs {
+		if job.doc != nil {
+			mediaDocs = append(mediaDocs, job.doc)
+		}
+	}
+	return append(mediaDocs, tableDocs...), nil
+}
+
+// captionable reports whether an image is of a type the LLM accepts and large enough to carry content.
+func (e *MediaExtractor) captionable(data []byte) bool {
+	if _, ok := imageExtensions[http.DetectContentType(data)]; !ok {
+		return false
+	}
+	// WebP cannot be decoded with the standard library and is not checked for its size.
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return cfg.Width >= e.cfg.MinImageSize && cfg.Height >= e.cfg.MinImageSize
+	}
+	return true
+}
+
+// processImage captions an image and stores its original under a key derived from the user and its content, so
+// that an image indexed again replaces the same object.
+func (e *MediaExtractor) processImage(ctx context.Context, userID string, job *imageJob) error {
+	caption, err := e.caption(ctx, job)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fileName, _ := job.parent.Metadata[schema.MetadataKeyFileName].(string)
+		e.log.Warn(fmt.Sprintf("Failed to caption an image of %s, skipping it: %v", fileName, err))
+		return nil
+	}
+
+	uri, err := e.images.Put(ctx, "images/"+userID+"/"+job.hash+imageExtensions[job.contentType], job.data, job.contentType)
+	if err != nil {
+		return err
+	}
+	job.doc = mediaDocument(job.parent, caption, docTypeImage)
+	job.doc.Metadata[schema.MetadataKeyImageURI] = uri
+	return nil
+}
+
+// caption asks the LLM to describe an image.
+func (e *MediaExtractor) caption(ctx context.Context, job *imageJob) (string, error) {
+	resp, err := e.captioner.GenerateContent(ctx, &models.GenerateContentRequest{
+		Content: []models.Content{
+			{
+				Role: "user",
+				Parts: []*models.Part{
+					{Text: captionPrompt},
+					{InlineData: &models.Blob{MIMEType: job.contentType, Data: job.data}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, content := range resp.Content {
+		for _, part := range content.Parts {
+			if part != nil && !part.Thought {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+	caption := strings.TrimSpace(sb.String())
+	if caption == "" {
+		return "", fmt.Errorf("the LLM returned an empty caption")
+	}
+	return caption, nil
+}
+
+// mediaDocument creates a document for an image or table found in parent.
+func mediaDocument(parent *schema.Document, text, docType string) *schema.Document {
+	doc := &schema.Document{
+		ID:       uuid.New().String(),
+		Text:     text,
+		Metadata: map[string]interface{}{schema.MetadataKeyDocType: docType},
+	}
+	for _, key := range []string{schema.MetadataKeyFileName, schema.MetadataKeyPageLabel, schema.MetadataKeySourceURL} {
+		if value, ok := parent.Metadata[key]; ok {
+			doc.Metadata[key] = value
+		}
+	}
+	return doc
+}
+
+// markdownTable renders rows of cell texts as a Markdown table whose first row is the header. Rows are padded to
+// the widest row. It returns "" for a table without data rows.
+func markdownTable(rows [][]string) string {
+	if len(rows) < 2 {
+		return ""
+	}
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = strings.ReplaceAll(strings.Join(strings.Fields(row[i]), " "), "|", `\|`)
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"sync"
+	"testing"
+
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// fakeCaptioner captions every image with the same text and counts the requests.
+type fakeCaptioner struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (c *fakeCaptioner) GenerateContent(_ context.Context, req *models.GenerateContentRequest) (*models.GenerateContentResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if req.Content[0].Parts[1].InlineData.MIMEType != "image/png" {
+		panic("image sent without its type")
+	}
+	return &models.GenerateContentResponse{Content: []models.Content{{Parts: []*models.Part{{Text: " A bar chart of sales. "}}}}}, nil
+}
+
+func (c *fakeCaptioner) GenerateContentStream(context.Context, *models.GenerateContentRequest) (<-chan *models.GenerateContentResponse, error) {
+	panic("not used")
+}
+
+// memoryObjectStore keeps objects in memory.
+type memoryObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *memoryObjectStore) Put(_ context.Context, key string, data []byte, _ string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	return "mem://" + key, nil
+}
+
+func pngImage(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMediaExtractorExtract(t *testing.T) {
+	captioner := &fakeCaptioner{}
+	store := &memoryObjectStore{objects: make(map[string][]byte)}
+	extractor := NewMediaExtractor(captioner, store, MediaConfig{}, *logger.New("test", "", ""))
+
+	chart, icon := pngImage(t, 200), pngImage(t, 16)
+	docs := []*schema.Document{
+		{Text: "page one", Metadata: map[string]interface{}{
+			schema.MetadataKeyFileName:  "report.pdf",
+			schema.MetadataKeyPageLabel: "1",
+			schema.MetadataKeyImage:     [][]byte{chart, icon},
+			schema.MetadataKeyChart:     [][][]string{{{"Region", "Sales"}, {"North", "12|5"}, {"South"}}},
+		}},
+		// The chart repeated on the next page is captioned once.
+		{Text: "page two", Metadata: map[string]interface{}{
+			schema.MetadataKeyFileName:  "report.pdf",
+			schema.MetadataKeyPageLabel: "2",
+			schema.MetadataKeyImage:     [][]byte{chart},
+		}},
+	}
+
+	mediaDocs, err := extractor.Extract(context.Background(), "u1", docs)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(mediaDocs) != 2 {
+		t.Fatalf("Extract() returned %d documents, want 2", len(mediaDocs))
+	}
+	if captioner.requests != 1 || len(store.objects) != 1 {
+		t.Errorf("captioned %d and stored %d images, want 1 each", captioner.requests, len(store.objects))
+	}
+
+	imageDoc := mediaDocs[0]
+	if imageDoc.Text != "A bar chart of sales." || imageDoc.Metadata[schema.MetadataKeyDocType] != docTypeImage ||
+		imageDoc.Metadata[schema.MetadataKeyPageLabel] != "1" || imageDoc.Metadata[schema.MetadataKeyFileName] != "report.pdf" {
+		t.Errorf("image document = %+v", imageDoc)
+	}
+	uri, _ := imageDoc.Metadata[schema.MetadataKeyImageURI].(string)
+	if stored, ok := store.objects[uri[len("mem://"):]]; !ok || !bytes.Equal(stored, chart) {
+		t.Errorf("image URI %q does not refer to the stored image", uri)
+	}
+
+	table := mediaDocs[1]
+	wantTable := "| Region | Sales |\n| --- | --- |\n| North | 12\\|5 |\n| South |  |"
+	if table.Text != wantTable || table.Metadata[schema.MetadataKeyDocType] != docTypeTable || table.Metadata[schema.MetadataKeyPageLabel] != "1" {
+		t.Errorf("table document = %q %v, want %q", table.Text, table.Metadata, wantTable)
+	}
+
+	for _, doc := range docs {
+		if _, ok := doc.Metadata[schema.MetadataKeyImage]; ok {
+			t.Errorf("images were left in the metadata of %q", doc.Text)
+		}
+	}
+}
@@ -25,7 +25,8 @@ func NewQAPipeline(llm interfaces.LLM, log logger.Logger) *QAPipeline {
 }
 
 // Run takes a query and a list of documents, builds a prompt, and calls the LLM to generate an answer.
-func (p *QAPipeline) Run(ctx context.Context, query string, documents []*schema.Document) (string, error) {
+// The answer cites the documents with inline markers like [1]; markers that do not match a document are removed.
+func (p *QAPipeline) Run(ctx context.Context, query string, documents []*schema.Document) (*Answer, error) {
 	p.log.Info(fmt.Sprintf("Building prompt for query: '%s' with %d documents", query, len(documents)))
 
 	// 1. Build the prompt
@@ -36,22 +37,61 @@ func (p *QAPipeline) Run(ctx context.Context, query string, documents []*schema.
 	answer, err := p.llm.Generate(ctx, prompt)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("LLM failed to generate answer: %v", err))
-		return "", err
+		return nil, err
 	}
 
-	p.log.Info("Successfully generated answer from LLM.")
-	return answer, nil
+	// 3. Keep only the citations that refer to a context document
+	result := extractCitations(answer, documents)
+	p.log.Info(fmt.Sprintf("Successfully generated answer from LLM with %d citations.", len(result.Citations)))
+	return result, nil
+}
+
+// RunStream is like Run but passes the answer to onToken piece by piece as the LLM generates it.
+// If the LLM cannot stream, the whole answer is passed at once. Streamed tokens are the raw LLM output;
+// the returned Answer has its citation markers validated.
+func (p *QAPipeline) RunStream(ctx context.Context, query string, documents []*schema.Document, onToken func(token string) error) (*Answer, error) {
+	streamingLLM, ok := p.llm.(interfaces.StreamingLLM)
+	if !ok {
+		answer, err := p.Run(ctx, query, documents)
+		if err != nil {
+			return nil, err
+		}
+		if err := onToken(answer.Text); err != nil {
+			return nil, err
+		}
+		return answer, nil
+	}
+
+	p.log.Info(fmt.Sprintf("Building prompt for query: '%s' with %d documents", query, len(documents)))
+	prompt := p.buildPrompt(query, documents)
+
+	p.log.Info("Streaming answer from LLM...")
+	answer, err := streamingLLM.GenerateStream(ctx, prompt, onToken)
+	if err != nil {
+		p.log.Error(fmt.Sprintf("LLM failed to stream answer: %v", err))
+		return nil, err
+	}
+
+	result := extractCitations(answer, documents)
+	p.log.Info(fmt.Sprintf("Successfully streamed answer from LLM with %d citations.", len(result.Citations)))
+	return result, nil
 }
 
 // buildPrompt constructs a prompt string from a query and a list of context documents.
 func (p *QAPipeline) buildPrompt(query string, documents []*schema.Document) string {
 	var sb strings.Builder
 
-	sb.WriteString("Based on the following context, please answer the question.\n\nContext:\n")
+	sb.WriteString("Based on the following context, please answer the question.\n")
+	sb.WriteString("Cite the context that supports each statement with its number in square brackets, e.g. [1] or [2, 3], ")
+	sb.WriteString("placed right after the statement. Only cite context numbers listed below, and do not cite context you did not use.\n\nContext:\n")
 
 	for i, doc := range documents {
 		sb.WriteString("---\n")
-		sb.WriteString(fmt.Sprintf("Context %d:\n%s\n", i+1, doc.Text))
+		if label := sourceLabel(doc); label != "" {
+			sb.WriteString(fmt.Sprintf("Context %d (%s):\n%s\n", i+1, label, doc.Text))
+		} else {
+			sb.WriteString(fmt.Sprintf("Context %d:\n%s\n", i+1, doc.Text))
+		}
 	}
 
 	sb.WriteString("---\n\n")
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxHistoryTurns bounds how much of the conversation is given to the LLM when rewriting a follow-up.
+const maxHistoryTurns = 10
+
+// listMarkerRegex matches the numbering or bullet models like to put in front of list items, e.g. "1. " or "- ".
+var listMarkerRegex = regexp.MustCompile(`^(?:\d+[.)]|[-*•])\s+`)
+
+// ChatTurn is a previous message in a conversation.
+type ChatTurn struct {
+	Role    string // "user" or "assistant"
+	Content string
+}
+
+// QueryExpansion selects additional queries to retrieve with.
+type QueryExpansion int
+
+const (
+	// ExpansionNone retrieves with the query only.
+	ExpansionNone QueryExpansion = iota
+	// ExpansionMultiQuery also retrieves with LLM-generated paraphrases of the query.
+	ExpansionMultiQuery
+	// ExpansionHyDE also searches the vector store with the embedding of an LLM-written hypothetical answer.
+	ExpansionHyDE
+)
+
+// rewriteQuery turns a follow-up question into a standalone question using the conversation history.
+// Without history the query is returned unchanged.
+func (p *RetrievalPipeline) rewriteQuery(ctx context.Context, history []ChatTurn, query string) (string, error) {
+	if len(history) == 0 {
+		return query, nil
+	}
+	if len(history) > maxHistoryTurns {
+		history = history[len(history)-maxHistoryTurns:]
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Given the following conversation and a follow-up question, rewrite the follow-up question ")
+	sb.WriteString("as a standalone question that can be understood without the conversation. ")
+	sb.WriteString("Resolve pronouns and references such as \"it\" or \"the second one\" to what they refer to, ")
+	sb.WriteString("and keep the language of the follow-up question. Reply with the standalone question only.\n\nConversation:\n")
+	for _, turn := range history {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", turn.Role, turn.Content))
+	}
+	sb.WriteString(fmt.Sprintf("\nFollow-up question: %s\n\nStandalone question:", query))
+
+	rewritten, err := p.llm.Generate(ctx, sb.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrite query: %w", err)
+	}
+	rewritten = strings.TrimSpace(rewritten)
+	if rewritten == "" {
+		return query, nil
+	}
+	return rewritten, nil
+}
+
+// queryVariants asks the LLM for n paraphrases of query that may match differently worded documents.
+func (p *RetrievalPipeline) queryVariants(ctx context.Context, query string, n int) ([]string, error) {
+	prompt := fmt.Sprintf("Write %d different versions of the following question to retrieve relevant documents "+
+		"from a search engine. Vary the wording and use synonyms, but keep the meaning. "+
+		"Reply with one question per line and nothing else.\n\nQuestion: %s", n, query)
+
+	response, err := p.llm.Generate(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query variants: %w", err)
+	}
+
+	var variants []string
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(listMarkerRegex.ReplaceAllString(strings.TrimSpace(line), ""))
+		if line == "" || strings.EqualFold(line, query) {
+			continue
+		}
+		variants = append(variants, line)
+		if len(variants) == n {
+			break
+		}
+	}
+	return variants, nil
+}
+
+// hypotheticalDocument asks the LLM for a passage answering query (HyDE). The passage is only used for its
+// embedding, which tends to lie closer to the relevant chunks than the embedding of the short question.
+func (p *RetrievalPipeline) hypotheticalDocument(ctx context.Context, query string) (string, error) {
+	prompt := fmt.Sprintf("Write a short passage, like one found in a document, that answers the following question. "+
+		"Reply with the passage only.\n\nQuestion: %s", query)
+
+	passage, err := p.llm.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate hypothetical document: %w", err)
+	}
+	return strings.TrimSpace(passage), nil
+}
@@ -12,93 +12,163 @@ import (
 
 // RetrievalPipeline orchestrates the process of retrieving relevant documents for a given query.
 type RetrievalPipeline struct {
-	embedder    interfaces.EmbeddingModel
-	vectorStore interfaces.VectorStore
-	docStore    interfaces.DocStore
-	reranker    interfaces.Reranker // Optional component to rerank results
-	log         logger.Logger
+	embedder     interfaces.EmbeddingModel
+	vectorStore  interfaces.VectorStore
+	docStore     interfaces.DocStore
+	keywordIndex interfaces.KeywordIndex // Optional keyword index for hybrid retrieval
+	reranker     interfaces.Reranker     // Optional component to rerank results
+	llm          interfaces.LLM          // Optional LLM for query rewriting and expansion
+	graph        *GraphRetriever         // Optional knowledge graph expansion; needs the LLM
+	log          logger.Logger
 }
 
 // NewRetrievalPipeline creates a new RetrievalPipeline.
-// The reranker is optional and can be nil.
+// The keyword index, the reranker, the LLM and the graph retriever are optional and can be nil. Without an LLM,
+// conversation history, query expansion and the knowledge graph are ignored.
 func NewRetrievalPipeline(
 	embedder interfaces.EmbeddingModel,
 	vectorStore interfaces.VectorStore,
 	docStore interfaces.DocStore,
+	keywordIndex interfaces.KeywordIndex,
 	reranker interfaces.Reranker,
+	llm interfaces.LLM,
+	graph *GraphRetriever,
 	log logger.Logger,
 ) *RetrievalPipeline {
 	return &RetrievalPipeline{
-		embedder:    embedder,
-		vectorStore: vectorStore,
-		docStore:    docStore,
-		reranker:    reranker,
-		log:         log,
+		embedder:     embedder,
+		vectorStore:  vectorStore,
+		docStore:     docStore,
+		keywordIndex: keywordIndex,
+		reranker:     reranker,
+		llm:          llm,
+		graph:        graph,
+		log:          log,
 	}
 }
 
+// RetrievalRequest describes what to retrieve and for whom.
+type RetrievalRequest struct {
+	Query     string
+	History   []ChatTurn // Earlier turns of the conversation, oldest first
+	UserID    string
+	FolderIDs []string        // Restricts results to these folders, if any
+	Filters   []schema.Filter // Metadata filters every result must match
+	TopK      int
+	Weights   HybridWeights
+	Expansion QueryExpansion
+	// NumVariants is the number of paraphrases generated for ExpansionMultiQuery.
+	NumVariants int
+	// Graph adds the knowledge graph facts about the entities of the query as an extra document, after the
+	// retrieved chunks. It is ignored if the pipeline has no graph retriever.
+	Graph bool
+}
+
+// RetrievalResult holds the retrieved documents and the query they were retrieved for.
+type RetrievalResult struct {
+	// StandaloneQuery is the query rewritten to be understood without the conversation history,
+	// or the original query if there was no history. Answers should be generated for this query.
+	StandaloneQuery string
+	Documents       []*schema.Document
+}
+
 // Run executes the retrieval pipeline with multi-tenancy filters.
-func (p *RetrievalPipeline) Run(ctx context.Context, query, userID string, folderIDs []string, topK int) ([]*schema.Document, error) {
-	p.log.Info(fmt.Sprintf("Starting retrieval for query: '%s' for user: %s", query, userID))
+// Results are restricted to req.FolderIDs, if any, and to chunks matching all metadata filters.
+// A follow-up query is first rewritten into a standalone query using the history, and may be expanded into
+// several queries. Vector and keyword results of all queries are merged with reciprocal-rank fusion according to
+// req.Weights; keyword search is skipped if no keyword index is configured.
+// Failures of the LLM steps are logged and retrieval falls back to the queries available. Knowledge graph facts
+// are added last if req.Graph is set; if their lookup fails, the chunks are returned without them.
+func (p *RetrievalPipeline) Run(ctx context.Context, req RetrievalRequest) (*RetrievalResult, error) {
+	p.log.Info(fmt.Sprintf("Starting retrieval for query: '%s' for user: %s", req.Query, req.UserID))
 
-	// 1. Embed the query
-	queryEmbeddings, err := p.embedder.Embed(ctx, []string{query})
-	if err != nil || len(queryEmbeddings) == 0 {
-		p.log.Error(fmt.Sprintf("Failed to embed query: %v", err))
-		return nil, fmt.Errorf("failed to embed query: %w", err)
+	weights := req.Weights
+	if p.keywordIndex == nil {
+		weights.Keyword = 0
 	}
-	p.log.Info("Successfully embedded query")
 
-	// 2. Construct filters for multi-tenancy
-	filters := map[string]interface{}{
-		vectorstore.FieldUserID: userID,
+	// 1. Rewrite a follow-up into a standalone query and expand it
+	result := &RetrievalResult{StandaloneQuery: req.Query, Documents: []*schema.Document{}}
+	vectorQueries, keywordQueries := []string{req.Query}, []string{req.Query}
+	if p.llm != nil {
+		standalone, err := p.rewriteQuery(ctx, req.History, req.Query)
+		if err != nil {
+			p.log.Warn(fmt.Sprintf("%v. Retrieving with the original query.", err))
+		} else if standalone != req.Query {
+			p.log.Info(fmt.Sprintf("Rewrote follow-up query to: '%s'", standalone))
+			result.StandaloneQuery = standalone
+		}
+		vectorQueries, keywordQueries = p.expandQuery(ctx, result.StandaloneQuery, req.Expansion, req.NumVariants)
 	}
-	if len(folderIDs) > 0 {
-		// This requires the buildFilterExpression to handle IN clauses
-		// For simplicity in this example, we will filter by the first folder ID.
-		// A production implementation should handle multiple folders.
-		filters[vectorstore.FieldFolderID] = folderIDs[0]
+
+	// 2. Query the VectorStore to get document IDs and preliminary metadata
+	var lists []rankedList
+	if weights.Vector > 0 {
+		vectorLists, err := p.vectorSearch(ctx, vectorQueries, req.UserID, req.FolderIDs, req.Filters, req.TopK)
+		if err != nil {
+			return nil, err
+		}
+		for _, docs := range vectorLists {
+			p.log.Info(fmt.Sprintf("Retrieved %d document candidates from vector store", len(docs)))
+			lists = append(lists, rankedList{docs: docs, weight: weights.Vector})
+		}
 	}
 
-	// 3. Query the VectorStore to get document IDs and preliminary metadata
-	retrievedDocs, err := p.vectorStore.Query(ctx, queryEmbeddings[0], topK, filters)
-	if err != nil {
-		p.log.Error(fmt.Sprintf("Failed to query vector store: %v", err))
-		return nil, err
+	// 3. Query the keyword index for exact term matches
+	if weights.Keyword > 0 {
+		for _, query := range keywordQueries {
+			docs, err := p.keywordIndex.Search(ctx, req.UserID, req.FolderIDs, query, req.TopK, req.Filters)
+			if err != nil {
+				p.log.Error(fmt.Sprintf("Failed to query keyword index: %v", err))
+				return nil, err
+			}
+			p.log.Info(fmt.Sprintf("Retrieved %d document candidates from keyword index", len(docs)))
+			lists = append(lists, rankedList{docs: docs, weight: weights.Keyword})
+		}
 	}
+
+	// 4. Fuse all result lists
+	retrievedDocs := reciprocalRankFusion(lists, req.TopK)
 	if len(retrievedDocs) == 0 {
-		p.log.Info("No documents found in vector store for the given query.")
-		return []*schema.Document{}, nil
+		p.log.Info("No documents found for the given query.")
+		result.Documents = p.withGraphFacts(ctx, req, result.StandaloneQuery, result.Documents)
+		return result, nil
 	}
-	p.log.Info(fmt.Sprintf("Retrieved %d document candidates from vector store", len(retrievedDocs)))
 
-	// 4. Enrich the results with full text from the DocStore
+	// 5. Enrich the results with full text from the DocStore
 	ids := make([]string, len(retrievedDocs))
 	for i, doc := range retrievedDocs {
 		ids[i] = doc.ID
 	}
 
-	fullDocsMap, err := p.docStore.Get(ctx, userID, ids)
+	fullDocsMap, err := p.docStore.Get(ctx, req.UserID, ids)
 	if err != nil {
 		p.log.Error(fmt.Sprintf("Failed to get full documents from doc store: %v", err))
 		return nil, err
 	}
 
-	// 5. Combine information into a final list
+	// 6. Combine information into a final list
 	finalDocs := make([]*schema.Document, 0, len(retrievedDocs))
 	for _, retrievedDoc := range retrievedDocs {
 		if fullDoc, ok := fullDocsMap[retrievedDoc.ID]; ok {
-			fullDoc.Metadata = retrievedDoc.Metadata
+			// Keep the chunk metadata from the DocStore (e.g. chunk_number) and overlay what the retriever returned.
+			if fullDoc.Metadata == nil {
+				fullDoc.Metadata = make(map[string]interface{}, len(retrievedDoc.Metadata))
+			}
+			for k, v := range retrievedDoc.Metadata {
+				fullDoc.Metadata[k] = v
+			}
+			fullDoc.Score = retrievedDoc.Score
 			finalDocs = append(finalDocs, fullDoc)
 		} else {
-			p.log.Warn(fmt.Sprintf("Could not find full document for ID: %s in doc store for user %s", retrievedDoc.ID, userID))
+			p.log.Warn(fmt.Sprintf("Could not find full document for ID: %s in doc store for user %s", retrievedDoc.ID, req.UserID))
 		}
 	}
 
-	// 6. Rerank the results if a reranker is configured
+	// 7. Rerank the results against the standalone query if a reranker is configured
 	if p.reranker != nil {
 		p.log.Info("Reranking documents...")
-		rerankedDocs, err := p.reranker.Rerank(ctx, query, finalDocs)
+		rerankedDocs, err := p.reranker.Rerank(ctx, result.StandaloneQuery, finalDocs)
 		if err != nil {
 			p.log.Warn(fmt.Sprintf("Reranker failed: %v. Returning documents without reranking.", err))
 		} else {
@@ -108,5 +178,70 @@ func (p *RetrievalPipeline) Run(ctx context.Context, query, userID string, folde
 	}
 
 	p.log.Info(fmt.Sprintf("Successfully retrieved and enriched %d documents", len(finalDocs)))
-	return finalDocs, nil
+
+	// 8. Add facts about the entities of the query from the knowledge graph
+	result.Documents = p.withGraphFacts(ctx, req, result.StandaloneQuery, finalDocs)
+	return result, nil
+}
+
+// expandQuery returns the queries to search the vector store and the keyword index with for the given expansion.
+// Both start with query itself. Hypothetical documents are only embedded, as their wording is made up.
+func (p *RetrievalPipeline) expandQuery(ctx context.Context, query string, expansion QueryExpansion, numVariants int) (vectorQueries, keywordQueries []string) {
+	vectorQueries, keywordQueries = []string{query}, []string{query}
+
+	switch expansion {
+	case ExpansionMultiQuery:
+		variants, err := p.queryVariants(ctx, query, numVariants)
+		if err != nil {
+			p.log.Warn(fmt.Sprintf("%v. Retrieving without query variants.", err))
+			break
+		}
+		p.log.Info(fmt.Sprintf("Generated %d query variants", len(variants)))
+		vectorQueries = append(vectorQueries, variants...)
+		keywordQueries = append(keywordQueries, variants...)
+	case ExpansionHyDE:
+		passage, err := p.hypotheticalDocument(ctx, query)
+		if err != nil {
+			p.log.Warn(fmt.Sprintf("%v. Retrieving without a hypothetical document.", err))
+			break
+		}
+		if passage != "" {
+			p.log.Info("Generated hypothetical document for the query")
+			vectorQueries = append(vectorQueries, passage)
+		}
+	}
+	return vectorQueries, keywordQueries
+}
+
+// vectorSearch embeds the queries in one batch and searches the vector store within the user's folders,
+// returning one result list per query.
+func (p *RetrievalPipeline) vectorSearch(ctx context.Context, queries []string, userID string, folderIDs []string, filters []schema.Filter, topK int) ([][]*schema.Document, error) {
+	queryEmbeddings, err := p.embedder.Embed(ctx, queries)
+	if err != nil {
+		p.log.Error(fmt.Sprintf("Failed to embed query: %v", err))
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(queryEmbeddings) != len(queries) {
+		p.log.Error(fmt.Sprintf("Embedder returned %d vectors for %d queries", len(queryEmbeddings), len(queries)))
+		return nil, fmt.Errorf("embedder returned %d vectors for %d queries", len(queryEmbeddings), len(queries))
+	}
+	p.log.Info(fmt.Sprintf("Successfully embedded %d queries", len(queries)))
+
+	// Construct filters for multi-tenancy, followed by the caller's metadata filters
+	tenantFilters := []schema.Filter{schema.Eq(vectorstore.FieldUserID, userID)}
+	if len(folderIDs) > 0 {
+		tenantFilters = append(tenantFilters, schema.In(vectorstore.FieldFolderID, folderIDs...))
+	}
+	allFilters := append(tenantFilters, filters...)
+
+	lists := make([][]*schema.Document, 0, len(queryEmbeddings))
+	for _, embedding := range queryEmbeddings {
+		docs, err := p.vectorStore.Query(ctx, embedding, topK, allFilters)
+		if err != nil {
+			p.log.Error(fmt.Sprintf("Failed to query vector store: %v", err))
+			return nil, err
+		}
+		lists = append(lists, docs)
+	}
+	return lists, nil
 }
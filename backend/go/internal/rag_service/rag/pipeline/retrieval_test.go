@@ -0,0 +1,24 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+// shortEmbedder returns one vector less than requested, without an error.
+type shortEmbedder struct{}
+
+func (shortEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	return make([][]float32, len(texts)-1), nil
+}
+
+func TestVectorSearchEmbeddingCountMismatch(t *testing.T) {
+	p := NewRetrievalPipeline(shortEmbedder{}, nil, nil, nil, nil, nil, nil, *logger.New("test", "", ""))
+	_, err := p.vectorSearch(context.Background(), []string{"a", "b"}, "u1", nil, nil, 5)
+	if err == nil || !strings.Contains(err.Error(), "returned 1 vectors for 2 queries") {
+		t.Errorf("vectorSearch() error = %v, want a length mismatch", err)
+	}
+}
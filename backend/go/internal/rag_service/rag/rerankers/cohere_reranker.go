@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"sort"
 )
 
 const cohereRerankURL = "https://api.cohere.ai/v1/rerank"
@@ -106,19 +105,13 @@ func (r *CohereReranker) Rerank(ctx context.Context, query string, docs []*schem
 			// Get the original document
 			originalDoc := docs[result.Index]
 			// Update its score
-			originalDoc.Metadata["score"] = result.RelevanceScore
+			originalDoc.Score = result.RelevanceScore
 			rerankedDocs = append(rerankedDocs, originalDoc)
 		}
 	}
 
 	// Sort by the new score in descending order
-	sort.Slice(rerankedDocs, func(i, j int) bool {
-		iScore, _ := rerankedDocs[i].Metadata["score"].(float64)
-		jScore, _ := rerankedDocs[j].Metadata["score"].(float64)
-		return iScore > jScore
-	})
-
-	return rerankedDocs, nil
+	return sortByScore(rerankedDocs, 0), nil
 }
 
 // compile-time check to ensure CohereReranker implements the Reranker interface
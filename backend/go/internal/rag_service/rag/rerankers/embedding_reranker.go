@@ -0,0 +1,44 @@
+package rerankers
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+)
+
+// EmbeddingReranker re-scores documents by the cosine similarity between their embedding and the query's.
+// It needs no service beyond the embedding model already used for indexing.
+type EmbeddingReranker struct {
+	embedder interfaces.EmbeddingModel
+	topN     int
+}
+
+// NewEmbeddingReranker creates a new EmbeddingReranker that keeps the topN best documents.
+func NewEmbeddingReranker(embedder interfaces.EmbeddingModel, topN int) *EmbeddingReranker {
+	return &EmbeddingReranker{
+		embedder: embedder,
+		topN:     topN,
+	}
+}
+
+// Rerank re-orders the documents by cosine similarity to the query and stores it as their score.
+func (r *EmbeddingReranker) Rerank(ctx context.Context, query string, docs []*schema.Document) ([]*schema.Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	queryVector, docVectors, err := embedQueryAndDocs(ctx, r.embedder, query, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]*schema.Document, len(docs))
+	for i, doc := range docs {
+		doc.Score = cosineSimilarity(queryVector, docVectors[i])
+		reranked[i] = doc
+	}
+	return sortByScore(reranked, r.topN), nil
+}
+
+// compile-time check to ensure EmbeddingReranker implements the Reranker interface
+var _ interfaces.Reranker = (*EmbeddingReranker)(nil)
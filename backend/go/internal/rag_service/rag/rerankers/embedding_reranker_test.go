@@ -0,0 +1,124 @@
+package rerankers
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// fakeEmbedder returns fixed vectors by text. Unknown texts fail the call.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	drop    bool // Return one vector less than requested
+}
+
+func (e *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector, ok := e.vectors[text]
+		if !ok {
+			return nil, errors.New("unknown text " + text)
+		}
+		vectors = append(vectors, vector)
+	}
+	if e.drop {
+		vectors = vectors[1:]
+	}
+	return vectors, nil
+}
+
+func textDocs(texts ...string) []*schema.Document {
+	docs := make([]*schema.Document, len(texts))
+	for i, text := range texts {
+		docs[i] = &schema.Document{ID: text, Text: text}
+	}
+	return docs
+}
+
+func docIDs(docs []*schema.Document) []string {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids
+}
+
+func TestEmbeddingRerankerRerank(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"query":      {1, 0},
+		"same":       {2, 0},
+		"close":      {1, 1},
+		"orthogonal": {0, 1},
+		"opposite":   {-1, 0},
+	}}
+
+	tests := []struct {
+		name string
+		topN int
+		docs []string
+		want []string
+	}{
+		{"by similarity", 0, []string{"orthogonal", "close", "opposite", "same"}, []string{"same", "close", "orthogonal", "opposite"}},
+		{"top n", 2, []string{"orthogonal", "close", "opposite", "same"}, []string{"same", "close"}},
+		{"empty", 3, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewEmbeddingReranker(embedder, tt.topN).Rerank(context.Background(), "query", textDocs(tt.docs...))
+			if err != nil {
+				t.Fatalf("Rerank() error = %v", err)
+			}
+			if ids := docIDs(got); !slices.Equal(ids, tt.want) {
+				t.Errorf("Rerank() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	got, _ := NewEmbeddingReranker(embedder, 0).Rerank(context.Background(), "query", textDocs("close"))
+	if math.Abs(got[0].Score-math.Sqrt2/2) > 1e-9 {
+		t.Errorf("Score = %v, want the cosine similarity %v", got[0].Score, math.Sqrt2/2)
+	}
+}
+
+func TestEmbeddingRerankerErrors(t *testing.T) {
+	vectors := map[string][]float32{"query": {1, 0}, "doc": {1, 0}}
+	tests := []struct {
+		name     string
+		embedder *fakeEmbedder
+	}{
+		{"embedding fails", &fakeEmbedder{vectors: map[string][]float32{"query": {1, 0}}}},
+		{"missing vectors", &fakeEmbedder{vectors: vectors, drop: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEmbeddingReranker(tt.embedder, 0).Rerank(context.Background(), "query", textDocs("doc")); err == nil {
+				t.Error("Rerank() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{"parallel", []float32{1, 2}, []float32{2, 4}, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 3}, 0},
+		{"opposite", []float32{1, 1}, []float32{-1, -1}, -1},
+		{"zero vector", []float32{0, 0}, []float32{1, 0}, 0},
+		{"different lengths", []float32{1}, []float32{1, 0}, 0},
+		{"empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("cosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
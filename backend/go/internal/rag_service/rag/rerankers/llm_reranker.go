@@ -0,0 +1,90 @@
+package rerankers
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// llmRerankPrompt asks the model to grade every passage in a single call.
+const llmRerankPrompt = `You are a relevance judge for a search engine.
+Rate how well each passage below answers the query on a scale from 0 (irrelevant) to 10 (fully answers it).
+
+Query: %s
+
+%s
+Respond with only a JSON array of %d numbers, one score per passage in the order given, e.g. [7, 0, 3].`
+
+// maxJudgedPassageChars bounds the length of each passage shown to the judge to keep the prompt small.
+const maxJudgedPassageChars = 2000
+
+// LLMReranker uses an LLM as a judge to score the relevance of each document to the query.
+type LLMReranker struct {
+	llm  interfaces.LLM
+	topN int
+}
+
+// NewLLMReranker creates a new LLMReranker that keeps the topN best documents.
+func NewLLMReranker(llm interfaces.LLM, topN int) *LLMReranker {
+	return &LLMReranker{
+		llm:  llm,
+		topN: topN,
+	}
+}
+
+// Rerank re-orders the documents by the judge's scores, normalised to [0, 1], and stores them as their score.
+func (r *LLMReranker) Rerank(ctx context.Context, query string, docs []*schema.Document) ([]*schema.Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	var passages strings.Builder
+	for i, doc := range docs {
+		text := doc.Text
+		if len(text) > maxJudgedPassageChars {
+			text = strings.ToValidUTF8(text[:maxJudgedPassageChars], "")
+		}
+		fmt.Fprintf(&passages, "Passage %d:\n%s\n\n", i+1, text)
+	}
+
+	answer, err := r.llm.Generate(ctx, fmt.Sprintf(llmRerankPrompt, query, passages.String(), len(docs)))
+	if err != nil {
+		return nil, fmt.Errorf("llm judge failed: %w", err)
+	}
+	scores, err := parseJudgeScores(answer, len(docs))
+	if err != nil {
+		return nil, err
+	}
+
+	reranked := make([]*schema.Document, len(docs))
+	for i, doc := range docs {
+		doc.Score = scores[i] / 10
+		reranked[i] = doc
+	}
+	return sortByScore(reranked, r.topN), nil
+}
+
+// parseJudgeScores extracts the JSON array of scores from the model's answer, tolerating surrounding text
+// such as markdown code fences.
+func parseJudgeScores(answer string, want int) ([]float64, error) {
+	start := strings.Index(answer, "[")
+	end := strings.LastIndex(answer, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("llm judge returned no score array: %q", answer)
+	}
+
+	var scores []float64
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &scores); err != nil {
+		return nil, fmt.Errorf("failed to parse llm judge scores: %w", err)
+	}
+	if len(scores) != want {
+		return nil, fmt.Errorf("llm judge returned %d scores for %d passages", len(scores), want)
+	}
+	return scores, nil
+}
+
+// compile-time check to ensure LLMReranker implements the Reranker interface
+var _ interfaces.Reranker = (*LLMReranker)(nil)
@@ -0,0 +1,84 @@
+package rerankers
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"math"
+)
+
+// MMRReranker implements maximal marginal relevance: it greedily picks the document that is most similar to the
+// query while least similar to the documents already picked, so near-duplicate chunks do not crowd out the context.
+type MMRReranker struct {
+	embedder interfaces.EmbeddingModel
+	lambda   float64
+	topN     int
+}
+
+// NewMMRReranker creates a new MMRReranker. lambda in [0, 1] trades relevance (1) against diversity (0).
+func NewMMRReranker(embedder interfaces.EmbeddingModel, lambda float64, topN int) *MMRReranker {
+	return &MMRReranker{
+		embedder: embedder,
+		lambda:   lambda,
+		topN:     topN,
+	}
+}
+
+// Rerank returns the documents in MMR selection order. Each document's score is its MMR value when it was picked.
+func (r *MMRReranker) Rerank(ctx context.Context, query string, docs []*schema.Document) ([]*schema.Document, error) {
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	queryVector, docVectors, err := embedQueryAndDocs(ctx, r.embedder, query, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := len(docs)
+	if r.topN > 0 && r.topN < limit {
+		limit = r.topN
+	}
+
+	relevance := make([]float64, len(docs))
+	for i := range docs {
+		relevance[i] = cosineSimilarity(queryVector, docVectors[i])
+	}
+	// maxSimilarity[i] is the highest similarity of document i to any selected document.
+	maxSimilarity := make([]float64, len(docs))
+	for i := range maxSimilarity {
+		maxSimilarity[i] = math.Inf(-1)
+	}
+	selected := make([]bool, len(docs))
+
+	reranked := make([]*schema.Document, 0, limit)
+	for len(reranked) < limit {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range docs {
+			if selected[i] {
+				continue
+			}
+			redundancy := 0.0
+			if len(reranked) > 0 {
+				redundancy = maxSimilarity[i]
+			}
+			score := r.lambda*relevance[i] - (1-r.lambda)*redundancy
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		selected[best] = true
+		docs[best].Score = bestScore
+		reranked = append(reranked, docs[best])
+		for i := range docs {
+			if !selected[i] {
+				maxSimilarity[i] = math.Max(maxSimilarity[i], cosineSimilarity(docVectors[i], docVectors[best]))
+			}
+		}
+	}
+	return reranked, nil
+}
+
+// compile-time check to ensure MMRReranker implements the Reranker interface
+var _ interfaces.Reranker = (*MMRReranker)(nil)
@@ -0,0 +1,61 @@
+package rerankers
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestMMRRerankerRerank(t *testing.T) {
+	// "first" and "duplicate" are nearly identical and both very relevant; "different" is less relevant but adds
+	// diversity.
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"query":     {1, 1},
+		"first":     {1, 0.9},
+		"duplicate": {1, 0.85},
+		"different": {0.2, 1},
+	}}
+	docs := []string{"different", "duplicate", "first"}
+
+	tests := []struct {
+		name   string
+		lambda float64
+		topN   int
+		want   []string
+	}{
+		{"pure relevance", 1, 0, []string{"first", "duplicate", "different"}},
+		{"balanced prefers diversity", 0.5, 0, []string{"first", "different", "duplicate"}},
+		{"top n", 0.5, 2, []string{"first", "different"}},
+		{"top n beyond the documents", 0.5, 10, []string{"first", "different", "duplicate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMMRReranker(embedder, tt.lambda, tt.topN).Rerank(context.Background(), "query", textDocs(docs...))
+			if err != nil {
+				t.Fatalf("Rerank() error = %v", err)
+			}
+			if ids := docIDs(got); !slices.Equal(ids, tt.want) {
+				t.Errorf("Rerank() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestMMRRerankerScores(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{"query": {1, 0}, "a": {1, 0}, "b": {0, 1}}}
+	got, err := NewMMRReranker(embedder, 0.5, 0).Rerank(context.Background(), "query", textDocs("b", "a"))
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	// The first pick has no redundancy; the second is orthogonal to both the query and the first pick.
+	if !slices.Equal(docIDs(got), []string{"a", "b"}) || got[0].Score != 0.5 || got[1].Score != 0 {
+		t.Errorf("Rerank() = %v with scores %v, %v, want [a b] with scores 0.5, 0", docIDs(got), got[0].Score, got[1].Score)
+	}
+}
+
+func TestMMRRerankerEmbeddingError(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{"query": {1, 0}}}
+	if _, err := NewMMRReranker(embedder, 0.5, 0).Rerank(context.Background(), "query", textDocs("unknown")); err == nil {
+		t.Error("Rerank() succeeded, want an error")
+	}
+}
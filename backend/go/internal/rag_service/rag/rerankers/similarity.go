@@ -0,0 +1,57 @@
+package rerankers
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// cosineSimilarity returns the cosine of the angle between two vectors, or 0 if either is empty or zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// embedQueryAndDocs embeds the query and the documents' text in a single call.
+// It returns the query vector followed by one vector per document.
+func embedQueryAndDocs(ctx context.Context, embedder interfaces.EmbeddingModel, query string, docs []*schema.Document) ([]float32, [][]float32, error) {
+	texts := make([]string, 0, len(docs)+1)
+	texts = append(texts, query)
+	for _, doc := range docs {
+		texts = append(texts, doc.Text)
+	}
+
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to embed query and documents: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	return vectors[0], vectors[1:], nil
+}
+
+// sortByScore orders documents by descending score and keeps at most topN of them; topN <= 0 keeps all.
+func sortByScore(docs []*schema.Document, topN int) []*schema.Document {
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].Score > docs[j].Score
+	})
+	if topN > 0 && len(docs) > topN {
+		docs = docs[:topN]
+	}
+	return docs
+}
@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FilterOp is the kind of predicate a Filter applies.
+type FilterOp string
+
+const (
+	// FilterOpEq matches chunks whose field equals Values[0].
+	FilterOpEq FilterOp = "eq"
+	// FilterOpIn matches chunks whose field equals any of Values.
+	FilterOpIn FilterOp = "in"
+	// FilterOpRange matches chunks whose field lies within the bounds that are set.
+	FilterOpRange FilterOp = "range"
+)
+
+// Filter is a predicate over a scalar metadata field of a chunk. Filters passed together are combined with AND.
+type Filter struct {
+	Field  string
+	Op     FilterOp
+	Values []string
+
+	// Numeric marks a field stored as an Int64, such as a page number or an indexing time. Values and bounds of a
+	// numeric filter must be decimal integers and are compared as numbers. Only numeric fields can be filtered by
+	// range, since string order would put "10" before "9".
+	Numeric bool
+
+	// Range bounds; nil bounds are open.
+	Gt, Gte, Lt, Lte *string
+}
+
+// Eq returns a filter matching chunks whose field equals value.
+func Eq(field, value string) Filter {
+	return Filter{Field: field, Op: FilterOpEq, Values: []string{value}}
+}
+
+// In returns a filter matching chunks whose field equals any of values.
+func In(field string, values ...string) Filter {
+	return Filter{Field: field, Op: FilterOpIn, Values: values}
+}
+
+// Validate reports filters that no store can apply, such as an eq filter without a value, a range without bounds
+// or an unknown op. Stores reject them rather than dropping the predicate.
+func (f Filter) Validate() error {
+	switch f.Op {
+	case FilterOpEq:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("eq filter on %q has no value", f.Field)
+		}
+	case FilterOpIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("in filter on %q has no values", f.Field)
+		}
+	case FilterOpRange:
+		if f.Gt == nil && f.Gte == nil && f.Lt == nil && f.Lte == nil {
+			return fmt.Errorf("range filter on %q has no bounds", f.Field)
+		}
+		if !f.Numeric {
+			return fmt.Errorf("range filter on %q needs a numeric field", f.Field)
+		}
+	default:
+		return fmt.Errorf("filter on %q has unknown op %q", f.Field, f.Op)
+	}
+	if f.Numeric {
+		for _, v := range f.operands() {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				return fmt.Errorf("filter on numeric field %q has non-integer value %q", f.Field, v)
+			}
+		}
+	}
+	return nil
+}
+
+// operands returns the values and set range bounds of the filter.
+func (f Filter) operands() []string {
+	operands := append([]string(nil), f.Values...)
+	for _, b := range []*string{f.Gt, f.Gte, f.Lt, f.Lte} {
+		if b != nil {
+			operands = append(operands, *b)
+		}
+	}
+	return operands
+}
+
+// Match reports whether value satisfies the filter. Invalid filters match nothing, and neither does a value of a
+// numeric filter that is not an integer.
+func (f Filter) Match(value string) bool {
+	if f.Validate() != nil {
+		return false
+	}
+	if f.Numeric {
+		return f.matchNumber(value)
+	}
+	switch f.Op {
+	case FilterOpEq:
+		return len(f.Values) > 0 && value == f.Values[0]
+	case FilterOpIn:
+		for _, v := range f.Values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// matchNumber is Match for a valid numeric filter.
+func (f Filter) matchNumber(value string) bool {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	// Validate has checked that every operand parses.
+	parse := func(s string) int64 {
+		v, _ := strconv.ParseInt(s, 10, 64)
+		return v
+	}
+	switch f.Op {
+	case FilterOpEq:
+		return n == parse(f.Values[0])
+	case FilterOpIn:
+		for _, v := range f.Values {
+			if n == parse(v) {
+				return true
+			}
+		}
+		return false
+	default:
+		return (f.Gt == nil || n > parse(*f.Gt)) &&
+			(f.Gte == nil || n >= parse(*f.Gte)) &&
+			(f.Lt == nil || n < parse(*f.Lt)) &&
+			(f.Lte == nil || n <= parse(*f.Lte))
+	}
+}
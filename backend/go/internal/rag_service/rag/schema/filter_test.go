@@ -0,0 +1,63 @@
+package schema
+
+import "testing"
+
+func bound(s string) *string { return &s }
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		value  string
+		want   bool
+	}{
+		{"eq", Eq("doc_type", "pdf"), "pdf", true},
+		{"eq mismatch", Eq("doc_type", "pdf"), "md", false},
+		{"eq without value", Filter{Field: "doc_type", Op: FilterOpEq}, "", false},
+		{"in", In("folder_id", "1", "2"), "2", true},
+		{"in mismatch", In("folder_id", "1", "2"), "3", false},
+		{"empty in", In("folder_id"), "", false},
+		{"range inside", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Gte: bound("2"), Lt: bound("4")}, "3", true},
+		{"range lower bound", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Gt: bound("2")}, "2", false},
+		{"range upper bound", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Lte: bound("2")}, "2", true},
+		{"range compares numbers", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Lt: bound("9")}, "10", false},
+		{"numeric eq", Filter{Field: "page_number", Op: FilterOpEq, Numeric: true, Values: []string{"7"}}, "07", true},
+		{"numeric value is not an integer", Filter{Field: "page_number", Op: FilterOpEq, Numeric: true, Values: []string{"7"}}, "vii", false},
+		{"range on a string field", Filter{Field: "file_name", Op: FilterOpRange, Gte: bound("b")}, "c.pdf", false},
+		{"unknown op", Filter{Field: "doc_type", Op: "like", Values: []string{"pdf"}}, "pdf", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.value); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{"eq", Eq("doc_type", "pdf"), false},
+		{"eq empty string", Eq("doc_type", ""), false},
+		{"eq without value", Filter{Field: "doc_type", Op: FilterOpEq}, true},
+		{"in", In("folder_id", "1"), false},
+		{"empty in", In("folder_id"), true},
+		{"range", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Lt: bound("9")}, false},
+		{"range without bounds", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true}, true},
+		{"range on a string field", Filter{Field: "file_name", Op: FilterOpRange, Lt: bound("b")}, true},
+		{"numeric in", Filter{Field: "indexed_at", Op: FilterOpIn, Numeric: true, Values: []string{"1", "2"}}, false},
+		{"numeric bound is not an integer", Filter{Field: "page_number", Op: FilterOpRange, Numeric: true, Lt: bound("9.5")}, true},
+		{"unknown op", Filter{Field: "doc_type", Op: "like", Values: []string{"pdf"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.filter.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
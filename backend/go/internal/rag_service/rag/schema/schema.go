@@ -4,8 +4,9 @@ const (
 	// MetadataKeyImage is the key for image content.
 	// The value should be a slice of byte slices ([][]byte), where each inner slice is the raw binary data of an image.
 	MetadataKeyImage = "image"
-	// MetadataKeyChart is the key for chart or table data.
-	// The value can be structured data, like a CSV string or a slice of slices representing the table.
+	// MetadataKeyChart is the key for tables found in the content.
+	// The value should be a slice of tables ([][][]string), where each table is a slice of rows of cell texts and the
+	// first row is the header.
 	MetadataKeyChart = "chart"
 	// MetadataKeyVideo is the key for video content.
 	MetadataKeyVideo = "video"
@@ -15,8 +16,33 @@ const (
 	MetadataKeyFileName = "file_name"
 	// MetadataKeyPageLabel is the key for the page number or label from the source document.
 	MetadataKeyPageLabel = "page_label"
+	// MetadataKeyPageNumber is the key for the 1-based page number of a chunk whose page label is a number, as an int64.
+	MetadataKeyPageNumber = "page_number"
+	// MetadataKeyIndexedAt is the key for when a chunk was indexed, as Unix seconds in an int64.
+	MetadataKeyIndexedAt = "indexed_at"
+	// MetadataKeyDocType is the key for the kind of source a document was loaded from, e.g. "pdf" or "web".
+	MetadataKeyDocType = "doc_type"
+	// MetadataKeyHeadingPath is the key for the path of Markdown headings enclosing a chunk, e.g. "Setup > Install".
+	MetadataKeyHeadingPath = "heading_path"
+	// MetadataKeySymbol is the key for the names of the functions, types or classes a code chunk contains.
+	MetadataKeySymbol = "symbol"
+	// MetadataKeyRowStart and MetadataKeyRowEnd are the keys for the 1-based range of table rows in a chunk.
+	MetadataKeyRowStart = "row_start"
+	MetadataKeyRowEnd   = "row_end"
+	// MetadataKeySourceURL is the key for the URL a web page was loaded from.
+	MetadataKeySourceURL = "source_url"
+	// MetadataKeyTitle is the key for the title of a web page.
+	MetadataKeyTitle = "title"
+	// MetadataKeyImageURI is the key for the URI of the stored original of an image chunk, e.g. "minio://bucket/key".
+	MetadataKeyImageURI = "image_uri"
+	// MetadataKeySourceType is the key for where a retrieved document comes from. It is only set on documents that
+	// are not chunks of indexed documents, e.g. to SourceTypeKnowledgeGraph.
+	MetadataKeySourceType = "source_type"
 )
 
+// SourceTypeKnowledgeGraph is the MetadataKeySourceType of documents holding facts from the user's knowledge graph.
+const SourceTypeKnowledgeGraph = "knowledge_graph"
+
 // Document is the central data structure representing a piece of text and its associated data.
 // It is the primary data carrier throughout the RAG pipeline.
 type Document struct {
@@ -29,6 +55,10 @@ type Document struct {
 	// Embedding is the vector representation of the text.
 	Embedding []float32
 
+	// Score is the relevance of the document to the query, set during retrieval. Higher is more relevant;
+	// the scale depends on the stage that set it last (fusion or a reranker).
+	Score float64
+
 	// Metadata holds arbitrary data about the document.
 	// It is used to store information like file_name, page_label, type (text, table), etc.
 	Metadata map[string]interface{}
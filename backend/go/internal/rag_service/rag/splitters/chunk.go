@@ -0,0 +1,50 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// lengthFunc measures the size of a piece of text in the unit chunk sizes are expressed in.
+type lengthFunc func(text string) int
+
+// newTokenLength returns a lengthFunc that counts tokens with the same encoding as TokenSplitter,
+// so all splitters interpret chunk sizes alike.
+func newTokenLength() (lengthFunc, error) {
+	tke, err := tiktoken.GetEncoding("cl100k_base")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tiktoken encoding: %w", err)
+	}
+	return func(text string) int {
+		return len(tke.Encode(text, nil, nil))
+	}, nil
+}
+
+// newChunk creates a chunk of doc with the given text. The chunk gets a deep copy of the document's metadata
+// plus the chunk-specific original_doc_id and chunk_number.
+func newChunk(doc *schema.Document, text string, chunkNumber int) *schema.Document {
+	metadata := make(map[string]interface{}, len(doc.Metadata)+2)
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	metadata["original_doc_id"] = doc.ID
+	metadata["chunk_number"] = chunkNumber
+
+	return &schema.Document{
+		ID:       uuid.New().String(),
+		Text:     text,
+		Metadata: metadata,
+	}
+}
+
+// appendNonEmpty appends text to chunks unless it is only whitespace.
+func appendNonEmpty(chunks []string, text string) []string {
+	if strings.TrimSpace(text) == "" {
+		return chunks
+	}
+	return append(chunks, strings.TrimSpace(text))
+}
@@ -0,0 +1,243 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// declarationRegex matches an unindented line that starts a function, class or type in common languages,
+// capturing its name, e.g. "def load(", "export async function fetchAll(", "public class Parser {".
+var declarationRegex = regexp.MustCompile(
+	`^(?:export\s+)?(?:default\s+)?(?:(?:public|private|protected|internal|static|abstract|final|async|pub)\s+)*` +
+		`(?:def|class|function|func|fn|impl|struct|enum|interface|trait|type|module)\s+([A-Za-z_$][\w$]*)`)
+
+// codeUnit is a top-level declaration together with its leading comments.
+type codeUnit struct {
+	symbol string
+	text   string
+}
+
+// CodeSplitter implements the Splitter interface by cutting source code at top-level functions, types and classes.
+// Go files are parsed with go/parser; other languages are split at unindented declaration keywords.
+// Small adjacent declarations are grouped up to ChunkSize tokens, and the names they define are recorded under
+// schema.MetadataKeySymbol. Declarations larger than ChunkSize are split further with a RecursiveSplitter.
+type CodeSplitter struct {
+	ChunkSize int
+	inner     *RecursiveSplitter
+	length    lengthFunc
+}
+
+// NewCodeSplitter creates a new CodeSplitter. Sizes are measured in tokens.
+func NewCodeSplitter(chunkSize, chunkOverlap int) (*CodeSplitter, error) {
+	inner, err := NewRecursiveSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	return &CodeSplitter{
+		ChunkSize: chunkSize,
+		inner:     inner,
+		length:    inner.length,
+	}, nil
+}
+
+// Split splits a list of source code documents into chunks along declaration boundaries.
+func (s *CodeSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	var chunks []*schema.Document
+	for _, doc := range docs {
+		fileName, _ := doc.Metadata[schema.MetadataKeyFileName].(string)
+
+		var units []codeUnit
+		if strings.EqualFold(filepath.Ext(fileName), ".go") {
+			units = splitGoDeclarations(doc.Text)
+		}
+		if units == nil {
+			units = splitDeclarations(doc.Text)
+		}
+
+		chunkNumber := 0
+		emit := func(text string, symbols []string) {
+			chunkNumber++
+			chunk := newChunk(doc, strings.TrimSpace(text), chunkNumber)
+			if len(symbols) > 0 {
+				chunk.Metadata[schema.MetadataKeySymbol] = strings.Join(symbols, ", ")
+			}
+			chunks = append(chunks, chunk)
+		}
+
+		var group strings.Builder
+		var groupSymbols []string
+		groupLen := 0
+		flush := func() {
+			if strings.TrimSpace(group.String()) != "" {
+				emit(group.String(), groupSymbols)
+			}
+			group.Reset()
+			groupSymbols = nil
+			groupLen = 0
+		}
+
+		for _, unit := range units {
+			n := s.length(unit.text)
+			if n > s.ChunkSize {
+				flush()
+				for _, text := range s.inner.SplitText(unit.text) {
+					emit(text, symbolList(unit.symbol))
+				}
+				continue
+			}
+			if groupLen+n > s.ChunkSize {
+				flush()
+			}
+			group.WriteString(unit.text)
+			groupSymbols = append(groupSymbols, symbolList(unit.symbol)...)
+			groupLen += n
+		}
+		flush()
+	}
+	return chunks, nil
+}
+
+func symbolList(symbol string) []string {
+	if symbol == "" {
+		return nil
+	}
+	return []string{symbol}
+}
+
+// splitGoDeclarations cuts Go source before every top-level declaration other than imports, including the
+// declaration's doc comment. It returns nil if the source does not parse.
+func splitGoDeclarations(src string) []codeUnit {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+
+	type boundary struct {
+		offset int
+		symbol string
+	}
+	var boundaries []boundary
+	for _, decl := range file.Decls {
+		var start token.Pos
+		var symbol string
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			start, symbol = d.Pos(), d.Name.Name
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				symbol = fmt.Sprintf("%s.%s", receiverTypeName(d.Recv.List[0].Type), symbol)
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			start, symbol = d.Pos(), genDeclNames(d)
+			if d.Doc != nil {
+				start = d.Doc.Pos()
+			}
+		default:
+			continue
+		}
+		boundaries = append(boundaries, boundary{offset: fset.Position(start).Offset, symbol: symbol})
+	}
+
+	// Everything before the first declaration (package clause, imports) forms a unit of its own.
+	units := make([]codeUnit, 0, len(boundaries)+1)
+	prev := codeUnit{}
+	prevOffset := 0
+	for _, b := range boundaries {
+		prev.text = src[prevOffset:b.offset]
+		units = append(units, prev)
+		prev, prevOffset = codeUnit{symbol: b.symbol}, b.offset
+	}
+	prev.text = src[prevOffset:]
+	return append(units, prev)
+}
+
+// receiverTypeName returns the type name of a method receiver, without pointer or type parameters.
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
+
+// genDeclNames returns the comma-separated names declared by a type, const or var declaration.
+func genDeclNames(d *ast.GenDecl) string {
+	var names []string
+	for _, spec := range d.Specs {
+		switch sp := spec.(type) {
+		case *ast.TypeSpec:
+			names = append(names, sp.Name.Name)
+		case *ast.ValueSpec:
+			for _, name := range sp.Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitDeclarations cuts source code before every unindented line matching declarationRegex.
+// Comment and decorator lines directly above a declaration stay with it.
+func splitDeclarations(src string) []codeUnit {
+	lines := strings.SplitAfter(src, "\n")
+
+	var units []codeUnit
+	current := codeUnit{}
+	var currentLines []string
+	for _, line := range lines {
+		match := declarationRegex.FindStringSubmatch(line)
+		if match == nil {
+			currentLines = append(currentLines, line)
+			continue
+		}
+
+		// Move leading comments and decorators from the previous unit to this one.
+		keep := len(currentLines)
+		for keep > 0 && isCommentOrDecorator(currentLines[keep-1]) {
+			keep--
+		}
+		leading := currentLines[keep:]
+		current.text = strings.Join(currentLines[:keep], "")
+		units = append(units, current)
+
+		current = codeUnit{symbol: match[1]}
+		currentLines = append(append([]string(nil), leading...), line)
+	}
+	current.text = strings.Join(currentLines, "")
+	return append(units, current)
+}
+
+// isCommentOrDecorator reports whether a line is a comment or an annotation/decorator in common languages.
+func isCommentOrDecorator(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"//", "#", "/*", "*", "--", "@"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compile-time check to ensure CodeSplitter implements the Splitter interface
+var _ interfaces.Splitter = (*CodeSplitter)(nil)
@@ -0,0 +1,138 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const goSource = `package p
+
+import "fmt"
+
+// Hello greets.
+func Hello() { fmt.Println("hi") }
+
+type T struct{}
+
+func (t *T) M() {}
+
+const A, B = 1, 2
+`
+
+const pythonSource = `import os
+
+@cached
+def load():
+    def inner():
+        pass
+
+# Parses input.
+class Parser:
+    x = 1
+`
+
+func unitSymbols(units []codeUnit) []string {
+	symbols := make([]string, len(units))
+	for i, unit := range units {
+		symbols[i] = unit.symbol
+	}
+	return symbols
+}
+
+func TestSplitGoDeclarations(t *testing.T) {
+	units := splitGoDeclarations(goSource)
+	if want := []string{"", "Hello", "T", "T.M", "A, B"}; !slices.Equal(unitSymbols(units), want) {
+		t.Fatalf("symbols = %q, want %q", unitSymbols(units), want)
+	}
+	if !strings.HasPrefix(units[0].text, "package p") || !strings.HasPrefix(units[1].text, "// Hello greets.\nfunc Hello()") {
+		t.Errorf("units = %q", units)
+	}
+	var joined strings.Builder
+	for _, unit := range units {
+		joined.WriteString(unit.text)
+	}
+	if joined.String() != goSource {
+		t.Errorf("units do not cover the source: %q", joined.String())
+	}
+
+	if units := splitGoDeclarations("not go"); units != nil {
+		t.Errorf("splitGoDeclarations() of invalid source = %q, want nil", units)
+	}
+}
+
+func TestSplitDeclarations(t *testing.T) {
+	tests := []struct {
+		name        string
+		src         string
+		wantSymbols []string
+		wantTexts   []string
+	}{
+		{
+			"python",
+			pythonSource,
+			[]string{"", "load", "Parser"},
+			[]string{
+				"import os\n\n",
+				"@cached\ndef load():\n    def inner():\n        pass\n\n",
+				"# Parses input.\nclass Parser:\n    x = 1\n",
+			},
+		},
+		{
+			"typescript",
+			"export async function fetchAll() {}\npublic class Store {}\n",
+			[]string{"", "fetchAll", "Store"},
+			[]string{"", "export async function fetchAll() {}\n", "public class Store {}\n"},
+		},
+		{"no declarations", "x = 1\n", []string{""}, []string{"x = 1\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units := splitDeclarations(tt.src)
+			texts := make([]string, len(units))
+			for i, unit := range units {
+				texts[i] = unit.text
+			}
+			if !slices.Equal(unitSymbols(units), tt.wantSymbols) || !slices.Equal(texts, tt.wantTexts) {
+				t.Errorf("splitDeclarations() = %q", units)
+			}
+		})
+	}
+}
+
+func TestCodeSplitterSplit(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		src         string
+		size        int
+		wantSymbols []string
+	}{
+		{"go declarations are grouped", "p.go", goSource, 1000, []string{"Hello, T, T.M, A, B"}},
+		{"go declarations on their own", "p.go", goSource, 60, []string{"", "Hello", "T, T.M, A, B"}},
+		{"python", "load.py", pythonSource, 70, []string{"load", "Parser"}},
+		{"declaration larger than the chunk size", "load.py", pythonSource, 40, []string{"", "load", "load", "Parser"}},
+		// Without the .go extension the regular expression is used, which misses methods and constants.
+		{"go without extension", "", goSource, 60, []string{"", "Hello", "T"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := &CodeSplitter{ChunkSize: tt.size, inner: newTestSplitter(tt.size, 0), length: utf8.RuneCountInString}
+			doc := &schema.Document{ID: "doc", Text: tt.src, Metadata: map[string]interface{}{schema.MetadataKeyFileName: tt.fileName}}
+			chunks, err := splitter.Split(context.Background(), []*schema.Document{doc})
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			symbols := make([]string, len(chunks))
+			for i, chunk := range chunks {
+				symbols[i], _ = chunk.Metadata[schema.MetadataKeySymbol].(string)
+			}
+			if !slices.Equal(symbols, tt.wantSymbols) {
+				t.Errorf("Split() symbols = %q, want %q for chunks %q", symbols, tt.wantSymbols, chunkTexts(chunks))
+			}
+		})
+	}
+}
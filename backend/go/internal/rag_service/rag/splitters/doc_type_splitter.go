@@ -0,0 +1,85 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+)
+
+// codeDocTypes are the doc types, i.e. file extensions, routed to the CodeSplitter by NewDefaultDocTypeSplitter.
+var codeDocTypes = []string{
+	"go", "py", "js", "jsx", "ts", "tsx", "java", "kt", "scala", "c", "h", "cc", "cpp", "hpp",
+	"cs", "rs", "rb", "php", "swift", "sh", "lua",
+}
+
+// DocTypeSplitter implements the Splitter interface by routing each document to a splitter chosen by its
+// schema.MetadataKeyDocType. Loaders pick the splitter for their documents by setting that metadata;
+// documents with an unknown or missing doc type go to the fallback splitter.
+type DocTypeSplitter struct {
+	fallback interfaces.Splitter
+	routes   map[string]interfaces.Splitter
+}
+
+// NewDocTypeSplitter creates a new DocTypeSplitter that maps doc types to splitters.
+func NewDocTypeSplitter(fallback interfaces.Splitter, routes map[string]interfaces.Splitter) *DocTypeSplitter {
+	return &DocTypeSplitter{
+		fallback: fallback,
+		routes:   routes,
+	}
+}
+
+// NewDefaultDocTypeSplitter creates a DocTypeSplitter that splits Markdown by headings, tables and CSV by rows,
+// source code by declarations and everything else with a RecursiveSplitter. Sizes are measured in tokens.
+func NewDefaultDocTypeSplitter(chunkSize, chunkOverlap int) (*DocTypeSplitter, error) {
+	recursive, err := NewRecursiveSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	markdown, err := NewMarkdownHeaderSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	table, err := NewTableSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	code, err := NewCodeSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := map[string]interfaces.Splitter{
+		"md":       markdown,
+		"markdown": markdown,
+		"xlsx":     table,
+		"csv":      table,
+		"table":    table,
+	}
+	for _, docType := range codeDocTypes {
+		routes[docType] = code
+	}
+	return NewDocTypeSplitter(recursive, routes), nil
+}
+
+// Split splits each document with the splitter for its doc type, preserving the order of the documents.
+func (s *DocTypeSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	var chunks []*schema.Document
+	for _, doc := range docs {
+		splitter := s.fallback
+		if docType, ok := doc.Metadata[schema.MetadataKeyDocType].(string); ok {
+			if routed, ok := s.routes[docType]; ok {
+				splitter = routed
+			}
+		}
+
+		docChunks, err := splitter.Split(ctx, []*schema.Document{doc})
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, docChunks...)
+	}
+	return chunks, nil
+}
+
+// compile-time check to ensure DocTypeSplitter implements the Splitter interface
+var _ interfaces.Splitter = (*DocTypeSplitter)(nil)
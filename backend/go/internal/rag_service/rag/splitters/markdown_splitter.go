@@ -0,0 +1,113 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"regexp"
+	"strings"
+)
+
+// headingRegex matches an ATX Markdown heading such as "## Installation".
+var headingRegex = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// markdownSection is the text under a heading, up to the next heading of any level.
+type markdownSection struct {
+	headingPath string
+	text        string
+}
+
+// MarkdownHeaderSplitter implements the Splitter interface by cutting Markdown documents at headings.
+// Each chunk records the path of headings enclosing it under schema.MetadataKeyHeadingPath.
+// Sections larger than the chunk size are split further with a RecursiveSplitter.
+type MarkdownHeaderSplitter struct {
+	inner *RecursiveSplitter
+}
+
+// NewMarkdownHeaderSplitter creates a new MarkdownHeaderSplitter. Sizes are measured in tokens.
+func NewMarkdownHeaderSplitter(chunkSize, chunkOverlap int) (*MarkdownHeaderSplitter, error) {
+	inner, err := NewRecursiveSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	return &MarkdownHeaderSplitter{inner: inner}, nil
+}
+
+// Split splits a list of Markdown documents into chunks along their headings.
+func (s *MarkdownHeaderSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	var chunks []*schema.Document
+	for _, doc := range docs {
+		chunkNumber := 0
+		for _, section := range splitMarkdownSections(doc.Text) {
+			for _, text := range s.inner.SplitText(section.text) {
+				chunkNumber++
+				chunk := newChunk(doc, text, chunkNumber)
+				if section.headingPath != "" {
+					chunk.Metadata[schema.MetadataKeyHeadingPath] = section.headingPath
+				}
+				chunks = append(chunks, chunk)
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// splitMarkdownSections cuts text before every heading. Lines inside fenced code blocks are never treated as
+// headings, so "# comment" in a shell snippet does not start a section.
+func splitMarkdownSections(text string) []markdownSection {
+	type heading struct {
+		level int
+		title string
+	}
+	var (
+		sections []markdownSection
+		stack    []heading
+		current  []string
+		fence    string
+	)
+
+	headingPath := func() string {
+		titles := make([]string, len(stack))
+		for i, h := range stack {
+			titles[i] = h.title
+		}
+		return strings.Join(titles, " > ")
+	}
+	flush := func() {
+		if body := strings.Join(current, "\n"); strings.TrimSpace(body) != "" {
+			sections = append(sections, markdownSection{headingPath: headingPath(), text: body})
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			current = append(current, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			current = append(current, line)
+			continue
+		}
+
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			flush()
+			level := len(match[1])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, heading{level: level, title: match[2]})
+		}
+		current = append(current, line)
+	}
+	flush()
+	return sections
+}
+
+// compile-time check to ensure MarkdownHeaderSplitter implements the Splitter interface
+var _ interfaces.Splitter = (*MarkdownHeaderSplitter)(nil)
@@ -0,0 +1,68 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestSplitMarkdownSections(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []markdownSection
+	}{
+		{"no headings", "just text", []markdownSection{{"", "just text"}}},
+		{
+			"nested headings",
+			"intro\n# A\nalpha\n## B\nbeta\n# C\ngamma",
+			[]markdownSection{
+				{"", "intro"},
+				{"A", "# A\nalpha"},
+				{"A > B", "## B\nbeta"},
+				{"C", "# C\ngamma"},
+			},
+		},
+		{"closing hashes", "### Title ###\ntext", []markdownSection{{"Title", "### Title ###\ntext"}}},
+		{
+			"code fences",
+			"# Setup\n```sh\n# not a heading\n```\nafter",
+			[]markdownSection{{"Setup", "# Setup\n```sh\n# not a heading\n```\nafter"}},
+		},
+		{"hash without space", "#tag\ntext", []markdownSection{{"", "#tag\ntext"}}},
+		{"empty", "\n\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitMarkdownSections(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("splitMarkdownSections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownHeaderSplitterSplit(t *testing.T) {
+	splitter := &MarkdownHeaderSplitter{inner: newTestSplitter(13, 0)}
+	doc := &schema.Document{ID: "doc", Text: "# Guide\nshort\n## Install\nfirst step\n\nsecond step"}
+	chunks, err := splitter.Split(context.Background(), []*schema.Document{doc})
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	want := []struct{ text, headingPath string }{
+		{"# Guide\nshort", "Guide"},
+		{"## Install", "Guide > Install"},
+		{"first step", "Guide > Install"},
+		{"second step", "Guide > Install"},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("Split() = %q", chunkTexts(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Text != want[i].text || chunk.Metadata[schema.MetadataKeyHeadingPath] != want[i].headingPath ||
+			chunk.Metadata["chunk_number"] != i+1 {
+			t.Errorf("chunk %d = %q with metadata %v, want %q under %q", i, chunk.Text, chunk.Metadata, want[i].text, want[i].headingPath)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"strings"
+)
+
+// defaultSeparators are tried in order, from paragraph breaks down to single characters,
+// so that text is cut at the largest natural boundary that yields small enough pieces.
+var defaultSeparators = []string{
+	"\n\n", "\n",
+	"。", ". ", "！", "! ", "？", "? ",
+	"；", "; ", "，", ", ",
+	" ", "",
+}
+
+// RecursiveSplitter implements the Splitter interface by recursively splitting text on paragraph, line,
+// sentence and word boundaries until every piece fits, then merging adjacent pieces into chunks of up to
+// ChunkSize tokens that overlap by about ChunkOverlap tokens. Chunks therefore rarely start mid-sentence.
+type RecursiveSplitter struct {
+	ChunkSize    int
+	ChunkOverlap int
+	separators   []string
+	length       lengthFunc
+}
+
+// NewRecursiveSplitter creates a new RecursiveSplitter. Sizes are measured in tokens.
+func NewRecursiveSplitter(chunkSize, chunkOverlap int) (*RecursiveSplitter, error) {
+	length, err := newTokenLength()
+	if err != nil {
+		return nil, err
+	}
+	return &RecursiveSplitter{
+		ChunkSize:    chunkSize,
+		ChunkOverlap: chunkOverlap,
+		separators:   defaultSeparators,
+		length:       length,
+	}, nil
+}
+
+// Split splits a list of documents into chunks along natural text boundaries.
+func (s *RecursiveSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	var chunks []*schema.Document
+	for _, doc := range docs {
+		for i, text := range s.SplitText(doc.Text) {
+			chunks = append(chunks, newChunk(doc, text, i+1))
+		}
+	}
+	return chunks, nil
+}
+
+// SplitText splits text into chunks of up to ChunkSize tokens. Other splitters use it for sections that are too large.
+func (s *RecursiveSplitter) SplitText(text string) []string {
+	return s.splitText(text, s.separators)
+}
+
+func (s *RecursiveSplitter) splitText(text string, separators []string) []string {
+	// Use the first separator that occurs in the text; "" always matches and splits into characters.
+	separator, rest := "", []string(nil)
+	for i, candidate := range separators {
+		if candidate == "" || strings.Contains(text, candidate) {
+			separator, rest = candidate, separators[i+1:]
+			break
+		}
+	}
+
+	var chunks, fitting []string
+	// SplitAfter keeps each separator at the end of its piece, so sentences keep their punctuation.
+	for _, piece := range strings.SplitAfter(text, separator) {
+		if s.length(piece) <= s.ChunkSize {
+			fitting = append(fitting, piece)
+			continue
+		}
+		chunks = append(chunks, s.merge(fitting)...)
+		fitting = nil
+		if len(rest) == 0 {
+			chunks = appendNonEmpty(chunks, piece)
+		} else {
+			chunks = append(chunks, s.splitText(piece, rest)...)
+		}
+	}
+	return append(chunks, s.merge(fitting)...)
+}
+
+// merge joins consecutive pieces into chunks of up to ChunkSize tokens. Each new chunk starts with the trailing
+// pieces of the previous one, up to ChunkOverlap tokens. Lengths are summed per piece, which slightly
+// overestimates the token count of the joined text.
+func (s *RecursiveSplitter) merge(pieces []string) []string {
+	var chunks, window []string
+	var windowLens []int
+	total := 0
+
+	for _, piece := range pieces {
+		n := s.length(piece)
+		if total+n > s.ChunkSize && len(window) > 0 {
+			chunks = appendNonEmpty(chunks, strings.Join(window, ""))
+			for len(window) > 0 && (total > s.ChunkOverlap || total+n > s.ChunkSize) {
+				total -= windowLens[0]
+				window, windowLens = window[1:], windowLens[1:]
+			}
+		}
+		window = append(window, piece)
+		windowLens = append(windowLens, n)
+		total += n
+	}
+	if len(window) > 0 {
+		chunks = appendNonEmpty(chunks, strings.Join(window, ""))
+	}
+	return chunks
+}
+
+// compile-time check to ensure RecursiveSplitter implements the Splitter interface
+var _ interfaces.Splitter = (*RecursiveSplitter)(nil)
@@ -0,0 +1,74 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+// newTestSplitter creates a RecursiveSplitter that measures sizes in characters, so the tests need no tokenizer.
+func newTestSplitter(chunkSize, chunkOverlap int) *RecursiveSplitter {
+	return &RecursiveSplitter{
+		ChunkSize:    chunkSize,
+		ChunkOverlap: chunkOverlap,
+		separators:   defaultSeparators,
+		length:       utf8.RuneCountInString,
+	}
+}
+
+func chunkTexts(chunks []*schema.Document) []string {
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	return texts
+}
+
+func TestRecursiveSplitterSplitText(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		size, overlap int
+		want          []string
+	}{
+		{"fits", "A short text.", 100, 0, []string{"A short text."}},
+		{"paragraphs", "aaaa\n\nbbbb\n\ncccc", 6, 0, []string{"aaaa", "bbbb", "cccc"}},
+		{"paragraphs are merged", "aa\n\nbb\n\ncccc", 8, 0, []string{"aa\n\nbb", "cccc"}},
+		{"sentences keep their punctuation", "First one. Second one. Third.", 12, 0, []string{"First one.", "Second one.", "Third."}},
+		{"chinese sentences", "第一句。第二句。", 4, 0, []string{"第一句。", "第二句。"}},
+		{"overlap", "aa bb cc dd ee", 10, 4, []string{"aa bb cc", "cc dd ee"}},
+		{"characters as a last resort", "abcdefgh", 3, 0, []string{"abc", "def", "gh"}},
+		{"only whitespace", " \n\n ", 10, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestSplitter(tt.size, tt.overlap).SplitText(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("SplitText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecursiveSplitterSplit(t *testing.T) {
+	doc := &schema.Document{ID: "doc", Text: "aaaa\n\nbbbb", Metadata: map[string]interface{}{schema.MetadataKeyFileName: "a.txt"}}
+	chunks, err := newTestSplitter(4, 0).Split(context.Background(), []*schema.Document{doc})
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+	if !slices.Equal(chunkTexts(chunks), []string{"aaaa", "bbbb"}) {
+		t.Fatalf("Split() = %q", chunkTexts(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.ID == "" || chunk.ID == doc.ID || chunk.Metadata["original_doc_id"] != "doc" ||
+			chunk.Metadata["chunk_number"] != i+1 || chunk.Metadata[schema.MetadataKeyFileName] != "a.txt" {
+			t.Errorf("chunk %d = %+v", i, chunk)
+		}
+	}
+	// Chunks get their own copy of the metadata.
+	chunks[0].Metadata["extra"] = true
+	if _, ok := doc.Metadata["extra"]; ok {
+		t.Error("chunk metadata is shared with the document")
+	}
+}
@@ -0,0 +1,122 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"regexp"
+	"strings"
+)
+
+// tableSeparatorRegex matches the line under a Markdown table header, e.g. "|---|:--:|" or "|---------".
+var tableSeparatorRegex = regexp.MustCompile(`^\s*\|?\s*:?-{3,}`)
+
+// TableSplitter implements the Splitter interface for tabular documents, such as the Markdown tables produced by
+// XlsxLoader or CSV files. Tables are split between rows and every chunk repeats the header, so each chunk can be
+// understood on its own. Row ranges are recorded under schema.MetadataKeyRowStart and schema.MetadataKeyRowEnd.
+// Text outside tables is split with a RecursiveSplitter.
+type TableSplitter struct {
+	ChunkSize int
+	inner     *RecursiveSplitter
+	length    lengthFunc
+}
+
+// NewTableSplitter creates a new TableSplitter. Sizes are measured in tokens.
+func NewTableSplitter(chunkSize, chunkOverlap int) (*TableSplitter, error) {
+	inner, err := NewRecursiveSplitter(chunkSize, chunkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	return &TableSplitter{
+		ChunkSize: chunkSize,
+		inner:     inner,
+		length:    inner.length,
+	}, nil
+}
+
+// Split splits a list of tabular documents into chunks of whole rows.
+func (s *TableSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	var chunks []*schema.Document
+	for _, doc := range docs {
+		lines := strings.Split(strings.TrimRight(doc.Text, "\n"), "\n")
+		docType, _ := doc.Metadata[schema.MetadataKeyDocType].(string)
+
+		chunkNumber := 0
+		emitText := func(text string) {
+			for _, piece := range s.inner.SplitText(text) {
+				chunkNumber++
+				chunks = append(chunks, newChunk(doc, piece, chunkNumber))
+			}
+		}
+		emitRows := func(header []string, rows []string, firstRow int) {
+			for _, group := range s.groupRows(header, rows) {
+				chunkNumber++
+				chunk := newChunk(doc, strings.Join(append(append([]string(nil), header...), group...), "\n"), chunkNumber)
+				chunk.Metadata[schema.MetadataKeyRowStart] = firstRow
+				chunk.Metadata[schema.MetadataKeyRowEnd] = firstRow + len(group) - 1
+				chunks = append(chunks, chunk)
+				firstRow += len(group)
+			}
+		}
+
+		// A CSV file is a single table whose first line is the header.
+		if docType == "csv" {
+			if len(lines) > 1 {
+				emitRows(lines[:1], lines[1:], 1)
+			} else {
+				emitText(doc.Text)
+			}
+			continue
+		}
+
+		var text []string
+		for i := 0; i < len(lines); {
+			if i+1 < len(lines) && isTableRow(lines[i]) && tableSeparatorRegex.MatchString(lines[i+1]) {
+				emitText(strings.Join(text, "\n"))
+				text = nil
+
+				end := i + 2
+				for end < len(lines) && isTableRow(lines[end]) {
+					end++
+				}
+				emitRows(lines[i:i+2], lines[i+2:end], 1)
+				i = end
+				continue
+			}
+			text = append(text, lines[i])
+			i++
+		}
+		emitText(strings.Join(text, "\n"))
+	}
+	return chunks, nil
+}
+
+// groupRows packs rows into groups whose text, with the header prepended, fits in ChunkSize tokens.
+// A row that does not fit even on its own gets a group of its own.
+func (s *TableSplitter) groupRows(header, rows []string) [][]string {
+	headerLen := s.length(strings.Join(header, "\n"))
+
+	var groups [][]string
+	var group []string
+	groupLen := headerLen
+	for _, row := range rows {
+		n := s.length(row) + 1 // +1 for the newline
+		if len(group) > 0 && groupLen+n > s.ChunkSize {
+			groups = append(groups, group)
+			group, groupLen = nil, headerLen
+		}
+		group = append(group, row)
+		groupLen += n
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func isTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// compile-time check to ensure TableSplitter implements the Splitter interface
+var _ interfaces.Splitter = (*TableSplitter)(nil)
@@ -0,0 +1,62 @@
+package splitters
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTableSplitterSplit(t *testing.T) {
+	type chunk struct {
+		text             string
+		rowStart, rowEnd int // 0 for text outside tables
+	}
+	tests := []struct {
+		name    string
+		docType string
+		text    string
+		size    int
+		want    []chunk
+	}{
+		{
+			"csv rows repeat the header",
+			"csv", "name,age\nann,1\nbob,2\ncid,3\n", 20,
+			[]chunk{{"name,age\nann,1\nbob,2", 1, 2}, {"name,age\ncid,3", 3, 3}},
+		},
+		{"csv without rows", "csv", "name,age", 20, []chunk{{"name,age", 0, 0}}},
+		{
+			"markdown table between text",
+			"xlsx", "Intro\n| a | b |\n|---|---|\n| 1 | 2 |\n| 3 | 4 |\nOutro", 1000,
+			[]chunk{{"Intro", 0, 0}, {"| a | b |\n|---|---|\n| 1 | 2 |\n| 3 | 4 |", 1, 2}, {"Outro", 0, 0}},
+		},
+		{
+			"row larger than the chunk size",
+			"xlsx", "| a |\n|---|\n| a long row |\n| 2 |", 20,
+			[]chunk{{"| a |\n|---|\n| a long row |", 1, 1}, {"| a |\n|---|\n| 2 |", 2, 2}},
+		},
+		{"pipe without separator is text", "", "| not a table", 1000, []chunk{{"| not a table", 0, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := &TableSplitter{ChunkSize: tt.size, inner: newTestSplitter(tt.size, 0), length: utf8.RuneCountInString}
+			doc := &schema.Document{ID: "doc", Text: tt.text, Metadata: map[string]interface{}{schema.MetadataKeyDocType: tt.docType}}
+			chunks, err := splitter.Split(context.Background(), []*schema.Document{doc})
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			if len(chunks) != len(tt.want) {
+				t.Fatalf("Split() = %q, want %d chunks", chunkTexts(chunks), len(tt.want))
+			}
+			for i, got := range chunks {
+				rowStart, _ := got.Metadata[schema.MetadataKeyRowStart].(int)
+				rowEnd, _ := got.Metadata[schema.MetadataKeyRowEnd].(int)
+				if got.Text != tt.want[i].text || rowStart != tt.want[i].rowStart || rowEnd != tt.want[i].rowEnd ||
+					got.Metadata["chunk_number"] != i+1 {
+					t.Errorf("chunk %d = %q rows %d-%d, want %q rows %d-%d",
+						i, got.Text, rowStart, rowEnd, tt.want[i].text, tt.want[i].rowStart, tt.want[i].rowEnd)
+				}
+			}
+		})
+	}
+}
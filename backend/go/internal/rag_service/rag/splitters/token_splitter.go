@@ -6,7 +6,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/google/uuid"
 	"github.com/pkoukk/tiktoken-go"
 )
 
@@ -51,18 +50,7 @@ func (s *TokenSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*
 			chunkText := s.tokenizer.Decode(tokens[start:end])
 
 			// Create a new document for the chunk
-			newDoc := &schema.Document{
-				ID:   uuid.New().String(),
-				Text: chunkText,
-				// Deep copy metadata to avoid sharing maps
-				Metadata: s.copyMetadata(doc.Metadata),
-			}
-
-			// Add chunk-specific metadata
-			newDoc.Metadata["original_doc_id"] = doc.ID
-			newDoc.Metadata["chunk_number"] = (start / step) + 1
-
-			chunks = append(chunks, newDoc)
+			chunks = append(chunks, newChunk(doc, chunkText, (start/step)+1))
 
 			if end == len(tokens) {
 				break
@@ -73,16 +61,5 @@ func (s *TokenSplitter) Split(ctx context.Context, docs []*schema.Document) ([]*
 	return chunks, nil
 }
 
-func (s *TokenSplitter) copyMetadata(md map[string]interface{}) map[string]interface{} {
-	if md == nil {
-		return make(map[string]interface{})
-	}
-	newMd := make(map[string]interface{}, len(md))
-	for k, v := range md {
-		newMd[k] = v
-	}
-	return newMd
-}
-
 // compile-time check to ensure TokenSplitter implements the Splitter interface
 var _ interfaces.Splitter = (*TokenSplitter)(nil)
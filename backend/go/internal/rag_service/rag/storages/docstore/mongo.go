@@ -0,0 +1,121 @@
+package docstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoChunk is the persisted form of a document chunk.
+// The _id combines the user ID and the chunk ID so that tenants can never read or overwrite each other's chunks.
+type mongoChunk struct {
+	ID        string                 `bson:"_id"`
+	UserID    string                 `bson:"user_id"`
+	ChunkID   string                 `bson:"chunk_id"`
+	Text      string                 `bson:"text"`
+	Metadata  map[string]interface{} `bson:"metadata"`
+	UpdatedAt time.Time              `bson:"updated_at"`
+}
+
+// MongoDocStore is a persistent implementation of the DocStore interface backed by MongoDB.
+type MongoDocStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDocStore creates a new MongoDocStore using the given database and collection.
+func NewMongoDocStore(db *mongo.Database, collectionName string) *MongoDocStore {
+	return &MongoDocStore{
+		collection: db.Collection(collectionName),
+	}
+}
+
+// EnsureIndexes creates the index used to look up chunks by user.
+func (s *MongoDocStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "chunk_id", Value: 1}},
+	})
+	return err
+}
+
+// tenantKey generates a key that is unique for a given user and document ID.
+func (s *MongoDocStore) tenantKey(userID, docID string) string {
+	return fmt.Sprintf("%s:%s", userID, docID)
+}
+
+// Add upserts a map of documents for a specific user. Embeddings are not stored; they live in the vector store.
+func (s *MongoDocStore) Add(ctx context.Context, userID string, docs map[string]*schema.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for id, doc := range docs {
+		chunk := mongoChunk{
+			ID:        s.tenantKey(userID, id),
+			UserID:    userID,
+			ChunkID:   id,
+			Text:      doc.Text,
+			Metadata:  doc.Metadata,
+			UpdatedAt: now,
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": chunk.ID}).
+			SetReplacement(chunk).
+			SetUpsert(true))
+	}
+
+	_, err := s.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to write chunks to mongo doc store: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a map of documents by their IDs for a specific user. Missing IDs are omitted from the result.
+func (s *MongoDocStore) Get(ctx context.Context, userID string, ids []string) (map[string]*schema.Document, error) {
+	result := make(map[string]*schema.Document)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	cursor, err := s.collection.Find(ctx, bson.M{"user_id": userID, "chunk_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mongo doc store: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var chunk mongoChunk
+		if err := cursor.Decode(&chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode chunk: %w", err)
+		}
+		result[chunk.ChunkID] = &schema.Document{
+			ID:       chunk.ChunkID,
+			Text:     chunk.Text,
+			Metadata: chunk.Metadata,
+		}
+	}
+	return result, cursor.Err()
+}
+
+// Delete removes documents by their IDs for a specific user.
+func (s *MongoDocStore) Delete(ctx context.Context, userID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.collection.DeleteMany(ctx, bson.M{"user_id": userID, "chunk_id": bson.M{"$in": ids}})
+	if err != nil {
+		return fmt.Errorf("failed to delete chunks from mongo doc store: %w", err)
+	}
+	return nil
+}
+
+// compile-time check to ensure MongoDocStore implements the DocStore interface
+var _ interfaces.DocStore = (*MongoDocStore)(nil)
@@ -0,0 +1,109 @@
+package docstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisChunk is the JSON form of a document chunk stored in Redis.
+type redisChunk struct {
+	Text     string                 `json:"text"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// RedisDocStore is a persistent implementation of the DocStore interface backed by Redis.
+// Each chunk is stored under "<prefix>:<userID>:<chunkID>", which keeps tenants isolated.
+type RedisDocStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisDocStore creates a new RedisDocStore. The prefix namespaces the keys, e.g. "rag:docstore".
+func NewRedisDocStore(client *redis.Client, prefix string) *RedisDocStore {
+	return &RedisDocStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// tenantKey generates a key that is unique for a given user and document ID.
+func (s *RedisDocStore) tenantKey(userID, docID string) string {
+	return fmt.Sprintf("%s:%s:%s", s.prefix, userID, docID)
+}
+
+// Add stores a map of documents for a specific user. Embeddings are not stored; they live in the vector store.
+func (s *RedisDocStore) Add(ctx context.Context, userID string, docs map[string]*schema.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	pipe := s.client.Pipeline()
+	for id, doc := range docs {
+		value, err := json.Marshal(redisChunk{Text: doc.Text, Metadata: doc.Metadata})
+		if err != nil {
+			return fmt.Errorf("failed to marshal chunk %s: %w", id, err)
+		}
+		pipe.Set(ctx, s.tenantKey(userID, id), value, 0)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write chunks to redis doc store: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a map of documents by their IDs for a specific user. Missing IDs are omitted from the result.
+func (s *RedisDocStore) Get(ctx context.Context, userID string, ids []string) (map[string]*schema.Document, error) {
+	result := make(map[string]*schema.Document)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	values, err := s.client.MGet(ctx, s.keys(userID, ids)...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query redis doc store: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // nil for missing keys
+		}
+		var chunk redisChunk
+		if err := json.Unmarshal([]byte(raw), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode chunk %s: %w", ids[i], err)
+		}
+		result[ids[i]] = &schema.Document{
+			ID:       ids[i],
+			Text:     chunk.Text,
+			Metadata: chunk.Metadata,
+		}
+	}
+	return result, nil
+}
+
+// Delete removes documents by their IDs for a specific user.
+func (s *RedisDocStore) Delete(ctx context.Context, userID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.client.Del(ctx, s.keys(userID, ids)...).Err(); err != nil {
+		return fmt.Errorf("failed to delete chunks from redis doc store: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisDocStore) keys(userID string, ids []string) []string {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = s.tenantKey(userID, id)
+	}
+	return keys
+}
+
+// compile-time check to ensure RedisDocStore implements the DocStore interface
+var _ interfaces.DocStore = (*RedisDocStore)(nil)
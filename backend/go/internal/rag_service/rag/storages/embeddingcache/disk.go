@@ -0,0 +1,87 @@
+package embeddingcache
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DiskCache is an implementation of the EmbeddingCache interface that stores each vector in a file under a local
+// directory, for deployments without Redis. Files are spread over subdirectories named after the first two
+// characters of their key, which must be safe to use as file names, such as hex hashes.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a new DiskCache in dir, creating the directory if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create embedding cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(c.dir, key)
+	}
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// Get returns the cached vectors for the keys, with nil for keys that are not cached.
+func (c *DiskCache) Get(ctx context.Context, keys []string) ([][]float32, error) {
+	vectors := make([][]float32, len(keys))
+	for i, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		buf, err := os.ReadFile(c.path(key))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached embedding: %w", err)
+		}
+		// A corrupt entry is treated as missing and overwritten once the text is embedded again.
+		if vector, err := decodeVector(buf); err == nil {
+			vectors[i] = vector
+		}
+	}
+	return vectors, nil
+}
+
+// Set caches the vectors under their keys. Each file is written to a temporary name and renamed into place, so that
+// concurrent readers never see a partial vector.
+func (c *DiskCache) Set(ctx context.Context, vectors map[string][]float32) error {
+	for key, vector := range vectors {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		path := c.path(key)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create embedding cache directory: %w", err)
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+		if err != nil {
+			return fmt.Errorf("failed to write cached embedding: %w", err)
+		}
+		_, writeErr := tmp.Write(encodeVector(vector))
+		closeErr := tmp.Close()
+		if err := errors.Join(writeErr, closeErr); err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write cached embedding: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write cached embedding: %w", err)
+		}
+	}
+	return nil
+}
+
+// compile-time check to ensure DiskCache implements the EmbeddingCache interface
+var _ interfaces.EmbeddingCache = (*DiskCache)(nil)
@@ -0,0 +1,68 @@
+package embeddingcache
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	vectors := map[string][]float32{
+		"ab01": {0.5, -1.25, 3},
+		"ab02": {1},
+		"c":    {2, 4}, // Too short for a subdirectory
+	}
+	if err := c.Set(ctx, vectors); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	// A corrupt entry is treated as missing.
+	if err := c.Set(ctx, map[string][]float32{"ff01": {1}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := os.WriteFile(c.path("ff01"), []byte{1, 2, 3}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"ab02", "missing", "ab01", "c", "ff01"}
+	got, err := c.Get(ctx, keys)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got) != len(keys) {
+		t.Fatalf("Get() returned %d vectors for %d keys", len(got), len(keys))
+	}
+	for i, key := range keys {
+		if !slices.Equal(got[i], vectors[key]) {
+			t.Errorf("Get(%q) = %v, want %v", key, got[i], vectors[key])
+		}
+	}
+
+	// A new cache in the same directory sees the stored vectors.
+	reopened, err := NewDiskCache(c.dir)
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	got, err = reopened.Get(ctx, []string{"ab01"})
+	if err != nil || !slices.Equal(got[0], vectors["ab01"]) {
+		t.Errorf("Get() after reopening = %v, %v; want %v", got, err, vectors["ab01"])
+	}
+}
+
+func TestDecodeVector(t *testing.T) {
+	vector := []float32{0, -0.5, 1e10}
+	got, err := decodeVector(encodeVector(vector))
+	if err != nil || !slices.Equal(got, vector) {
+		t.Errorf("decodeVector(encodeVector(%v)) = %v, %v", vector, got, err)
+	}
+	for _, buf := range [][]byte{nil, {1, 2, 3}, {1, 2, 3, 4, 5}} {
+		if _, err := decodeVector(buf); err == nil {
+			t.Errorf("decodeVector(%v) succeeded", buf)
+		}
+	}
+}
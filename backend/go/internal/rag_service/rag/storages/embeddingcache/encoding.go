@@ -0,0 +1,28 @@
+package embeddingcache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// encodeVector encodes a vector as little-endian float32 values.
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector decodes a vector encoded by encodeVector.
+func decodeVector(buf []byte) ([]float32, error) {
+	if len(buf) == 0 || len(buf)%4 != 0 {
+		return nil, fmt.Errorf("invalid cached vector of %d bytes", len(buf))
+	}
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector, nil
+}
@@ -0,0 +1,70 @@
+package embeddingcache
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisCache is an implementation of the EmbeddingCache interface backed by Redis, which lets all replicas of the
+// service share one cache. Each vector is stored under "<prefix>:<key>".
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisCache creates a new RedisCache. The prefix namespaces the keys, e.g. "rag:embeddings"; a ttl of 0 keeps
+// vectors until Redis evicts them.
+func NewRedisCache(client *redis.Client, prefix string, ttl time.Duration) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Get returns the cached vectors for the keys, with nil for keys that are not cached.
+func (c *RedisCache) Get(ctx context.Context, keys []string) ([][]float32, error) {
+	vectors := make([][]float32, len(keys))
+	if len(keys) == 0 {
+		return vectors, nil
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = c.prefix + ":" + key
+	}
+
+	values, err := c.client.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query redis embedding cache: %w", err)
+	}
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // nil for missing keys
+		}
+		// A corrupt entry is treated as missing and overwritten once the text is embedded again.
+		if vector, err := decodeVector([]byte(raw)); err == nil {
+			vectors[i] = vector
+		}
+	}
+	return vectors, nil
+}
+
+// Set caches the vectors under their keys.
+func (c *RedisCache) Set(ctx context.Context, vectors map[string][]float32) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	pipe := c.client.Pipeline()
+	for key, vector := range vectors {
+		pipe.Set(ctx, c.prefix+":"+key, encodeVector(vector), c.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write redis embedding cache: %w", err)
+	}
+	return nil
+}
+
+// compile-time check to ensure RedisCache implements the EmbeddingCache interface
+var _ interfaces.EmbeddingCache = (*RedisCache)(nil)
@@ -0,0 +1,437 @@
+package keywordstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+const (
+	// Standard Okapi BM25 parameters.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// snapshotDelay is how long changes are collected before a snapshot is written, so that indexing a batch of files
+// writes one snapshot instead of one per file.
+const snapshotDelay = 5 * time.Second
+
+// MetadataKeyKeywordScore is the metadata key under which Search stores the BM25 score of a hit.
+const MetadataKeyKeywordScore = "keyword_score"
+
+// segment is the inverted index of a single user's folder.
+type segment struct {
+	Postings map[string]map[string]int    // term -> chunk ID -> term frequency
+	DocLens  map[string]int               // chunk ID -> number of tokens
+	Fields   map[string]map[string]string // chunk ID -> filterable field -> value
+	Terms    map[string][]string          // chunk ID -> distinct terms, so that removing a chunk visits only its postings
+	TotalLen int
+}
+
+func newSegment() *segment {
+	return &segment{
+		Postings: make(map[string]map[string]int),
+		DocLens:  make(map[string]int),
+		Fields:   make(map[string]map[string]string),
+		Terms:    make(map[string][]string),
+	}
+}
+
+// BM25Index is a thread-safe, in-memory BM25 keyword index over chunk text, partitioned by user and folder.
+// If a snapshot path is configured, the index is loaded from it on creation and written back in the background a
+// few seconds after it changes, and on Close, so it survives restarts without an external search service. Changes
+// made in the last seconds before a crash are lost and come back when the affected documents are re-indexed.
+//
+// The index lives in the memory of one process, while index jobs run on whichever replica consumes them. Replicas
+// would each see only the chunks they indexed themselves, so a service using the index must run as a single
+// replica, which serves both indexing and queries; rag_service enforces this with the etcd lock configured under
+// rag.keyword_index.replica_lock.
+type BM25Index struct {
+	log          logger.Logger
+	mu           sync.RWMutex
+	segments     map[string]map[string]*segment // user ID -> folder ID -> segment
+	snapshotPath string
+	changed      chan struct{} // Signals the snapshot writer; buffered so a pending signal covers later changes
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewBM25Index creates a new BM25Index. An empty snapshotPath keeps the index in memory only.
+func NewBM25Index(snapshotPath string, log logger.Logger) (*BM25Index, error) {
+	idx := &BM25Index{
+		log:          log,
+		segments:     make(map[string]map[string]*segment),
+		snapshotPath: snapshotPath,
+	}
+	if snapshotPath == "" {
+		return idx, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(snapshotPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create keyword index snapshot directory: %w", err)
+	}
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+
+	idx.changed = make(chan struct{}, 1)
+	idx.stop = make(chan struct{})
+	idx.stopped = make(chan struct{})
+	go idx.writeSnapshots()
+	return idx, nil
+}
+
+// load reads the snapshot, if there is one.
+func (idx *BM25Index) load() error {
+	f, err := os.Open(idx.snapshotPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open keyword index snapshot: %w", err)
+	}
+	defer f.Close()
+	if err := gob.NewDecoder(f).Decode(&idx.segments); err != nil {
+		return fmt.Errorf("failed to decode keyword index snapshot: %w", err)
+	}
+	return nil
+}
+
+// Add indexes the text of the given chunks into the user's folder. Re-adding a chunk ID replaces it.
+func (idx *BM25Index) Add(ctx context.Context, userID, folderID string, docs []*schema.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	folders, ok := idx.segments[userID]
+	if !ok {
+		folders = make(map[string]*segment)
+		idx.segments[userID] = folders
+	}
+	seg, ok := folders[folderID]
+	if !ok {
+		seg = newSegment()
+		folders[folderID] = seg
+	}
+
+	for _, doc := range docs {
+		seg.remove(doc.ID)
+		tokens := Tokenize(doc.Text)
+		for _, term := range tokens {
+			postings, ok := seg.Postings[term]
+			if !ok {
+				postings = make(map[string]int)
+				seg.Postings[term] = postings
+			}
+			postings[doc.ID]++
+		}
+		seg.Terms[doc.ID] = uniqueTerms(tokens)
+		seg.DocLens[doc.ID] = len(tokens)
+		seg.TotalLen += len(tokens)
+
+		fields := map[string]string{vectorstore.FieldFolderID: folderID}
+		for _, field := range vectorstore.FilterableFields {
+			if value, ok := doc.Metadata[field]; ok && value != nil && field != vectorstore.FieldFolderID {
+				fields[field] = fmt.Sprintf("%v", value)
+			}
+		}
+		seg.Fields[doc.ID] = fields
+	}
+	idx.markChanged()
+	return nil
+}
+
+// Delete removes chunks by their IDs from all of the user's folders.
+func (idx *BM25Index) Delete(ctx context.Context, userID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for folderID, seg := range idx.segments[userID] {
+		for _, id := range ids {
+			seg.remove(id)
+		}
+		if len(seg.DocLens) == 0 {
+			delete(idx.segments[userID], folderID)
+		}
+	}
+	if len(idx.segments[userID]) == 0 {
+		delete(idx.segments, userID)
+	}
+	idx.markChanged()
+	return nil
+}
+
+// Search returns up to topK chunks of the user ranked by BM25 score, most relevant first.
+// If folderIDs is empty, all of the user's folders are searched. Corpus statistics are computed over the searched folders.
+// Only chunks matching all filters are returned, consistent with the filters applied by the vector store.
+// The returned documents carry only their ID and the stored filterable metadata; the text lives in the DocStore.
+func (idx *BM25Index) Search(ctx context.Context, userID string, folderIDs []string, query string, topK int, filters []schema.Filter) ([]*schema.Document, error) {
+	for _, filter := range filters {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	terms := Tokenize(query)
+	if len(terms) == 0 || topK <= 0 {
+		return []*schema.Document{}, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	folders := idx.segments[userID]
+	var searched []string
+	if len(folderIDs) == 0 {
+		for folderID := range folders {
+			searched = append(searched, folderID)
+		}
+	} else {
+		for _, folderID := range folderIDs {
+			if _, ok := folders[folderID]; ok {
+				searched = append(searched, folderID)
+			}
+		}
+	}
+
+	var numDocs, totalLen int
+	for _, folderID := range searched {
+		numDocs += len(folders[folderID].DocLens)
+		totalLen += folders[folderID].TotalLen
+	}
+	if numDocs == 0 {
+		return []*schema.Document{}, nil
+	}
+	avgLen := float64(totalLen) / float64(numDocs)
+
+	type hit struct {
+		id       string
+		folderID string
+		score    float64
+	}
+	hits := make(map[string]*hit)
+	for _, term := range uniqueTerms(terms) {
+		df := 0
+		for _, folderID := range searched {
+			df += len(folders[folderID].Postings[term])
+		}
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(numDocs)-float64(df)+0.5)/(float64(df)+0.5))
+
+		for _, folderID := range searched {
+			seg := folders[folderID]
+			for id, tf := range seg.Postings[term] {
+				if !seg.matches(id, filters) {
+					continue
+				}
+				norm := bm25K1 * (1 - bm25B + bm25B*float64(seg.DocLens[id])/avgLen)
+				h, ok := hits[id]
+				if !ok {
+					h = &hit{id: id, folderID: folderID}
+					hits[id] = h
+				}
+				h.score += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + norm)
+			}
+		}
+	}
+
+	ranked := make([]*hit, 0, len(hits))
+	for _, h := range hits {
+		ranked = append(ranked, h)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].id < ranked[j].id
+	})
+	if len(ranked) > topK {
+		ranked = ranked[:topK]
+	}
+
+	results := make([]*schema.Document, len(ranked))
+	for i, h := range ranked {
+		metadata := map[string]interface{}{
+			vectorstore.FieldUserID: userID,
+			MetadataKeyKeywordScore: h.score,
+		}
+		for field, value := range folders[h.folderID].Fields[h.id] {
+			metadata[field] = value
+			// Numeric fields are returned as int64, like the vector stores return them.
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && slices.Contains(vectorstore.NumericFields, field) {
+				metadata[field] = n
+			}
+		}
+		metadata[vectorstore.FieldFolderID] = h.folderID
+		results[i] = &schema.Document{ID: h.id, Metadata: metadata}
+	}
+	return results, nil
+}
+
+// remove drops a chunk from the segment if it is present.
+func (seg *segment) remove(id string) {
+	length, ok := seg.DocLens[id]
+	if !ok {
+		return
+	}
+	for _, term := range seg.Terms[id] {
+		postings := seg.Postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(seg.Postings, term)
+		}
+	}
+	delete(seg.Terms, id)
+	delete(seg.DocLens, id)
+	delete(seg.Fields, id)
+	seg.TotalLen -= length
+}
+
+// matches reports whether the stored fields of a chunk satisfy all filters. A missing field never matches.
+func (seg *segment) matches(id string, filters []schema.Filter) bool {
+	for _, filter := range filters {
+		value, ok := seg.Fields[id][filter.Field]
+		if !ok || !filter.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// markChanged schedules a snapshot, if one is configured. The caller must hold the write lock.
+func (idx *BM25Index) markChanged() {
+	if idx.changed == nil {
+		return
+	}
+	select {
+	case idx.changed <- struct{}{}:
+	default: // a snapshot is already scheduled and will include this change
+	}
+}
+
+// writeSnapshots writes a snapshot snapshotDelay after the index changes, until Close.
+func (idx *BM25Index) writeSnapshots() {
+	defer close(idx.stopped)
+	for {
+		select {
+		case <-idx.stop:
+			return
+		case <-idx.changed:
+		}
+
+		select {
+		case <-idx.stop:
+			return
+		case <-time.After(snapshotDelay):
+		}
+		if err := idx.persist(); err != nil {
+			idx.log.Error(fmt.Sprintf("Failed to write keyword index snapshot, retrying on the next change: %v", err))
+		}
+	}
+}
+
+// Close writes a final snapshot and stops the background snapshot writer.
+func (idx *BM25Index) Close() error {
+	if idx.stop == nil {
+		return nil
+	}
+	close(idx.stop)
+	<-idx.stopped
+	return idx.persist()
+}
+
+// persist writes the index to the snapshot path. The index is encoded under the read lock, so searches continue
+// meanwhile, and written to a temporary file that is renamed so a crash never leaves a truncated snapshot behind.
+func (idx *BM25Index) persist() error {
+	var buf bytes.Buffer
+	idx.mu.RLock()
+	err := gob.NewEncoder(&buf).Encode(idx.segments)
+	idx.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode keyword index snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(idx.snapshotPath), filepath.Base(idx.snapshotPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create keyword index snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write keyword index snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write keyword index snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), idx.snapshotPath); err != nil {
+		return fmt.Errorf("failed to replace keyword index snapshot: %w", err)
+	}
+	return nil
+}
+
+// Tokenize lower-cases text and splits it into terms. Runs of letters, digits and underscores form a term,
+// so identifiers and error codes such as "ERR_CONN_42" stay intact. Han characters are indexed one per term
+// since Chinese text has no word separators.
+func Tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	unique := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if _, ok := seen[term]; !ok {
+			seen[term] = struct{}{}
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// compile-time check to ensure BM25Index implements the KeywordIndex interface
+var _ interfaces.KeywordIndex = (*BM25Index)(nil)
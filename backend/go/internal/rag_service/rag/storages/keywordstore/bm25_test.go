@@ -0,0 +1,152 @@
+package keywordstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Hello, World!", []string{"hello", "world"}},
+		{"connect failed: ERR_CONN_42 (retry)", []string{"connect", "failed", "err_conn_42", "retry"}},
+		{"RAG检索 v2", []string{"rag", "检", "索", "v2"}},
+		{"  --  ", nil},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func chunk(id, text string, metadata map[string]interface{}) *schema.Document {
+	return &schema.Document{ID: id, Text: text, Metadata: metadata}
+}
+
+func ids(docs []*schema.Document) []string {
+	result := make([]string, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.ID
+	}
+	return result
+}
+
+func newTestIndex(t *testing.T, snapshotPath string) *BM25Index {
+	t.Helper()
+	idx, err := NewBM25Index(snapshotPath, *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewBM25Index() error = %v", err)
+	}
+	return idx
+}
+
+func search(t *testing.T, idx *BM25Index, userID string, folderIDs []string, query string, filters ...schema.Filter) []string {
+	t.Helper()
+	docs, err := idx.Search(context.Background(), userID, folderIDs, query, 10, filters)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	return ids(docs)
+}
+
+func TestBM25IndexSearch(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestIndex(t, "")
+	if err := idx.Add(ctx, "u1", "f1", []*schema.Document{
+		chunk("rare", "The deployment failed with ERR_CONN_42 on the gateway.", map[string]interface{}{schema.MetadataKeyDocType: "log"}),
+		chunk("common", "The gateway gateway gateway handles the traffic of the deployment.", map[string]interface{}{schema.MetadataKeyDocType: "doc"}),
+		chunk("other", "Quarterly revenue grew in every region.", nil),
+	}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := idx.Add(ctx, "u1", "f2", []*schema.Document{chunk("f2-doc", "The gateway restarted.", nil)}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := idx.Add(ctx, "u2", "f1", []*schema.Document{chunk("foreign", "ERR_CONN_42 gateway", nil)}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		folderIDs []string
+		query     string
+		filters   []schema.Filter
+		want      []string
+	}{
+		// The rare identifier outweighs repetitions of a common term.
+		{"rare term ranks first", []string{"f1"}, "gateway ERR_CONN_42", nil, []string{"rare", "common"}},
+		{"term frequency", []string{"f1"}, "gateway", nil, []string{"common", "rare"}},
+		{"all folders", nil, "restarted", nil, []string{"f2-doc"}},
+		{"folder scope", []string{"f1"}, "restarted", nil, []string{}},
+		{"filter", []string{"f1"}, "gateway", []schema.Filter{schema.Eq(schema.MetadataKeyDocType, "log")}, []string{"rare"}},
+		{"missing field never matches", []string{"f1"}, "revenue", []schema.Filter{schema.In(schema.MetadataKeyDocType, "log", "doc")}, []string{}},
+		{"no terms", nil, "?!", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search(t, idx, "u1", tt.folderIDs, tt.query, tt.filters...); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	docs, _ := idx.Search(ctx, "u1", []string{"f1"}, "deployment", 1, nil)
+	if len(docs) != 1 || docs[0].Metadata[vectorstore.FieldFolderID] != "f1" || docs[0].Metadata[MetadataKeyKeywordScore].(float64) <= 0 {
+		t.Errorf("Search() with topK 1 = %+v", docs)
+	}
+}
+
+func TestBM25IndexReplaceAndDelete(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestIndex(t, "")
+	_ = idx.Add(ctx, "u1", "f1", []*schema.Document{chunk("c1", "alpha beta", nil), chunk("c2", "beta gamma", nil)})
+
+	// Re-adding a chunk replaces its terms.
+	_ = idx.Add(ctx, "u1", "f1", []*schema.Document{chunk("c1", "delta", nil)})
+	if got := search(t, idx, "u1", nil, "alpha"); len(got) != 0 {
+		t.Errorf("old terms of a replaced chunk still match: %v", got)
+	}
+	// Both terms are as rare, so the shorter chunk ranks first.
+	if got := search(t, idx, "u1", nil, "delta beta"); !slices.Equal(got, []string{"c1", "c2"}) {
+		t.Errorf("Search() = %v, want [c1 c2]", got)
+	}
+
+	if err := idx.Delete(ctx, "u1", []string{"c1", "c2"}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(idx.segments) != 0 {
+		t.Errorf("empty segments were kept: %v", idx.segments)
+	}
+}
+
+func TestBM25IndexSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keywords.gob")
+	idx := newTestIndex(t, path)
+	_ = idx.Add(ctx, "u1", "f1", []*schema.Document{chunk("c1", "alpha beta", nil), chunk("c2", "beta", nil)})
+	_ = idx.Delete(ctx, "u1", []string{"c2"})
+	if err := idx.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reloaded := newTestIndex(t, path)
+	defer reloaded.Close()
+	if got := search(t, reloaded, "u1", nil, "beta"); !slices.Equal(got, []string{"c1"}) {
+		t.Errorf("Search() after reload = %v, want [c1]", got)
+	}
+	// The term lists are restored, so a reloaded chunk is removed completely.
+	_ = reloaded.Delete(ctx, "u1", []string{"c1"})
+	if len(reloaded.segments) != 0 {
+		t.Errorf("Delete() after reload left %v", reloaded.segments)
+	}
+}
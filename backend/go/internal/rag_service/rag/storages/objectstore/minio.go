@@ -0,0 +1,44 @@
+package objectstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/loaders"
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// MinIOStore is an implementation of the ObjectStore interface that keeps objects in a MinIO bucket. Objects are
+// addressed by "minio://bucket/key" URIs, which the MinIO loader reads as well.
+type MinIOStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewMinIOStore creates a new MinIOStore that stores objects in bucket, creating the bucket if it does not exist.
+func NewMinIOStore(ctx context.Context, client *minio.Client, bucket string) (*MinIOStore, error) {
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+		}
+	}
+	return &MinIOStore{client: client, bucket: bucket}, nil
+}
+
+// Put uploads data under key and returns its "minio://bucket/key" URI.
+func (s *MinIOStore) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s to bucket %s: %w", key, s.bucket, err)
+	}
+	return loaders.MinIOURI(s.bucket, key), nil
+}
+
+// compile-time check to ensure MinIOStore implements the ObjectStore interface
+var _ interfaces.ObjectStore = (*MinIOStore)(nil)
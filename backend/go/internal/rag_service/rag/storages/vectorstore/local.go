@@ -0,0 +1,183 @@
+package vectorstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"Jarvis_2.0/backend/go/pkg/util"
+)
+
+// snapshotDelay is how long changes are collected before a snapshot is written, so that indexing a batch of files
+// writes one snapshot instead of one per file.
+const snapshotDelay = 5 * time.Second
+
+// LocalStore is an embedded VectorStore that keeps vectors in process, for offline and test deployments that have
+// no Milvus. It searches exactly by L2 distance and stores the same scalar fields as MilvusStore, so filters and
+// result metadata behave alike: a missing field is stored as the empty string, or 0 for NumericFields, numeric
+// filters compare numbers and invalid filters are rejected.
+// If a snapshot path is configured, the store is loaded from it on creation and rewritten in the background
+// snapshotDelay after it changes; Close writes the final snapshot.
+type LocalStore struct {
+	log          logger.Logger
+	index        *util.FlatIndex
+	snapshotPath string
+	changed      chan struct{} // Signals the snapshot writer; buffered so a pending signal covers later changes
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// NewLocalStore creates a new LocalStore. An empty snapshotPath keeps the vectors in memory only.
+func NewLocalStore(snapshotPath string, log logger.Logger) (*LocalStore, error) {
+	s := &LocalStore{log: log, index: util.NewFlatIndex(), snapshotPath: snapshotPath}
+	if snapshotPath == "" {
+		return s, nil
+	}
+
+	index, err := util.NewFlatIndexFromFile(snapshotPath)
+	switch {
+	case err == nil:
+		s.index = index
+		s.log.Info(fmt.Sprintf("Loaded %d vectors from snapshot %s", index.Len(), snapshotPath))
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to load vector store snapshot: %w", err)
+	}
+
+	s.changed = make(chan struct{}, 1)
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.writeSnapshots()
+	return s, nil
+}
+
+// Add inserts the documents' embeddings and metadata fields. A document whose ID is already stored replaces it.
+func (s *LocalStore) Add(ctx context.Context, docs []*schema.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	for _, doc := range docs {
+		fields := make(map[string]string, len(metadataFields))
+		for _, field := range metadataFields {
+			fields[field] = ""
+			if slices.Contains(NumericFields, field) {
+				fields[field] = "0"
+			}
+			if value, ok := doc.Metadata[field]; ok && value != nil {
+				fields[field] = fmt.Sprintf("%v", value)
+			}
+		}
+		s.index.Upsert(doc.ID, doc.Embedding, fields)
+	}
+	s.markChanged()
+	return nil
+}
+
+// Query returns the topK documents nearest to the embedding that match all filters, nearest first.
+// Like MilvusStore, each result carries its L2 distance as "score" and its non-empty metadata fields.
+func (s *LocalStore) Query(ctx context.Context, embedding []float32, topK int, filters []schema.Filter) ([]*schema.Document, error) {
+	for _, filter := range filters {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	hits := s.index.Search(embedding, topK, func(fields map[string]string) bool {
+		for _, filter := range filters {
+			if !filter.Match(fields[filter.Field]) {
+				return false
+			}
+		}
+		return true
+	})
+
+	results := make([]*schema.Document, len(hits))
+	for i, hit := range hits {
+		doc := &schema.Document{
+			ID:       hit.ID,
+			Metadata: map[string]interface{}{"score": hit.Distance},
+		}
+		for field, value := range hit.Fields {
+			if !slices.Contains(NumericFields, field) {
+				if value != "" {
+					doc.Metadata[field] = value
+				}
+			} else if n, err := strconv.ParseInt(value, 10, 64); err == nil && n != 0 {
+				doc.Metadata[field] = n
+			}
+		}
+		results[i] = doc
+	}
+	return results, nil
+}
+
+// Delete removes the vectors with the given IDs. IDs that are not stored are ignored.
+func (s *LocalStore) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if s.index.Delete(ids...) > 0 {
+		s.markChanged()
+	}
+	return nil
+}
+
+// markChanged schedules a snapshot, if one is configured.
+func (s *LocalStore) markChanged() {
+	if s.changed == nil {
+		return
+	}
+	select {
+	case s.changed <- struct{}{}:
+	default: // a snapshot is already scheduled and will include this change
+	}
+}
+
+// writeSnapshots writes a snapshot snapshotDelay after the store changes, until Close.
+func (s *LocalStore) writeSnapshots() {
+	defer close(s.stopped)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.changed:
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(snapshotDelay):
+		}
+		if err := s.persist(); err != nil {
+			s.log.Error(fmt.Sprintf("Failed to write vector store snapshot, retrying on the next change: %v", err))
+		}
+	}
+}
+
+// Close writes a final snapshot and stops the background snapshot writer.
+func (s *LocalStore) Close() error {
+	if s.stop == nil {
+		return nil
+	}
+	close(s.stop)
+	<-s.stopped
+	return s.persist()
+}
+
+// persist writes the store to the snapshot path.
+func (s *LocalStore) persist() error {
+	if err := s.index.WriteToFile(s.snapshotPath); err != nil {
+		return fmt.Errorf("failed to write vector store snapshot: %w", err)
+	}
+	return nil
+}
+
+// compile-time check to ensure LocalStore implements the VectorStore interface
+var _ interfaces.VectorStore = (*LocalStore)(nil)
@@ -0,0 +1,136 @@
+package vectorstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"Jarvis_2.0/backend/go/pkg/logger"
+)
+
+func newTestDoc(id, userID, folderID, docType string, embedding ...float32) *schema.Document {
+	metadata := map[string]interface{}{FieldUserID: userID, FieldFolderID: folderID}
+	if docType != "" {
+		metadata[FieldDocType] = docType
+	}
+	return &schema.Document{ID: id, Embedding: embedding, Metadata: metadata}
+}
+
+func queryIDs(t *testing.T, store *LocalStore, embedding []float32, topK int, filters ...schema.Filter) []string {
+	t.Helper()
+	docs, err := store.Query(context.Background(), embedding, topK, filters)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids
+}
+
+func TestLocalStoreQuery(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore("", *logger.New("test", "", ""))
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	docs := []*schema.Document{
+		newTestDoc("a", "u1", "1", "pdf", 0, 0),
+		newTestDoc("b", "u1", "2", "md", 1, 0),
+		newTestDoc("c", "u1", "3", "", 2, 0),
+		newTestDoc("d", "u2", "1", "pdf", 0, 0.5),
+	}
+	docs[0].Metadata[FieldPageNumber] = int64(9)
+	docs[1].Metadata[FieldPageNumber] = int64(10)
+	if err = store.Add(ctx, docs); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		topK    int
+		filters []schema.Filter
+		want    []string
+	}{
+		{"nearest first", 10, nil, []string{"a", "d", "b", "c"}},
+		{"top k", 2, nil, []string{"a", "d"}},
+		{"eq", 10, []schema.Filter{schema.Eq(FieldUserID, "u1")}, []string{"a", "b", "c"}},
+		{"in", 10, []schema.Filter{schema.Eq(FieldUserID, "u1"), schema.In(FieldFolderID, "2", "3")}, []string{"b", "c"}},
+		// A missing field is stored as the empty string, as in Milvus.
+		{"missing field", 10, []schema.Filter{schema.Eq(FieldDocType, "")}, []string{"c"}},
+		{"range compares numbers", 10, []schema.Filter{{Field: FieldPageNumber, Op: schema.FilterOpRange, Numeric: true, Gte: strPtr("9")}}, []string{"a", "b"}},
+		// A missing numeric field is stored as 0.
+		{"missing number", 10, []schema.Filter{{Field: FieldPageNumber, Op: schema.FilterOpEq, Numeric: true, Values: []string{"0"}}}, []string{"d", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := queryIDs(t, store, []float32{0, 0}, tt.topK, tt.filters...)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Query() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Query() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	for _, filter := range []schema.Filter{
+		schema.In(FieldFolderID),
+		{Field: FieldFolderID, Op: schema.FilterOpRange, Gte: strPtr("2")},
+		{Field: FieldPageNumber, Op: schema.FilterOpRange, Numeric: true, Gte: strPtr("nine")},
+	} {
+		if _, err := store.Query(ctx, []float32{0, 0}, 10, []schema.Filter{filter}); err == nil {
+			t.Errorf("Query() with invalid filter %+v succeeded", filter)
+		}
+	}
+
+	docs, _ = store.Query(ctx, []float32{0, 0}, 1, []schema.Filter{schema.Eq(FieldUserID, "u2")})
+	if score := docs[0].Metadata["score"]; score != float32(0.25) {
+		t.Errorf("score = %v, want the squared L2 distance 0.25", score)
+	}
+	if _, ok := docs[0].Metadata[FieldPageLabel]; ok {
+		t.Errorf("empty field %q returned in metadata", FieldPageLabel)
+	}
+	if _, ok := docs[0].Metadata[FieldPageNumber]; ok {
+		t.Errorf("zero field %q returned in metadata", FieldPageNumber)
+	}
+	docs, _ = store.Query(ctx, []float32{0, 0}, 1, []schema.Filter{schema.Eq(FieldUserID, "u1")})
+	if page := docs[0].Metadata[FieldPageNumber]; page != int64(9) {
+		t.Errorf("%s = %#v, want int64(9)", FieldPageNumber, page)
+	}
+}
+
+func TestLocalStoreSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vectors", "snapshot.gob")
+	log := *logger.New("test", "", "")
+
+	store, err := NewLocalStore(path, log)
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+	if err := store.Add(ctx, []*schema.Document{newTestDoc("a", "u1", "1", "pdf", 0, 0), newTestDoc("b", "u1", "1", "pdf", 1, 1)}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.Delete(ctx, []string{"a"}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reloaded, err := NewLocalStore(path, log)
+	if err != nil {
+		t.Fatalf("NewLocalStore() reload error = %v", err)
+	}
+	got := queryIDs(t, reloaded, []float32{0, 0}, 10, schema.Eq(FieldFolderID, "1"))
+	if len(got) != 1 || got[0] != "b" {
+		t.Errorf("reloaded Query() = %v, want [b]", got)
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -5,7 +5,10 @@ import (
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"Jarvis_2.0/backend/go/internal/database/milvus"
 	"Jarvis_2.0/backend/go/pkg/logger"
@@ -15,21 +18,43 @@ import (
 
 const (
 	// Schema fields for the Milvus collection that we want to filter on or output.
-	FieldID        = "id"
-	FieldEmbedding = "embedding"
-	FieldDocType   = "doc_type"
-	FieldFileName  = "file_name"
-	FieldPageLabel = "page_label"
-	FieldUserID    = "user_id"
-	FieldFolderID  = "folder_id"
+	FieldID         = "id"
+	FieldEmbedding  = "embedding"
+	FieldDocType    = "doc_type"
+	FieldFileName   = "file_name"
+	FieldPageLabel  = "page_label"
+	FieldUserID     = "user_id"
+	FieldFolderID   = "folder_id"
+	FieldPageNumber = "page_number"
+	FieldIndexedAt  = "indexed_at"
 )
 
+// FilterableFields are the scalar fields that queries may filter on.
+// FieldUserID is deliberately absent: it is always set from the caller's identity for multi-tenancy.
+var FilterableFields = []string{FieldDocType, FieldFileName, FieldPageLabel, FieldFolderID, FieldPageNumber, FieldIndexedAt}
+
+// NumericFields are the filterable fields stored as Int64. They are the only fields that can be filtered by range;
+// the others are strings, whose order puts "10" before "9". A chunk without a value stores 0, which is left out of
+// search results.
+var NumericFields = []string{FieldPageNumber, FieldIndexedAt}
+
+// metadataFields are the scalar fields stored alongside each vector and returned with search results.
+var metadataFields = []string{FieldUserID, FieldFolderID, FieldDocType, FieldFileName, FieldPageLabel, FieldPageNumber, FieldIndexedAt}
+
 // MilvusStore is an adapter for the existing Milvus client to implement the VectorStore interface.
 // It uses the underlying milvus-sdk-go client to leverage advanced features like metadata filtering.
+//
+// The collection needs the VarChar fields "id" (primary key), "user_id" and "folder_id" and the FloatVector field
+// "embedding". The VarChar fields "doc_type", "file_name" and "page_label" and the Int64 fields "page_number" and
+// "indexed_at" are optional: collections created before they were stored keep working without them, but filtering
+// on them fails. To migrate such a collection, recreate it with the five fields and re-index the documents.
 type MilvusStore struct {
 	log        logger.Logger
 	client     client.Client // The raw client from the existing MilvusClient wrapper
 	collection string
+
+	fieldsMu sync.Mutex
+	fields   []string // The metadataFields the collection has, looked up on first use
 }
 
 // NewMilvusStore creates a new MilvusStore adapter.
@@ -52,11 +77,23 @@ func (s *MilvusStore) Add(ctx context.Context, docs []*schema.Document) error {
 		return nil
 	}
 
+	fields, err := s.storedFields(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Prepare columns from the documents
 	ids := make([]string, len(docs))
 	embeddings := make([][]float32, len(docs))
-	userIDs := make([]string, len(docs))
-	folderIDs := make([]string, len(docs)) // Assuming folderID is stored as string
+	metadata := make(map[string][]string, len(fields))
+	numbers := make(map[string][]int64, len(NumericFields))
+	for _, field := range fields {
+		if slices.Contains(NumericFields, field) {
+			numbers[field] = make([]int64, len(docs))
+		} else {
+			metadata[field] = make([]string, len(docs))
+		}
+	}
 
 	dim := 0
 	for i, doc := range docs {
@@ -66,22 +103,34 @@ func (s *MilvusStore) Add(ctx context.Context, docs []*schema.Document) error {
 			dim = len(doc.Embedding)
 		}
 
-		if uid, ok := doc.Metadata[FieldUserID].(string); ok {
-			userIDs[i] = uid
-		}
-		if fid, ok := doc.Metadata[FieldFolderID].(string); ok {
-			folderIDs[i] = fid
+		for _, field := range fields {
+			value, ok := doc.Metadata[field]
+			if !ok || value == nil {
+				continue
+			}
+			if _, numeric := numbers[field]; !numeric {
+				metadata[field][i] = fmt.Sprintf("%v", value)
+			} else if numbers[field][i], err = strconv.ParseInt(fmt.Sprintf("%v", value), 10, 64); err != nil {
+				return fmt.Errorf("document %s has non-integer %s %v", doc.ID, field, value)
+			}
 		}
 	}
 
 	// Create Milvus columns
-	idCol := entity.NewColumnVarChar(FieldID, ids)
-	embeddingCol := entity.NewColumnFloatVector(FieldEmbedding, dim, embeddings)
-	userIDCol := entity.NewColumnVarChar(FieldUserID, userIDs)
-	folderIDCol := entity.NewColumnVarChar(FieldFolderID, folderIDs)
+	columns := []entity.Column{
+		entity.NewColumnVarChar(FieldID, ids),
+		entity.NewColumnFloatVector(FieldEmbedding, dim, embeddings),
+	}
+	for _, field := range fields {
+		if data, numeric := numbers[field]; numeric {
+			columns = append(columns, entity.NewColumnInt64(field, data))
+		} else {
+			columns = append(columns, entity.NewColumnVarChar(field, metadata[field]))
+		}
+	}
 
 	s.log.Info(fmt.Sprintf("Inserting %d documents into Milvus collection: %s", len(docs), s.collection))
-	_, err := s.client.Insert(ctx, s.collection, "" /* default partition */, idCol, embeddingCol, userIDCol, folderIDCol)
+	_, err = s.client.Insert(ctx, s.collection, "" /* default partition */, columns...)
 	if err != nil {
 		s.log.Error(fmt.Sprintf("Failed to insert data into Milvus: %v", err))
 		return fmt.Errorf("failed to insert data into Milvus: %w", err)
@@ -91,11 +140,23 @@ func (s *MilvusStore) Add(ctx context.Context, docs []*schema.Document) error {
 }
 
 // Query performs a vector search in the Milvus collection with optional metadata filtering.
-func (s *MilvusStore) Query(ctx context.Context, embedding []float32, topK int, filters map[string]interface{}) ([]*schema.Document, error) {
-	filterExpr := s.buildFilterExpression(filters)
+func (s *MilvusStore) Query(ctx context.Context, embedding []float32, topK int, filters []schema.Filter) ([]*schema.Document, error) {
+	fields, err := s.storedFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range filters {
+		if !slices.Contains(fields, filter.Field) {
+			return nil, fmt.Errorf("milvus collection %q has no field %q to filter on", s.collection, filter.Field)
+		}
+	}
+	filterExpr, err := buildFilterExpression(filters)
+	if err != nil {
+		return nil, err
+	}
 
 	searchParams, _ := entity.NewIndexIvfFlatSearchParam(10)
-	outputFields := []string{FieldID, FieldUserID, FieldFolderID} // Adjust output fields as needed
+	outputFields := append([]string{FieldID}, fields...)
 
 	s.log.Info(fmt.Sprintf("Querying Milvus collection '%s' with filter: '%s'", s.collection, filterExpr))
 
@@ -127,12 +188,15 @@ func (s *MilvusStore) Query(ctx context.Context, embedding []float32, topK int,
 		}
 		idData := idCol.Data()
 
-		var userIDData, folderIDData []string
-		if userIDCol, ok := findColumn(FieldUserID).(*entity.ColumnVarChar); ok {
-			userIDData = userIDCol.Data()
-		}
-		if folderIDCol, ok := findColumn(FieldFolderID).(*entity.ColumnVarChar); ok {
-			folderIDData = folderIDCol.Data()
+		metadataData := make(map[string][]string, len(fields))
+		numberData := make(map[string][]int64, len(NumericFields))
+		for _, field := range fields {
+			switch col := findColumn(field).(type) {
+			case *entity.ColumnVarChar:
+				metadataData[field] = col.Data()
+			case *entity.ColumnInt64:
+				numberData[field] = col.Data()
+			}
 		}
 
 		for i := 0; i < res.ResultCount; i++ {
@@ -140,11 +204,15 @@ func (s *MilvusStore) Query(ctx context.Context, embedding []float32, topK int,
 				ID:       idData[i],
 				Metadata: map[string]interface{}{"score": res.Scores[i]},
 			}
-			if userIDData != nil {
-				doc.Metadata[FieldUserID] = userIDData[i]
+			for field, data := range metadataData {
+				if data[i] != "" {
+					doc.Metadata[field] = data[i]
+				}
 			}
-			if folderIDData != nil {
-				doc.Metadata[FieldFolderID] = folderIDData[i]
+			for field, data := range numberData {
+				if data[i] != 0 {
+					doc.Metadata[field] = data[i]
+				}
 			}
 			results = append(results, doc)
 		}
@@ -153,19 +221,99 @@ func (s *MilvusStore) Query(ctx context.Context, embedding []float32, topK int,
 	return results, nil
 }
 
-// buildFilterExpression creates a Milvus filter expression string from a map.
-func (s *MilvusStore) buildFilterExpression(filters map[string]interface{}) string {
-	if len(filters) == 0 {
-		return ""
+// Delete removes the vectors with the given IDs from the Milvus collection.
+func (s *MilvusStore) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = strconv.Quote(id)
+	}
+	expr := fmt.Sprintf("%s in [%s]", FieldID, strings.Join(quoted, ","))
+
+	s.log.Info(fmt.Sprintf("Deleting %d documents from Milvus collection: %s", len(ids), s.collection))
+	if err := s.client.Delete(ctx, s.collection, "" /* default partition */, expr); err != nil {
+		s.log.Error(fmt.Sprintf("Failed to delete data from Milvus: %v", err))
+		return fmt.Errorf("failed to delete data from Milvus: %w", err)
+	}
+	return nil
+}
+
+// storedFields returns the metadataFields the collection has. The lookup is cached once it succeeds.
+func (s *MilvusStore) storedFields(ctx context.Context) ([]string, error) {
+	s.fieldsMu.Lock()
+	defer s.fieldsMu.Unlock()
+	if s.fields != nil {
+		return s.fields, nil
+	}
+
+	collection, err := s.client.DescribeCollection(ctx, s.collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Milvus collection %s: %w", s.collection, err)
 	}
+	fields := make([]string, 0, len(metadataFields))
+	var missing []string
+	for _, field := range metadataFields {
+		if slices.ContainsFunc(collection.Schema.Fields, func(f *entity.Field) bool { return f.Name == field }) {
+			fields = append(fields, field)
+		} else {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		s.log.Warn(fmt.Sprintf("Milvus collection %s has no fields %v, they are neither stored nor filterable", s.collection, missing))
+	}
+	s.fields = fields
+	return fields, nil
+}
+
+// buildFilterExpression creates a Milvus boolean expression that is the conjunction of the given filters.
+func buildFilterExpression(filters []schema.Filter) (string, error) {
+	conditions := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		condition, err := filterCondition(filter)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, " and "), nil
+}
 
-	var conditions []string
-	for key, value := range filters {
-		if v, ok := value.(string); ok {
-			conditions = append(conditions, fmt.Sprintf(`%s == "%s"`, key, v))
+// filterCondition translates a single filter into a Milvus expression. String values are quoted and escaped;
+// values of numeric filters, which Validate has checked to be integers, are not.
+// Invalid filters are rejected instead of being dropped from the expression.
+func filterCondition(filter schema.Filter) (string, error) {
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+	literal := strconv.Quote
+	if filter.Numeric {
+		literal = func(s string) string { return s }
+	}
+	switch filter.Op {
+	case schema.FilterOpEq:
+		return fmt.Sprintf("%s == %s", filter.Field, literal(filter.Values[0])), nil
+	case schema.FilterOpIn:
+		quoted := make([]string, len(filter.Values))
+		for i, v := range filter.Values {
+			quoted[i] = literal(v)
+		}
+		return fmt.Sprintf("%s in [%s]", filter.Field, strings.Join(quoted, ",")), nil
+	default: // schema.FilterOpRange
+		var bounds []string
+		for _, bound := range []struct {
+			op    string
+			value *string
+		}{{">", filter.Gt}, {">=", filter.Gte}, {"<", filter.Lt}, {"<=", filter.Lte}} {
+			if bound.value != nil {
+				bounds = append(bounds, fmt.Sprintf("%s %s %s", filter.Field, bound.op, literal(*bound.value)))
+			}
 		}
+		return "(" + strings.Join(bounds, " and ") + ")", nil
 	}
-	return strings.Join(conditions, " and ")
 }
 
 // compile-time check to ensure MilvusStore implements the VectorStore interface
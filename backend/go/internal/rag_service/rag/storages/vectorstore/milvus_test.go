@@ -0,0 +1,49 @@
+package vectorstore
+
+import (
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"testing"
+)
+
+func TestBuildFilterExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []schema.Filter
+		want    string
+		wantErr bool
+	}{
+		{"no filters", nil, "", false},
+		{"eq", []schema.Filter{schema.Eq(FieldDocType, "pdf")}, `doc_type == "pdf"`, false},
+		{"quotes are escaped", []schema.Filter{schema.Eq(FieldFileName, `a"b.pdf`)}, `file_name == "a\"b.pdf"`, false},
+		{"in", []schema.Filter{schema.In(FieldFolderID, "1", "2")}, `folder_id in ["1","2"]`, false},
+		{
+			"range",
+			[]schema.Filter{{Field: FieldPageNumber, Op: schema.FilterOpRange, Numeric: true, Gte: strPtr("9"), Lt: strPtr("12")}},
+			`(page_number >= 9 and page_number < 12)`, false,
+		},
+		{"numeric in", []schema.Filter{{Field: FieldIndexedAt, Op: schema.FilterOpIn, Numeric: true, Values: []string{"1", "2"}}}, `indexed_at in [1,2]`, false},
+		{
+			"conjunction",
+			[]schema.Filter{schema.Eq(FieldUserID, "u1"), schema.In(FieldFolderID, "1")},
+			`user_id == "u1" and folder_id in ["1"]`, false,
+		},
+		{"eq without value", []schema.Filter{{Field: FieldDocType, Op: schema.FilterOpEq}}, "", true},
+		{"empty in", []schema.Filter{schema.In(FieldFolderID)}, "", true},
+		{"range without bounds", []schema.Filter{{Field: FieldPageNumber, Op: schema.FilterOpRange, Numeric: true}}, "", true},
+		{"range on a string field", []schema.Filter{{Field: FieldFileName, Op: schema.FilterOpRange, Gte: strPtr("a")}}, "", true},
+		{"numeric value is not an integer", []schema.Filter{{Field: FieldPageNumber, Op: schema.FilterOpEq, Numeric: true, Values: []string{"1 or 1 == 1"}}}, "", true},
+		{"unknown op", []schema.Filter{{Field: FieldDocType, Op: "like", Values: []string{"pdf"}}}, "", true},
+		{"invalid filter after a valid one", []schema.Filter{schema.Eq(FieldUserID, "u1"), {Field: FieldDocType, Op: "like"}}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildFilterExpression(tt.filters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildFilterExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildFilterExpression() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "Jarvis_2.0/api/proto/v1"
+	ragv1 "Jarvis_2.0/api/proto/v1/rag"
+	"Jarvis_2.0/backend/go/internal/agent"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AgentName is the name the knowledge base agent is registered under in etcd. The coordinator calls agents by the
+// name in their metadata, so both must be the same.
+const AgentName = "knowledge_base"
+
+// AgentServer exposes the RAG service as a sub-agent implementing the generic AgentService, so that the coordinator
+// can answer questions from a user's knowledge base.
+type AgentServer struct {
+	v1.UnimplementedAgentServiceServer
+	rag *Server
+}
+
+// NewAgentServer creates an AgentServer answering tasks with the given RAG server.
+func NewAgentServer(rag *Server) *AgentServer {
+	return &AgentServer{rag: rag}
+}
+
+// Metadata describes the knowledge base agent to the coordinator.
+func (a *AgentServer) Metadata() agent.AgentMetadata {
+	return agent.AgentMetadata{
+		Name:              AgentName,
+		Capability:        "在用户上传到知识库的文档中检索并回答问题，并注明答案引用的文件、页码和片段。",
+		InputDescription:  "一个需要根据用户自己的文档回答的问题，例如 '合同里约定的付款周期是多久？'。",
+		OutputDescription: "带有 [1] 这类引用标记的答案，以及一个 JSON 格式的来源列表，标记对应来源的 marker。",
+	}
+}
+
+// GetMetadata returns the agent's metadata.
+func (a *AgentServer) GetMetadata(ctx context.Context, _ *empty.Empty) (*v1.AgentMetadata, error) {
+	meta := a.Metadata()
+	return &v1.AgentMetadata{
+		Name:              meta.Name,
+		Capability:        meta.Capability,
+		InputDescription:  meta.InputDescription,
+		OutputDescription: meta.OutputDescription,
+	}, nil
+}
+
+// agentSource is a cited source as returned in the result of ExecuteTask.
+type agentSource struct {
+	Marker      int32  `json:"marker"`
+	DocumentID  string `json:"document_id"`
+	FileName    string `json:"file_name,omitempty"`
+	SourceURL   string `json:"source_url,omitempty"`
+	PageLabel   string `json:"page_label,omitempty"`
+	ChunkNumber int32  `json:"chunk_number"`
+}
+
+// ExecuteTask answers the question in the task from the knowledge base of the task's user, searching only the
+// task's folders if it has any. The result has two parts: the answer with citation markers, and a JSON object
+// {"sources": [...]} listing the sources the answer cites.
+func (a *AgentServer) ExecuteTask(ctx context.Context, task *v1.AgentTask) (*v1.AgentTask, error) {
+	if task.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "task %s has no user_id", task.GetTaskId())
+	}
+	question := taskQuestion(task)
+	if question == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "task %s has no question", task.GetTaskId())
+	}
+	if task.GetTimeoutSeconds() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(task.GetTimeoutSeconds())*time.Second)
+		defer cancel()
+	}
+	a.rag.log.Info(fmt.Sprintf("Agent task %s from %s for user %s", task.GetTaskId(), task.GetSourceAgentId(), task.GetUserId()))
+
+	resp, err := a.rag.Query(ctx, &ragv1.QueryRequest{
+		UserId:    task.GetUserId(),
+		Query:     question,
+		FolderIds: task.GetFolderIds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sources, err := json.Marshal(map[string][]agentSource{"sources": citedSources(resp)})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode sources: %v", err)
+	}
+
+	return &v1.AgentTask{
+		TaskId:        uuid.NewString(),
+		CorrelationId: task.GetCorrelationId(),
+		ParentTaskId:  task.GetTaskId(),
+		SourceAgentId: AgentName,
+		TargetAgentId: task.GetSourceAgentId(),
+		TaskName:      "Knowledge Base Answer",
+		Content: []*v1.Content{
+			{
+				Parts: []*v1.Part{
+					{Text: resp.GetAnswer()},
+					{Text: string(sources)},
+				},
+			},
+		},
+		UserId:    task.GetUserId(),
+		FolderIds: task.GetFolderIds(),
+	}, nil
+}
+
+// taskQuestion returns the question of a task. The coordinator sends the function call arguments as JSON, with the
+// question in "task_description"; any other text is taken as the question itself.
+func taskQuestion(task *v1.AgentTask) string {
+	if len(task.GetContent()) == 0 || len(task.GetContent()[0].GetParts()) == 0 {
+		return ""
+	}
+	text := task.GetContent()[0].GetParts()[0].GetText()
+	var args struct {
+		TaskDescription string `json:"task_description"`
+		Input           string `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(text), &args); err == nil {
+		if args.TaskDescription != "" {
+			return strings.TrimSpace(args.TaskDescription)
+		}
+		return strings.TrimSpace(args.Input)
+	}
+	return strings.TrimSpace(text)
+}
+
+// citedSources lists the sources cited by the answer, with the URL of web pages taken from the retrieved documents.
+func citedSources(resp *ragv1.QueryResponse) []agentSource {
+	sourceURLs := make(map[string]string, len(resp.GetSources()))
+	for _, doc := range resp.GetSources() {
+		if url := doc.GetMetadata()[schema.MetadataKeySourceURL]; url != "" {
+			sourceURLs[doc.GetId()] = url
+		}
+	}
+	sources := make([]agentSource, 0, len(resp.GetCitations()))
+	for _, citation := range resp.GetCitations() {
+		sources = append(sources, agentSource{
+			Marker:      citation.GetMarker(),
+			DocumentID:  citation.GetDocumentId(),
+			FileName:    citation.GetFileName(),
+			SourceURL:   sourceURLs[citation.GetDocumentId()],
+			PageLabel:   citation.GetPageLabel(),
+			ChunkNumber: citation.GetChunkNumber(),
+		})
+	}
+	return sources
+}
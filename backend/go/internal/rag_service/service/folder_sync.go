@@ -0,0 +1,494 @@
+package service
+
+import (
+	loaders2 "Jarvis_2.0/backend/go/internal/rag_service/rag/loaders"
+	pipeline2 "Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ragv1 "Jarvis_2.0/api/proto/v1/rag"
+	"Jarvis_2.0/backend/go/internal/models"
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Bounds of FolderSync.interval_seconds.
+const (
+	defaultFolderSyncInterval = 300
+	minFolderSyncInterval     = 30
+)
+
+// errFolderSyncRunning is returned by runFolderSync if the folder is already being synced.
+var errFolderSyncRunning = errors.New("folder sync is already running")
+
+// LeaderElector defines the interface for the leader election that lets a single replica run the polled syncs.
+type LeaderElector interface {
+	// Campaign blocks until leadership is acquired and returns a channel closed on leadership loss.
+	Campaign(ctx context.Context) (<-chan struct{}, error)
+	Resign(ctx context.Context) error
+}
+
+// folderSyncLocks tracks the folders being synced by this server, so that a folder is never synced twice at once.
+type folderSyncLocks struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func (l *folderSyncLocks) tryLock(folderID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running[folderID] {
+		return false
+	}
+	if l.running == nil {
+		l.running = make(map[string]bool)
+	}
+	l.running[folderID] = true
+	return true
+}
+
+func (l *folderSyncLocks) unlock(folderID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.running, folderID)
+}
+
+// SetFolderSync binds a local directory or a MinIO bucket prefix to a folder, replacing its previous sync source.
+// The folder is synced at the next poll.
+func (s *Server) SetFolderSync(ctx context.Context, req *ragv1.SetFolderSyncRequest) (*ragv1.FolderSync, error) {
+	s.log.Info(fmt.Sprintf("Received SetFolderSync request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	if err := s.checkFolder(ctx, req.GetUserId(), req.GetFolderId()); err != nil {
+		return nil, err
+	}
+
+	folderSync := &models.RagFolderSync{
+		UserID:          req.GetUserId(),
+		FolderID:        req.GetFolderId(),
+		IntervalSeconds: int(req.GetSync().GetIntervalSeconds()),
+		Status:          models.RagFolderSyncStatusIdle,
+	}
+	if folderSync.IntervalSeconds == 0 {
+		folderSync.IntervalSeconds = defaultFolderSyncInterval
+	}
+	if folderSync.IntervalSeconds < minFolderSyncInterval {
+		return nil, status.Errorf(codes.InvalidArgument, "interval_seconds must be at least %d", minFolderSyncInterval)
+	}
+
+	switch source := req.GetSync().GetSource().(type) {
+	case *ragv1.FolderSync_Directory:
+		if len(s.syncRoots) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "directory sync sources are not enabled")
+		}
+		if !filepath.IsAbs(source.Directory) {
+			return nil, status.Errorf(codes.InvalidArgument, "directory must be an absolute path")
+		}
+		dir, err := filepath.EvalSymlinks(source.Directory)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "directory %s does not exist", source.Directory)
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is not a directory", source.Directory)
+		}
+		if err := s.checkSyncDirectory(dir); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+		}
+		folderSync.Source = models.RagFolderSyncSourceDirectory
+		folderSync.Directory = dir
+	case *ragv1.FolderSync_Minio:
+		if s.minioClient == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "MinIO sync sources are not enabled")
+		}
+		bucket := source.Minio.GetBucket()
+		if bucket == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "bucket is required")
+		}
+		exists, err := s.minioClient.BucketExists(ctx, bucket)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check bucket %s: %v", bucket, err)
+		}
+		if !exists {
+			return nil, status.Errorf(codes.InvalidArgument, "bucket %s does not exist", bucket)
+		}
+		folderSync.Source = models.RagFolderSyncSourceMinIO
+		folderSync.Bucket = bucket
+		folderSync.Prefix = strings.TrimPrefix(source.Minio.GetPrefix(), "/")
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "a directory or minio source is required")
+	}
+
+	existing, err := s.folderSyncDal.GetSync(ctx, req.GetUserId(), req.GetFolderId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get folder sync: %v", err)
+	}
+	if existing != nil {
+		folderSync.ID = existing.ID
+		folderSync.CreatedAt = existing.CreatedAt
+	}
+	if err := s.folderSyncDal.SaveSync(ctx, folderSync); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save folder sync: %v", err)
+	}
+	return toProtoFolderSync(folderSync), nil
+}
+
+// DeleteFolderSync unbinds the sync source of a folder. The documents indexed from it stay in the folder.
+func (s *Server) DeleteFolderSync(ctx context.Context, req *ragv1.DeleteFolderSyncRequest) (*ragv1.DeleteFolderSyncResponse, error) {
+	s.log.Info(fmt.Sprintf("Received DeleteFolderSync request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	deleted, err := s.folderSyncDal.DeleteSync(ctx, req.GetUserId(), req.GetFolderId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete folder sync: %v", err)
+	}
+	if !deleted {
+		return nil, status.Errorf(codes.NotFound, "folder %s has no sync source", req.GetFolderId())
+	}
+	return &ragv1.DeleteFolderSyncResponse{}, nil
+}
+
+// SyncFolder syncs a folder with its source now and returns the result.
+func (s *Server) SyncFolder(ctx context.Context, req *ragv1.SyncFolderRequest) (*ragv1.FolderSync, error) {
+	s.log.Info(fmt.Sprintf("Received SyncFolder request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	folderSync, err := s.folderSyncDal.GetSync(ctx, req.GetUserId(), req.GetFolderId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get folder sync: %v", err)
+	}
+	if folderSync == nil {
+		return nil, status.Errorf(codes.NotFound, "folder %s has no sync source", req.GetFolderId())
+	}
+	if err := s.runFolderSync(ctx, folderSync); err != nil {
+		if errors.Is(err, errFolderSyncRunning) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return toProtoFolderSync(folderSync), nil
+}
+
+// checkFolder returns a gRPC error unless the folder exists and belongs to the user.
+func (s *Server) checkFolder(ctx context.Context, userID, folderID string) error {
+	id, err := strconv.ParseUint(folderID, 10, 64)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid folder id: %v", err)
+	}
+	folder, err := s.folderDal.GetFolder(ctx, userID, uint(id))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get folder: %v", err)
+	}
+	if folder == nil {
+		return status.Errorf(codes.NotFound, "folder not found")
+	}
+	return nil
+}
+
+// checkSyncDirectory returns an error unless dir, resolved of symlinks, lies within one of the sync roots. It is
+// checked when a directory is bound and again before every sync, since the directory may have been replaced by a
+// symlink in between.
+func (s *Server) checkSyncDirectory(dir string) error {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory %s: %w", dir, err)
+	}
+	for _, root := range s.syncRoots {
+		root, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("directory %s is outside the allowed sync roots", dir)
+}
+
+// RunFolderSyncs polls the sync sources of all folders every pollInterval until ctx is cancelled, syncing each folder
+// whose own interval has passed since its last run. Folders are synced concurrently with each other.
+// With an elector, only the replica holding leadership polls, so that replicas do not sync the same folder at once;
+// syncs in progress are cancelled when leadership is lost. If elector is nil, this instance always polls.
+func (s *Server) RunFolderSyncs(ctx context.Context, pollInterval time.Duration, elector LeaderElector) {
+	for {
+		// A nil channel never fires, so without an elector this instance leads forever.
+		var lost <-chan struct{}
+		if elector != nil {
+			var err error
+			lost, err = elector.Campaign(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.log.Error(fmt.Sprintf("Folder sync leader election failed: %v", err))
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(pollInterval):
+				}
+				continue
+			}
+		}
+
+		s.log.Info("Acquired folder sync leadership, polling sync sources")
+		if s.pollFolderSyncs(ctx, pollInterval, lost) {
+			if elector != nil {
+				if err := elector.Resign(context.Background()); err != nil {
+					s.log.Warn(fmt.Sprintf("Failed to resign folder sync leadership: %v", err))
+				}
+			}
+			return
+		}
+		s.log.Warn("Lost folder sync leadership")
+	}
+}
+
+// pollFolderSyncs starts the due folder syncs on every poll until leadership is lost or ctx is cancelled.
+// It reports whether RunFolderSyncs should stop.
+func (s *Server) pollFolderSyncs(ctx context.Context, pollInterval time.Duration, lost <-chan struct{}) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		syncs, err := s.folderSyncDal.ListSyncs(ctx)
+		if err != nil {
+			s.log.Error(fmt.Sprintf("Failed to list folder syncs: %v", err))
+		}
+		now := time.Now()
+		for _, folderSync := range syncs {
+			if folderSync.Due(now) {
+				go func() {
+					if err := s.runFolderSync(ctx, folderSync); err != nil && !errors.Is(err, errFolderSyncRunning) {
+						s.log.Error(fmt.Sprintf("Failed to sync folder %s: %v", folderSync.FolderID, err))
+					}
+				}()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return true
+		case <-lost:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// runFolderSync brings a folder in line with its source: files that are new or whose version changed are indexed,
+// and the documents of files that disappeared from the source are deleted. Documents outside the source, e.g. indexed
+// by hand, are left alone. The outcome is recorded on folderSync; the returned error only reports failures to run.
+func (s *Server) runFolderSync(ctx context.Context, folderSync *models.RagFolderSync) error {
+	if !s.folderSyncLocks.tryLock(folderSync.FolderID) {
+		return errFolderSyncRunning
+	}
+	defer s.folderSyncLocks.unlock(folderSync.FolderID)
+
+	s.log.Info(fmt.Sprintf("Syncing folder %s with %s", folderSync.FolderID, folderSyncScope(folderSync)))
+	folderSync.Status = models.RagFolderSyncStatusRunning
+	if err := s.folderSyncDal.UpdateSyncStatus(ctx, folderSync); err != nil {
+		return fmt.Errorf("failed to update folder sync: %w", err)
+	}
+
+	err := s.syncFolderFiles(ctx, folderSync)
+	now := time.Now()
+	folderSync.LastRunAt = &now
+	folderSync.Status = models.RagFolderSyncStatusIdle
+	folderSync.LastError = ""
+	if err != nil {
+		folderSync.Status = models.RagFolderSyncStatusFailed
+		folderSync.LastError = err.Error()
+	}
+	if err := s.folderSyncDal.UpdateSyncStatus(context.Background(), folderSync); err != nil {
+		return fmt.Errorf("failed to update folder sync: %w", err)
+	}
+	s.log.Info(fmt.Sprintf("Synced folder %s: %d added, %d updated, %d deleted, %d unchanged, %d failed",
+		folderSync.FolderID, folderSync.Added, folderSync.Updated, folderSync.Deleted, folderSync.Unchanged, folderSync.Failed))
+	return nil
+}
+
+// syncFolderFiles does the work of runFolderSync, counting the outcomes on folderSync.
+func (s *Server) syncFolderFiles(ctx context.Context, folderSync *models.RagFolderSync) error {
+	folderSync.Added, folderSync.Updated, folderSync.Deleted, folderSync.Unchanged, folderSync.Failed = 0, 0, 0, 0, 0
+
+	versions, err := s.listSyncFiles(ctx, folderSync)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", folderSyncScope(folderSync), err)
+	}
+	docs, err := s.documentDal.ListDocumentsByFolder(ctx, folderSync.UserID, folderSync.FolderID)
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+	synced := make(map[string]*models.RagDocument)
+	for _, doc := range docs {
+		if strings.HasPrefix(doc.Source, folderSyncScope(folderSync)) {
+			synced[doc.Source] = doc
+		}
+	}
+
+	indexingPipeline, err := s.newIndexingPipeline()
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(s.indexWorkers)
+	for source, version := range versions {
+		doc := synced[source]
+		if doc != nil && doc.SyncVersion == version {
+			mu.Lock()
+			folderSync.Unchanged++
+			mu.Unlock()
+			continue
+		}
+		g.Go(func() error {
+			outcome := s.syncFile(ctx, indexingPipeline, folderSync, source, version, doc)
+			mu.Lock()
+			defer mu.Unlock()
+			*outcome++
+			return nil
+		})
+	}
+	for source, doc := range synced {
+		if _, ok := versions[source]; ok {
+			continue
+		}
+		g.Go(func() error {
+			err := s.deleteDocument(ctx, doc)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				s.log.Error(fmt.Sprintf("Failed to delete document of removed file %s: %v", source, err))
+				folderSync.Failed++
+				return nil
+			}
+			folderSync.Deleted++
+			return nil
+		})
+	}
+	g.Wait()
+	return ctx.Err()
+}
+
+// syncFile indexes a new or changed file of a sync source and returns the counter of folderSync for its outcome.
+// The file's version is recorded once it has been indexed, so that it is not loaded again until it changes. A file
+// whose type cannot be indexed is counted as unchanged. It has no document of its own to record the version on, so
+// the version is only recorded on the document of an earlier version of the file, if there is one; otherwise the
+// file is looked at again at every run. Other failures are retried at the next run.
+func (s *Server) syncFile(ctx context.Context, indexingPipeline *pipeline2.IndexingPipeline, folderSync *models.RagFolderSync, source, version string, doc *models.RagDocument) *int {
+	result, err := s.indexPath(ctx, indexingPipeline, folderSync.UserID, folderSync.FolderID, source, false, nil, func(*ragv1.IndexResponse) error { return nil })
+	unsupported := errors.Is(err, loaders2.ErrUnsupportedType)
+	if err == nil || unsupported {
+		if indexed, lookupErr := s.documentDal.GetDocumentBySource(context.Background(), folderSync.UserID, folderSync.FolderID, source); lookupErr != nil {
+			s.log.Error(fmt.Sprintf("Failed to look up synced document %s: %v", source, lookupErr))
+		} else if indexed != nil {
+			if err := s.documentDal.SetSyncVersion(context.Background(), indexed.ID, version); err != nil {
+				s.log.Error(fmt.Sprintf("Failed to record version of synced document %s: %v", source, err))
+			}
+		}
+	}
+
+	switch {
+	case unsupported:
+		// Files that are not documents, such as images, are expected in a synced directory.
+		return &folderSync.Unchanged
+	case err != nil:
+		s.log.Warn(fmt.Sprintf("Failed to index synced file %s: %v", source, err))
+		return &folderSync.Failed
+	case doc == nil:
+		return &folderSync.Added
+	case result.Skipped:
+		return &folderSync.Unchanged
+	default:
+		return &folderSync.Updated
+	}
+}
+
+// listSyncFiles lists the files of a sync source by their source path or URI, mapped to a version that changes
+// whenever the file does: the modification time and size of a local file, or the ETag of a MinIO object.
+// Hidden files and directories are ignored.
+func (s *Server) listSyncFiles(ctx context.Context, folderSync *models.RagFolderSync) (map[string]string, error) {
+	versions := make(map[string]string)
+	switch folderSync.Source {
+	case models.RagFolderSyncSourceDirectory:
+		if err := s.checkSyncDirectory(folderSync.Directory); err != nil {
+			return nil, err
+		}
+		err := filepath.WalkDir(folderSync.Directory, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != folderSync.Directory && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			versions[path] = fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+			return nil
+		})
+		return versions, err
+	case models.RagFolderSyncSourceMinIO:
+		if s.minioClient == nil {
+			return nil, errors.New("MinIO sync sources are not enabled")
+		}
+		for object := range s.minioClient.ListObjects(ctx, folderSync.Bucket, minio.ListObjectsOptions{Prefix: folderSync.Prefix, Recursive: true}) {
+			if object.Err != nil {
+				return nil, object.Err
+			}
+			if strings.HasSuffix(object.Key, "/") || strings.HasPrefix(filepath.Base(object.Key), ".") {
+				continue
+			}
+			versions[loaders2.MinIOURI(folderSync.Bucket, object.Key)] = object.ETag
+		}
+		return versions, nil
+	default:
+		return nil, fmt.Errorf("unknown sync source %q", folderSync.Source)
+	}
+}
+
+// folderSyncScope returns the prefix shared by the sources of all documents indexed from a sync source.
+func folderSyncScope(folderSync *models.RagFolderSync) string {
+	if folderSync.Source == models.RagFolderSyncSourceMinIO {
+		return loaders2.MinIOURI(folderSync.Bucket, folderSync.Prefix)
+	}
+	return strings.TrimSuffix(folderSync.Directory, string(filepath.Separator)) + string(filepath.Separator)
+}
+
+func toProtoFolderSync(folderSync *models.RagFolderSync) *ragv1.FolderSync {
+	resp := &ragv1.FolderSync{
+		IntervalSeconds: int32(folderSync.IntervalSeconds),
+		Status:          string(folderSync.Status),
+		LastError:       folderSync.LastError,
+		Added:           int32(folderSync.Added),
+		Updated:         int32(folderSync.Updated),
+		Deleted:         int32(folderSync.Deleted),
+		Unchanged:       int32(folderSync.Unchanged),
+		Failed:          int32(folderSync.Failed),
+	}
+	if folderSync.Source == models.RagFolderSyncSourceMinIO {
+		resp.Source = &ragv1.FolderSync_Minio{Minio: &ragv1.MinIOSource{Bucket: folderSync.Bucket, Prefix: folderSync.Prefix}}
+	} else {
+		resp.Source = &ragv1.FolderSync_Directory{Directory: folderSync.Directory}
+	}
+	if folderSync.LastRunAt != nil {
+		resp.LastRunAt = folderSync.LastRunAt.String()
+	}
+	return resp
+}
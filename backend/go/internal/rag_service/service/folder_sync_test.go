@@ -0,0 +1,50 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckSyncDirectory(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	for _, dir := range []string{filepath.Join(root, "docs", "nested"), filepath.Join(root, "..docs")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "docs"), filepath.Join(outside, "inside")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{syncRoots: []string{root}}
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{"root", root, false},
+		{"nested", filepath.Join(root, "docs", "nested"), false},
+		{"name starting with dots", filepath.Join(root, "..docs"), false},
+		{"dot dot", filepath.Join(root, "docs", "..", ".."), true},
+		{"outside", outside, true},
+		{"symlink escaping the root", filepath.Join(root, "escape"), true},
+		{"symlink into the root", filepath.Join(outside, "inside"), false},
+		{"missing", filepath.Join(root, "missing"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.checkSyncDirectory(tt.dir); (err != nil) != tt.wantErr {
+				t.Errorf("checkSyncDirectory(%s) error = %v, wantErr %v", tt.dir, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (&Server{}).checkSyncDirectory(root); err == nil {
+		t.Error("checkSyncDirectory() without sync roots succeeded")
+	}
+}
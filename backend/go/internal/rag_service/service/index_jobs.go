@@ -0,0 +1,296 @@
+package service
+
+import (
+	pipeline2 "Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ragv1 "Jarvis_2.0/api/proto/v1/rag"
+	"Jarvis_2.0/backend/go/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// indexJobWatchInterval is how often WatchIndexJob polls the job for changes.
+const indexJobWatchInterval = time.Second
+
+// ErrIndexJobNotFound is returned by RunIndexJob for a job that does not exist.
+var ErrIndexJobNotFound = errors.New("index job not found")
+
+// IndexJobStore persists indexing jobs and the results of their files. It is implemented by dal.IndexJobDAL.
+type IndexJobStore interface {
+	CreateJob(ctx context.Context, job *models.RagIndexJob) error
+	GetJob(ctx context.Context, userID, jobID string) (*models.RagIndexJob, error)
+	UpdateJob(ctx context.Context, job *models.RagIndexJob) error
+	UpdateFile(ctx context.Context, file *models.RagIndexJobFile) error
+}
+
+// IndexJobPublisher queues indexing jobs for the indexing workers. It is implemented by publisher.IndexJobPublisher.
+type IndexJobPublisher interface {
+	Publish(ctx context.Context, job *models.RagIndexJob) error
+}
+
+// indexFileFunc indexes one path of a job.
+type indexFileFunc func(ctx context.Context, path string) (*pipeline2.IndexResult, error)
+
+// SubmitIndexJob registers an indexing job and queues it on Kafka for the indexing workers.
+func (s *Server) SubmitIndexJob(ctx context.Context, req *ragv1.IndexRequest) (*ragv1.IndexJob, error) {
+	s.log.Info(fmt.Sprintf("Received SubmitIndexJob request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	if req.GetUserId() == "" || req.GetFolderId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id and folder_id are required")
+	}
+	if len(req.GetPaths()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "paths must not be empty")
+	}
+	crawl, err := crawlOptions(req.GetCrawl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	job := &models.RagIndexJob{
+		ID:       uuid.NewString(),
+		UserID:   req.GetUserId(),
+		FolderID: req.GetFolderId(),
+		Force:    req.GetReindex(),
+		Crawl:    crawl,
+		Status:   models.RagIndexJobStatusQueued,
+	}
+	// Files are indexed concurrently, so each path may only appear once.
+	seen := make(map[string]bool, len(req.GetPaths()))
+	for _, path := range req.GetPaths() {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		job.Files = append(job.Files, models.RagIndexJobFile{Path: path, Status: models.RagIndexJobFileStatusPending})
+	}
+	if err := s.indexJobDal.CreateJob(ctx, job); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create index job: %v", err)
+	}
+
+	if err := s.jobPublisher.Publish(ctx, job); err != nil {
+		job.Status = models.RagIndexJobStatusFailed
+		job.Error = fmt.Sprintf("failed to queue job: %v", err)
+		if err := s.indexJobDal.UpdateJob(context.Background(), job); err != nil {
+			s.log.Error(fmt.Sprintf("Failed to mark index job %s as failed: %v", job.ID, err))
+		}
+		return nil, status.Errorf(codes.Internal, "failed to queue index job: %v", err)
+	}
+
+	s.log.Info(fmt.Sprintf("Queued index job %s with %d paths", job.ID, len(job.Files)))
+	return toProtoIndexJob(job), nil
+}
+
+// GetIndexJob returns the status of an indexing job.
+func (s *Server) GetIndexJob(ctx context.Context, req *ragv1.GetIndexJobRequest) (*ragv1.IndexJob, error) {
+	job, err := s.getIndexJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoIndexJob(job), nil
+}
+
+// WatchIndexJob sends the status of an indexing job, and again whenever it changes, until the job has finished.
+func (s *Server) WatchIndexJob(req *ragv1.GetIndexJobRequest, stream ragv1.RagService_WatchIndexJobServer) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(indexJobWatchInterval)
+	defer ticker.Stop()
+
+	var last *ragv1.IndexJob
+	for {
+		job, err := s.getIndexJob(ctx, req)
+		if err != nil {
+			return err
+		}
+		current := toProtoIndexJob(job)
+		if last == nil || !proto.Equal(last, current) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+		if job.Status == models.RagIndexJobStatusCompleted || job.Status == models.RagIndexJobStatusFailed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) getIndexJob(ctx context.Context, req *ragv1.GetIndexJobRequest) (*models.RagIndexJob, error) {
+	job, err := s.indexJobDal.GetJob(ctx, req.GetUserId(), req.GetJobId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get index job: %v", err)
+	}
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "index job %s not found", req.GetJobId())
+	}
+	return job, nil
+}
+
+// RunIndexJob processes a queued indexing job, indexing up to indexWorkers of its files concurrently.
+// The result of each file is recorded as soon as it is known. Files that are already done are skipped, so running
+// a job again after an interruption resumes it; running a finished job does nothing.
+// An error is returned if the job could not be run to the end, in which case it should be run again later.
+func (s *Server) RunIndexJob(ctx context.Context, userID, jobID string) error {
+	job, err := s.startIndexJob(ctx, userID, jobID)
+	if err != nil || job == nil {
+		return err
+	}
+
+	indexingPipeline, err := s.newIndexingPipeline()
+	if err != nil {
+		job.Error = err.Error()
+		return s.finishIndexJob(job)
+	}
+	return s.runIndexJobFiles(ctx, job, func(ctx context.Context, path string) (*pipeline2.IndexResult, error) {
+		return s.indexPath(ctx, indexingPipeline, job.UserID, job.FolderID, path, job.Force, job.Crawl, func(*ragv1.IndexResponse) error { return nil })
+	})
+}
+
+// startIndexJob loads a job and marks it as running. It returns nil if the job has already finished.
+func (s *Server) startIndexJob(ctx context.Context, userID, jobID string) (*models.RagIndexJob, error) {
+	job, err := s.indexJobDal.GetJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load index job %s: %w", jobID, err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("%w: %s", ErrIndexJobNotFound, jobID)
+	}
+	if job.Status == models.RagIndexJobStatusCompleted || job.Status == models.RagIndexJobStatusFailed {
+		s.log.Info(fmt.Sprintf("Index job %s has already finished, ignoring", jobID))
+		return nil, nil
+	}
+
+	s.log.Info(fmt.Sprintf("Running index job %s for user %s, folder %s", jobID, userID, job.FolderID))
+	if job.StartedAt == nil {
+		now := time.Now()
+		job.StartedAt = &now
+	}
+	job.Status = models.RagIndexJobStatusRunning
+	if err := s.indexJobDal.UpdateJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to update index job %s: %w", jobID, err)
+	}
+	return job, nil
+}
+
+// runIndexJobFiles indexes the files of a running job that are not done yet with index, up to indexWorkers of them
+// concurrently, and records the final status of the job once all of them are done.
+func (s *Server) runIndexJobFiles(ctx context.Context, job *models.RagIndexJob, index indexFileFunc) error {
+	var g errgroup.Group
+	g.SetLimit(s.indexWorkers)
+	for i := range job.Files {
+		file := &job.Files[i]
+		if file.Status.Done() {
+			continue
+		}
+		g.Go(func() error {
+			return s.runIndexJobFile(ctx, file, index)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		// Files that were not finished stay pending or indexing and are picked up when the job runs again.
+		return fmt.Errorf("index job %s interrupted: %w", job.ID, err)
+	}
+	return s.finishIndexJob(job)
+}
+
+// runIndexJobFile indexes one file of a job and records the result. It only returns an error if the result could
+// not be recorded or the job is being cancelled.
+func (s *Server) runIndexJobFile(ctx context.Context, file *models.RagIndexJobFile, index indexFileFunc) error {
+	file.Status = models.RagIndexJobFileStatusIndexing
+	if err := s.indexJobDal.UpdateFile(ctx, file); err != nil {
+		return err
+	}
+
+	result, err := index(ctx, file.Path)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	switch {
+	case err != nil:
+		file.Status = models.RagIndexJobFileStatusFailed
+		file.Error = err.Error()
+	case result.Skipped:
+		file.Status = models.RagIndexJobFileStatusSkipped
+	default:
+		file.Status = models.RagIndexJobFileStatusIndexed
+		file.ChunkCount = len(result.ChunkIDs)
+	}
+	return s.indexJobDal.UpdateFile(context.Background(), file)
+}
+
+// finishIndexJob records the final status of a job. A job fails if it has an error or none of its files succeeded.
+func (s *Server) finishIndexJob(job *models.RagIndexJob) error {
+	_, _, _, failed := indexJobCounts(job)
+	job.Status = models.RagIndexJobStatusCompleted
+	if job.Error != "" || (len(job.Files) > 0 && failed == len(job.Files)) {
+		job.Status = models.RagIndexJobStatusFailed
+	}
+	now := time.Now()
+	job.FinishedAt = &now
+
+	if err := s.indexJobDal.UpdateJob(context.Background(), job); err != nil {
+		return fmt.Errorf("failed to update index job %s: %w", job.ID, err)
+	}
+	s.log.Info(fmt.Sprintf("Index job %s %s: %d of %d files failed", job.ID, job.Status, failed, len(job.Files)))
+	return nil
+}
+
+// indexJobCounts counts the files of a job by outcome.
+func indexJobCounts(job *models.RagIndexJob) (total, indexed, skipped, failed int) {
+	for _, file := range job.Files {
+		switch file.Status {
+		case models.RagIndexJobFileStatusIndexed:
+			indexed++
+		case models.RagIndexJobFileStatusSkipped:
+			skipped++
+		case models.RagIndexJobFileStatusFailed:
+			failed++
+		}
+	}
+	return len(job.Files), indexed, skipped, failed
+}
+
+func toProtoIndexJob(job *models.RagIndexJob) *ragv1.IndexJob {
+	total, indexed, skipped, failed := indexJobCounts(job)
+	resp := &ragv1.IndexJob{
+		JobId:        job.ID,
+		FolderId:     job.FolderID,
+		Status:       string(job.Status),
+		Error:        job.Error,
+		TotalFiles:   int32(total),
+		IndexedFiles: int32(indexed),
+		SkippedFiles: int32(skipped),
+		FailedFiles:  int32(failed),
+		Files:        make([]*ragv1.IndexJobFile, 0, len(job.Files)),
+	}
+	if !job.CreatedAt.IsZero() {
+		resp.CreatedAt = job.CreatedAt.String()
+	}
+	if job.StartedAt != nil {
+		resp.StartedAt = job.StartedAt.String()
+	}
+	if job.FinishedAt != nil {
+		resp.FinishedAt = job.FinishedAt.String()
+	}
+	for _, file := range job.Files {
+		resp.Files = append(resp.Files, &ragv1.IndexJobFile{
+			Path:       file.Path,
+			Status:     string(file.Status),
+			Error:      file.Error,
+			ChunkCount: int32(file.ChunkCount),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	pipeline2 "Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	ragv1 "Jarvis_2.0/api/proto/v1/rag"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// memoryJobStore is an IndexJobStore keeping jobs in memory and recording every status it is given.
+type memoryJobStore struct {
+	mu           sync.Mutex
+	jobs         map[string]*models.RagIndexJob
+	jobStatuses  []models.RagIndexJobStatus
+	fileStatuses map[string][]models.RagIndexJobFileStatus // By path
+	err          error                                     // Returned by GetJob and UpdateFile if set
+}
+
+func newMemoryJobStore(jobs ...*models.RagIndexJob) *memoryJobStore {
+	s := &memoryJobStore{jobs: make(map[string]*models.RagIndexJob), fileStatuses: make(map[string][]models.RagIndexJobFileStatus)}
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+	}
+	return s
+}
+
+func (s *memoryJobStore) CreateJob(_ context.Context, job *models.RagIndexJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *job
+	s.jobs[job.ID] = &copied
+	return nil
+}
+
+func (s *memoryJobStore) GetJob(_ context.Context, userID, jobID string) (*models.RagIndexJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	job, ok := s.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, nil
+	}
+	copied := *job
+	copied.Files = slices.Clone(job.Files)
+	return &copied, nil
+}
+
+func (s *memoryJobStore) UpdateJob(_ context.Context, job *models.RagIndexJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.jobs[job.ID]
+	stored.Status, stored.Error, stored.StartedAt, stored.FinishedAt = job.Status, job.Error, job.StartedAt, job.FinishedAt
+	s.jobStatuses = append(s.jobStatuses, job.Status)
+	return nil
+}
+
+func (s *memoryJobStore) UpdateFile(_ context.Context, file *models.RagIndexJobFile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.fileStatuses[file.Path] = append(s.fileStatuses[file.Path], file.Status)
+	return nil
+}
+
+// fakePublisher records the published jobs, or fails with err if it is set.
+type fakePublisher struct {
+	published []string
+	err       error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, job *models.RagIndexJob) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, job.ID)
+	return nil
+}
+
+func newJobServer(store *memoryJobStore, publisher IndexJobPublisher) *Server {
+	return &Server{log: *logger.New("test", "", ""), indexJobDal: store, jobPublisher: publisher, indexWorkers: 2}
+}
+
+func newJob(id string, jobStatus models.RagIndexJobStatus, files ...models.RagIndexJobFile) *models.RagIndexJob {
+	return &models.RagIndexJob{ID: id, UserID: "u1", FolderID: "f1", Status: jobStatus, Files: files}
+}
+
+func jobFile(path string, fileStatus models.RagIndexJobFileStatus) models.RagIndexJobFile {
+	return models.RagIndexJobFile{Path: path, Status: fileStatus}
+}
+
+func TestSubmitIndexJob(t *testing.T) {
+	store := newMemoryJobStore()
+	publisher := &fakePublisher{}
+	s := newJobServer(store, publisher)
+
+	job, err := s.SubmitIndexJob(context.Background(), &ragv1.IndexRequest{UserId: "u1", FolderId: "f1", Paths: []string{"a.pdf", "b.pdf", "a.pdf"}})
+	if err != nil {
+		t.Fatalf("SubmitIndexJob() error = %v", err)
+	}
+	if job.GetStatus() != string(models.RagIndexJobStatusQueued) || job.GetTotalFiles() != 2 {
+		t.Errorf("SubmitIndexJob() = %v, want a queued job with 2 files", job)
+	}
+	if !slices.Equal(publisher.published, []string{job.GetJobId()}) {
+		t.Errorf("published %v, want [%s]", publisher.published, job.GetJobId())
+	}
+
+	for _, req := range []*ragv1.IndexRequest{
+		{FolderId: "f1", Paths: []string{"a.pdf"}},
+		{UserId: "u1", Paths: []string{"a.pdf"}},
+		{UserId: "u1", FolderId: "f1"},
+		{UserId: "u1", FolderId: "f1", Paths: []string{"a.pdf"}, Crawl: &ragv1.CrawlOptions{MaxDepth: -1}},
+	} {
+		if _, err := s.SubmitIndexJob(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("SubmitIndexJob(%v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestSubmitIndexJobPublishFailure(t *testing.T) {
+	store := newMemoryJobStore()
+	s := newJobServer(store, &fakePublisher{err: errors.New("broker unavailable")})
+	if _, err := s.SubmitIndexJob(context.Background(), &ragv1.IndexRequest{UserId: "u1", FolderId: "f1", Paths: []string{"a.pdf"}}); status.Code(err) != codes.Internal {
+		t.Fatalf("SubmitIndexJob() error = %v, want Internal", err)
+	}
+	// A job that could not be queued would never run, so it is marked as failed.
+	if len(store.jobs) != 1 {
+		t.Fatalf("stored %d jobs, want 1", len(store.jobs))
+	}
+	for _, job := range store.jobs {
+		if job.Status != models.RagIndexJobStatusFailed || job.Error == "" {
+			t.Errorf("job status = %s, error %q; want failed with an error", job.Status, job.Error)
+		}
+	}
+}
+
+func TestStartIndexJob(t *testing.T) {
+	startedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	resumed := newJob("resumed", models.RagIndexJobStatusRunning, jobFile("a.pdf", models.RagIndexJobFileStatusIndexed))
+	resumed.StartedAt = &startedAt
+	store := newMemoryJobStore(
+		newJob("queued", models.RagIndexJobStatusQueued, jobFile("a.pdf", models.RagIndexJobFileStatusPending)),
+		resumed,
+		newJob("completed", models.RagIndexJobStatusCompleted),
+		newJob("failed", models.RagIndexJobStatusFailed),
+	)
+	s := newJobServer(store, nil)
+
+	for _, jobID := range []string{"queued", "resumed"} {
+		job, err := s.startIndexJob(context.Background(), "u1", jobID)
+		if err != nil || job == nil {
+			t.Fatalf("startIndexJob(%s) = %v, %v", jobID, job, err)
+		}
+		if job.Status != models.RagIndexJobStatusRunning || store.jobs[jobID].Status != models.RagIndexJobStatusRunning || job.StartedAt == nil {
+			t.Errorf("startIndexJob(%s) = %s started at %v, stored %s; want running", jobID, job.Status, job.StartedAt, store.jobs[jobID].Status)
+		}
+	}
+	// A job that was interrupted keeps the time it was first started.
+	if !store.jobs["resumed"].StartedAt.Equal(startedAt) {
+		t.Errorf("resumed job started at %v, want %v", store.jobs["resumed"].StartedAt, startedAt)
+	}
+
+	// Finished jobs are not run again, so redelivered messages are harmless.
+	store.jobStatuses = nil
+	for _, jobID := range []string{"completed", "failed"} {
+		if job, err := s.startIndexJob(context.Background(), "u1", jobID); job != nil || err != nil {
+			t.Errorf("startIndexJob(%s) = %v, %v; want nothing to run", jobID, job, err)
+		}
+	}
+	if len(store.jobStatuses) != 0 {
+		t.Errorf("finished jobs were updated to %v", store.jobStatuses)
+	}
+
+	for _, tt := range []struct{ userID, jobID string }{{"u1", "missing"}, {"u2", "queued"}} {
+		if _, err := s.startIndexJob(context.Background(), tt.userID, tt.jobID); !errors.Is(err, ErrIndexJobNotFound) {
+			t.Errorf("startIndexJob(%s, %s) error = %v, want ErrIndexJobNotFound", tt.userID, tt.jobID, err)
+		}
+	}
+	store.err = errors.New("database unavailable")
+	if _, err := s.startIndexJob(context.Background(), "u1", "queued"); err == nil || errors.Is(err, ErrIndexJobNotFound) {
+		t.Errorf("startIndexJob() with a failing store error = %v, want a retryable error", err)
+	}
+}
+
+func TestFinishIndexJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		jobError string
+		files    []models.RagIndexJobFile
+		want     models.RagIndexJobStatus
+	}{
+		{"all files succeed", "", []models.RagIndexJobFile{jobFile("a", models.RagIndexJobFileStatusIndexed), jobFile("b", models.RagIndexJobFileStatusSkipped)}, models.RagIndexJobStatusCompleted},
+		{"some files fail", "", []models.RagIndexJobFile{jobFile("a", models.RagIndexJobFileStatusIndexed), jobFile("b", models.RagIndexJobFileStatusFailed)}, models.RagIndexJobStatusCompleted},
+		{"all files fail", "", []models.RagIndexJobFile{jobFile("a", models.RagIndexJobFileStatusFailed)}, models.RagIndexJobStatusFailed},
+		{"job error", "failed to create splitter", []models.RagIndexJobFile{jobFile("a", models.RagIndexJobFileStatusPending)}, models.RagIndexJobStatusFailed},
+		{"no files", "", nil, models.RagIndexJobStatusCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newJob("j1", models.RagIndexJobStatusRunning, tt.files...)
+			job.Error = tt.jobError
+			store := newMemoryJobStore(newJob("j1", models.RagIndexJobStatusRunning))
+			if err := newJobServer(store, nil).finishIndexJob(job); err != nil {
+				t.Fatalf("finishIndexJob() error = %v", err)
+			}
+			stored := store.jobs["j1"]
+			if stored.Status != tt.want || stored.FinishedAt == nil || stored.Error != tt.jobError {
+				t.Errorf("stored job = %s finished at %v with error %q; want %s", stored.Status, stored.FinishedAt, stored.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunIndexJobFiles(t *testing.T) {
+	index := func(_ context.Context, path string) (*pipeline2.IndexResult, error) {
+		switch path {
+		case "indexed.pdf":
+			return &pipeline2.IndexResult{ChunkIDs: []string{"c1", "c2", "c3"}}, nil
+		case "unchanged.pdf":
+			return &pipeline2.IndexResult{Skipped: true}, nil
+		default:
+			return nil, errors.New("unsupported file type")
+		}
+	}
+
+	tests := []struct {
+		name  string
+		files []models.RagIndexJobFile
+		want  models.RagIndexJobStatus
+	}{
+		{
+			"some files fail",
+			[]models.RagIndexJobFile{
+				jobFile("indexed.pdf", models.RagIndexJobFileStatusPending),
+				jobFile("unchanged.pdf", models.RagIndexJobFileStatusPending),
+				jobFile("broken.exe", models.RagIndexJobFileStatusIndexing),
+				jobFile("done.pdf", models.RagIndexJobFileStatusIndexed),
+			},
+			models.RagIndexJobStatusCompleted,
+		},
+		{
+			"all files fail",
+			[]models.RagIndexJobFile{jobFile("broken.exe", models.RagIndexJobFileStatusPending), jobFile("other.exe", models.RagIndexJobFileStatusPending)},
+			models.RagIndexJobStatusFailed,
+		},
+		{"no files", nil, models.RagIndexJobStatusCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newJob("j1", models.RagIndexJobStatusRunning, slices.Clone(tt.files)...)
+			store := newMemoryJobStore(newJob("j1", models.RagIndexJobStatusRunning))
+			if err := newJobServer(store, nil).runIndexJobFiles(context.Background(), job, index); err != nil {
+				t.Fatalf("runIndexJobFiles() error = %v", err)
+			}
+			if job.Status != tt.want || store.jobs["j1"].Status != tt.want {
+				t.Errorf("job status = %s, stored %s; want %s", job.Status, store.jobs["j1"].Status, tt.want)
+			}
+
+			// Every file that was not done is marked as indexing and then records its result.
+			for i, file := range job.Files {
+				var want []models.RagIndexJobFileStatus
+				if !tt.files[i].Status.Done() {
+					want = []models.RagIndexJobFileStatus{models.RagIndexJobFileStatusIndexing, file.Status}
+				}
+				if got := store.fileStatuses[file.Path]; !slices.Equal(got, want) {
+					t.Errorf("%s statuses = %v, want %v", file.Path, got, want)
+				}
+				switch file.Path {
+				case "indexed.pdf":
+					if file.Status != models.RagIndexJobFileStatusIndexed || file.ChunkCount != 3 {
+						t.Errorf("%s = %s with %d chunks, want indexed with 3", file.Path, file.Status, file.ChunkCount)
+					}
+				case "unchanged.pdf":
+					if file.Status != models.RagIndexJobFileStatusSkipped {
+						t.Errorf("%s = %s, want skipped", file.Path, file.Status)
+					}
+				case "broken.exe", "other.exe":
+					if file.Status != models.RagIndexJobFileStatusFailed || file.Error == "" {
+						t.Errorf("%s = %s, error %q; want failed with an error", file.Path, file.Status, file.Error)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunIndexJobFilesInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	index := func(ctx context.Context, path string) (*pipeline2.IndexResult, error) {
+		cancel()
+		return nil, ctx.Err()
+	}
+	job := newJob("j1", models.RagIndexJobStatusRunning, jobFile("a.pdf", models.RagIndexJobFileStatusPending))
+	store := newMemoryJobStore(newJob("j1", models.RagIndexJobStatusRunning))
+	if err := newJobServer(store, nil).runIndexJobFiles(ctx, job, index); !errors.Is(err, context.Canceled) {
+		t.Fatalf("runIndexJobFiles() error = %v, want context.Canceled", err)
+	}
+	// The file is left for the next run of the job, which is not finished.
+	if job.Files[0].Status != models.RagIndexJobFileStatusIndexing || job.Files[0].Error != "" {
+		t.Errorf("file = %s, error %q; want indexing", job.Files[0].Status, job.Files[0].Error)
+	}
+	if len(store.jobStatuses) != 0 || job.FinishedAt != nil {
+		t.Errorf("interrupted job was finished with statuses %v", store.jobStatuses)
+	}
+
+	// A result that cannot be recorded also interrupts the job.
+	job = newJob("j1", models.RagIndexJobStatusRunning, jobFile("a.pdf", models.RagIndexJobFileStatusPending))
+	store.err = errors.New("database unavailable")
+	indexed := func(context.Context, string) (*pipeline2.IndexResult, error) { return &pipeline2.IndexResult{}, nil }
+	if err := newJobServer(store, nil).runIndexJobFiles(context.Background(), job, indexed); !errors.Is(err, store.err) {
+		t.Errorf("runIndexJobFiles() error = %v, want %v", err, store.err)
+	}
+	if len(store.jobStatuses) != 0 {
+		t.Errorf("interrupted job was finished with statuses %v", store.jobStatuses)
+	}
+}
@@ -2,61 +2,121 @@ package service
 
 import (
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/dal"
-	"Jarvis_2.0/backend/go/internal/rag_service/rag/embeddings"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/dedup"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/interfaces"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/llms"
 	loaders2 "Jarvis_2.0/backend/go/internal/rag_service/rag/loaders"
 	pipeline2 "Jarvis_2.0/backend/go/internal/rag_service/rag/pipeline"
-	"Jarvis_2.0/backend/go/internal/rag_service/rag/rerankers"
+	"Jarvis_2.0/backend/go/internal/rag_service/rag/schema"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/splitters"
-	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/docstore"
 	"Jarvis_2.0/backend/go/internal/rag_service/rag/storages/vectorstore"
 	"context"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	ragv1 "Jarvis_2.0/api/proto/v1/rag"
-	"Jarvis_2.0/backend/go/internal/database/milvus"
-	"Jarvis_2.0/backend/go/internal/embedding"
 	"Jarvis_2.0/backend/go/internal/llm"
+	"Jarvis_2.0/backend/go/internal/models"
 	"Jarvis_2.0/backend/go/pkg/logger"
+	"github.com/minio/minio-go/v7"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// Bounds of QueryRequest.num_query_variants for multi-query expansion.
+const (
+	defaultQueryVariants = 3
+	maxQueryVariants     = 5
+)
+
+// Upper bounds of CrawlOptions, so that a single request cannot crawl a site without end.
+const (
+	maxCrawlDepth = 10
+	maxCrawlPages = 1000
+)
+
 // Server implements the RagServiceServer interface generated from the proto.
 type Server struct {
 	ragv1.UnimplementedRagServiceServer
 
-	log                   logger.Logger
-	folderDal             *dal.FolderDAL
-	milvusClient          *milvus.MilvusClient
-	geminiEmbeddingClient *embedding.GoogleModel
-	geminiLLMClient       *llm.Gemini
-	collectionName        string
-	cohereAPIKey          string // Assuming cohere API key is passed in config
+	log             logger.Logger
+	folderDal       *dal.FolderDAL
+	documentDal     *dal.DocumentDAL
+	indexJobDal     IndexJobStore
+	folderSyncDal   *dal.FolderSyncDAL
+	chunkDal        *dal.ChunkDAL
+	vectorStore     interfaces.VectorStore
+	minioClient     *minio.Client // Optional; nil disables MinIO sources
+	syncRoots       []string      // Directories that directory sync sources must lie in; empty disables them
+	docStore        interfaces.DocStore
+	keywordIndex    interfaces.KeywordIndex
+	dedup           *dedup.Deduplicator       // Optional; nil stores duplicate chunks
+	media           *pipeline2.MediaExtractor // Optional; nil does not index images and tables on their own
+	graph           *pipeline2.GraphRetriever // Optional; nil disables knowledge graph retrieval
+	embedder        interfaces.EmbeddingModel
+	geminiLLMClient *llm.Gemini
+	reranker        interfaces.Reranker // Optional; nil disables reranking
+	jobPublisher    IndexJobPublisher
+	loaders         *loaders2.Registry
+	indexWorkers    int // Number of files of an index job indexed concurrently
+	folderSyncLocks folderSyncLocks
 }
 
 // NewServer creates a new gRPC server for the RAG service.
+// The vectorStore, docStore and keywordIndex are shared by all Index and Query calls, so they must be persistent.
 func NewServer(
 	log logger.Logger,
 	folderDal *dal.FolderDAL,
-	milvusClient *milvus.MilvusClient,
-	geminiEmbeddingClient *embedding.GoogleModel,
+	documentDal *dal.DocumentDAL,
+	indexJobDal IndexJobStore,
+	folderSyncDal *dal.FolderSyncDAL,
+	chunkDal *dal.ChunkDAL,
+	vectorStore interfaces.VectorStore,
+	minioClient *minio.Client,
+	syncRoots []string,
+	docStore interfaces.DocStore,
+	keywordIndex interfaces.KeywordIndex,
+	deduplicator *dedup.Deduplicator,
+	media *pipeline2.MediaExtractor,
+	graph *pipeline2.GraphRetriever,
+	embedder interfaces.EmbeddingModel,
 	geminiLLMClient *llm.Gemini,
-	collectionName string,
-	cohereAPIKey string,
+	reranker interfaces.Reranker,
+	jobPublisher IndexJobPublisher,
+	indexWorkers int,
 ) *Server {
+	if indexWorkers < 1 {
+		indexWorkers = 1
+	}
+	registry := loaders2.NewDefaultRegistry()
+	if minioClient != nil {
+		registry.RegisterScheme(loaders2.MinIOScheme, loaders2.NewMinIOLoader(minioClient, registry))
+	}
 	return &Server{
-		log:                   log,
-		folderDal:             folderDal,
-		milvusClient:          milvusClient,
-		geminiEmbeddingClient: geminiEmbeddingClient,
-		geminiLLMClient:       geminiLLMClient,
-		collectionName:        collectionName,
-		cohereAPIKey:          cohereAPIKey,
+		log:             log,
+		folderDal:       folderDal,
+		documentDal:     documentDal,
+		indexJobDal:     indexJobDal,
+		folderSyncDal:   folderSyncDal,
+		chunkDal:        chunkDal,
+		vectorStore:     vectorStore,
+		minioClient:     minioClient,
+		syncRoots:       syncRoots,
+		docStore:        docStore,
+		keywordIndex:    keywordIndex,
+		dedup:           deduplicator,
+		media:           media,
+		graph:           graph,
+		embedder:        embedder,
+		geminiLLMClient: geminiLLMClient,
+		reranker:        reranker,
+		jobPublisher:    jobPublisher,
+		loaders:         registry,
+		indexWorkers:    indexWorkers,
 	}
 }
 
@@ -64,99 +124,572 @@ func NewServer(
 func (s *Server) Query(ctx context.Context, req *ragv1.QueryRequest) (*ragv1.QueryResponse, error) {
 	s.log.Info(fmt.Sprintf("Received Query request for user %s", req.GetUserId()))
 
-	docStore := docstore.NewInMemoryDocStore()
-	vectorStore, err := vectorstore.NewMilvusStore(s.milvusClient, s.collectionName, s.log)
+	retrieval, err := s.retrieve(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create vector store: %v", err)
+		return nil, err
 	}
-	embeddingAdapter := embeddings.NewGenaiAdapter(s.geminiEmbeddingClient)
-	llmAdapter := llms.NewGeminiAdapter(s.geminiLLMClient)
-	reranker := rerankers.NewCohereReranker(s.cohereAPIKey, "rerank-english-v2.0", 10)
-
-	retrievalPipeline := pipeline2.NewRetrievalPipeline(embeddingAdapter, vectorStore, docStore, reranker, s.log)
-	qaPipeline := pipeline2.NewQAPipeline(llmAdapter, s.log)
+	retrievedDocs := retrieval.Documents
 
-	retrievedDocs, err := retrievalPipeline.Run(ctx, req.GetQuery(), req.GetUserId(), req.GetFolderIds(), 10)
+	qaPipeline := pipeline2.NewQAPipeline(llms.NewGeminiAdapter(s.geminiLLMClient.Stateless()), s.log)
+	answer, err := qaPipeline.Run(ctx, retrieval.StandaloneQuery, retrievedDocs)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "retrieval pipeline failed: %v", err)
+		return nil, status.Errorf(codes.Internal, "QA pipeline failed: %v", err)
+	}
+
+	resp := &ragv1.QueryResponse{Answer: answer.Text, StandaloneQuery: retrieval.StandaloneQuery}
+	for _, doc := range retrievedDocs {
+		resp.Sources = append(resp.Sources, toProtoRetrievedDocument(doc))
+	}
+	for _, citation := range answer.Citations {
+		resp.Citations = append(resp.Citations, toProtoCitation(citation))
 	}
 
-	answer, err := qaPipeline.Run(ctx, req.GetQuery(), retrievedDocs)
+	return resp, nil
+}
+
+// QueryStream handles a user's query like Query, but streams the sources, the answer tokens and a final
+// completion message with citations and timings.
+func (s *Server) QueryStream(req *ragv1.QueryRequest, stream ragv1.RagService_QueryStreamServer) error {
+	ctx := stream.Context()
+	s.log.Info(fmt.Sprintf("Received QueryStream request for user %s", req.GetUserId()))
+	start := time.Now()
+
+	retrieval, err := s.retrieve(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "QA pipeline failed: %v", err)
+		return err
 	}
+	retrievedDocs := retrieval.Documents
+	retrievalDone := time.Now()
 
-	resp := &ragv1.QueryResponse{Answer: answer}
+	sources := &ragv1.QuerySources{}
 	for _, doc := range retrievedDocs {
-		metadata := make(map[string]string)
-		for k, v := range doc.Metadata {
-			metadata[k] = fmt.Sprintf("%v", v)
+		sources.Sources = append(sources.Sources, toProtoRetrievedDocument(doc))
+	}
+	if err := stream.Send(&ragv1.QueryStreamResponse{Event: &ragv1.QueryStreamResponse_Sources{Sources: sources}}); err != nil {
+		return err
+	}
+
+	var firstToken time.Time
+	qaPipeline := pipeline2.NewQAPipeline(llms.NewGeminiAdapter(s.geminiLLMClient.Stateless()), s.log)
+	answer, err := qaPipeline.RunStream(ctx, retrieval.StandaloneQuery, retrievedDocs, func(token string) error {
+		if firstToken.IsZero() {
+			firstToken = time.Now()
 		}
-		resp.Sources = append(resp.Sources, &ragv1.RetrievedDocument{
-			Id: doc.ID, Text: doc.Text, Metadata: metadata,
-		})
+		return stream.Send(&ragv1.QueryStreamResponse{Event: &ragv1.QueryStreamResponse_Token{Token: token}})
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "QA pipeline failed: %v", err)
 	}
+	done := time.Now()
 
-	return resp, nil
+	completed := &ragv1.QueryCompleted{
+		Answer:          answer.Text,
+		StandaloneQuery: retrieval.StandaloneQuery,
+		Timings: &ragv1.QueryTimings{
+			RetrievalMs:  retrievalDone.Sub(start).Milliseconds(),
+			GenerationMs: done.Sub(retrievalDone).Milliseconds(),
+			TotalMs:      done.Sub(start).Milliseconds(),
+		},
+	}
+	if !firstToken.IsZero() {
+		completed.Timings.FirstTokenMs = firstToken.Sub(start).Milliseconds()
+	}
+	for _, citation := range answer.Citations {
+		completed.Citations = append(completed.Citations, toProtoCitation(citation))
+	}
+	return stream.Send(&ragv1.QueryStreamResponse{Event: &ragv1.QueryStreamResponse_Completed{Completed: completed}})
+}
+
+// retrieve validates a query request and runs the retrieval pipeline for it. Errors are gRPC status errors.
+func (s *Server) retrieve(ctx context.Context, req *ragv1.QueryRequest) (*pipeline2.RetrievalResult, error) {
+	weights, err := hybridWeights(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	filters, err := metadataFilters(req.GetFilters())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	history, err := chatHistory(req.GetHistory())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	expansion, numVariants, err := queryExpansion(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if req.GetGraphRetrieval() && s.graph == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "knowledge graph retrieval is not enabled")
+	}
+
+	llmAdapter := llms.NewGeminiAdapter(s.geminiLLMClient.Stateless())
+
+	retrievalPipeline := pipeline2.NewRetrievalPipeline(s.embedder, s.vectorStore, s.docStore, s.keywordIndex, s.reranker, llmAdapter, s.graph, s.log)
+	result, err := retrievalPipeline.Run(ctx, pipeline2.RetrievalRequest{
+		Query:       req.GetQuery(),
+		History:     history,
+		UserID:      req.GetUserId(),
+		FolderIDs:   req.GetFolderIds(),
+		Filters:     filters,
+		TopK:        10,
+		Weights:     weights,
+		Expansion:   expansion,
+		NumVariants: numVariants,
+		Graph:       req.GetGraphRetrieval(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "retrieval pipeline failed: %v", err)
+	}
+	return result, nil
+}
+
+// binaryMetadataKeys are metadata entries holding raw media, which are not returned to clients.
+var binaryMetadataKeys = map[string]bool{
+	schema.MetadataKeyImage: true,
+	schema.MetadataKeyChart: true,
+	schema.MetadataKeyVideo: true,
+	schema.MetadataKeyAudio: true,
+}
+
+func toProtoRetrievedDocument(doc *schema.Document) *ragv1.RetrievedDocument {
+	metadata := make(map[string]string)
+	for k, v := range doc.Metadata {
+		if binaryMetadataKeys[k] {
+			continue
+		}
+		metadata[k] = fmt.Sprintf("%v", v)
+	}
+	retrieved := &ragv1.RetrievedDocument{
+		Id: doc.ID, Text: doc.Text, Score: float32(doc.Score), Metadata: metadata,
+	}
+	if doc.Metadata[schema.MetadataKeySourceType] == schema.SourceTypeKnowledgeGraph {
+		retrieved.SourceType = ragv1.SourceType_SOURCE_TYPE_KNOWLEDGE_GRAPH
+	}
+	return retrieved
+}
+
+func toProtoCitation(citation pipeline2.Citation) *ragv1.Citation {
+	doc := citation.Document
+	fileName, _ := doc.Metadata[schema.MetadataKeyFileName].(string)
+	pageLabel, _ := doc.Metadata[schema.MetadataKeyPageLabel].(string)
+	return &ragv1.Citation{
+		Marker:      int32(citation.Marker),
+		DocumentId:  doc.ID,
+		FileName:    fileName,
+		PageLabel:   pageLabel,
+		ChunkNumber: int32(pipeline2.MetadataInt(doc.Metadata, "chunk_number")),
+	}
 }
 
 // Index handles the document indexing process.
+// Paths whose content is unchanged since they were last indexed into the folder are skipped unless req.Reindex is set.
 func (s *Server) Index(req *ragv1.IndexRequest, stream ragv1.RagService_IndexServer) error {
-	ctx := stream.Context()
 	s.log.Info(fmt.Sprintf("Received Index request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
 
-	docStore := docstore.NewInMemoryDocStore()
-	vectorStore, err := vectorstore.NewMilvusStore(s.milvusClient, s.collectionName, s.log)
+	crawl, err := crawlOptions(req.GetCrawl())
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to create vector store: %v", err)
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := s.indexPaths(stream.Context(), req.GetUserId(), req.GetFolderId(), req.GetPaths(), req.GetReindex(), crawl, stream.Send); err != nil {
+		return err
 	}
-	splitter, err := splitters.NewTokenSplitter(1024, 256)
+
+	s.log.Info("Finished processing all paths for Index request.")
+	return nil
+}
+
+// Reindex re-indexes every document registered in a folder, skipping unchanged documents unless req.Force is set.
+func (s *Server) Reindex(req *ragv1.ReindexRequest, stream ragv1.RagService_ReindexServer) error {
+	ctx := stream.Context()
+	s.log.Info(fmt.Sprintf("Received Reindex request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	docs, err := s.documentDal.ListDocumentsByFolder(ctx, req.GetUserId(), req.GetFolderId())
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to create splitter: %v", err)
+		return status.Errorf(codes.Internal, "failed to list documents: %v", err)
+	}
+	paths := make([]string, len(docs))
+	for i, doc := range docs {
+		paths[i] = doc.Source
 	}
-	embeddingAdapter := embeddings.NewGenaiAdapter(s.geminiEmbeddingClient)
 
-	indexingPipeline := pipeline2.NewIndexingPipeline(splitter, embeddingAdapter, docStore, vectorStore, s.log)
+	// Crawled sites are crawled again with the options they were indexed with.
+	return s.indexPaths(ctx, req.GetUserId(), req.GetFolderId(), paths, req.GetForce(), nil, stream.Send)
+}
 
-	for _, path := range req.GetPaths() {
-		var loader interfaces.Loader
-		ext := strings.ToLower(filepath.Ext(path))
+// indexPaths indexes each path into the folder and streams progress via send. A path that fails to index is
+// reported to the client with an error message and does not stop the remaining paths.
+func (s *Server) indexPaths(ctx context.Context, userID, folderID string, paths []string, force bool, crawl *models.RagCrawlOptions, send func(*ragv1.IndexResponse) error) error {
+	indexingPipeline, err := s.newIndexingPipeline()
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
 
-		switch {
-		case strings.HasPrefix(path, "http"):
-			loader = loaders2.NewWebLoader()
-		case ext == ".pdf":
-			loader = loaders2.NewPdfLoader()
-		case ext == ".docx":
-			loader = loaders2.NewDocxLoader()
-		case ext == ".xlsx":
-			loader = loaders2.NewXlsxLoader()
-		case ext == ".md":
-			loader = loaders2.NewMarkdownLoader()
-		case ext == ".txt":
-			loader = loaders2.NewTxtLoader()
-		default:
-			s.log.Warn(fmt.Sprintf("Unsupported file type '%s' for path %s. Defaulting to text loader.", ext, path))
-			loader = loaders2.NewTxtLoader()
+	// Once sending fails the client is gone; stop sending but finish the current path.
+	var sendErr error
+	sendProgress := func(progress *ragv1.IndexResponse) error {
+		if sendErr == nil {
+			if sendErr = send(progress); sendErr != nil {
+				s.log.Error(fmt.Sprintf("Failed to send progress update to client: %v", sendErr))
+			}
 		}
+		return sendErr
+	}
 
-		progressChan := make(chan *ragv1.IndexResponse)
-		go func() {
-			if err := indexingPipeline.Run(ctx, loader, path, req.GetUserId(), req.GetFolderId(), progressChan); err != nil {
-				s.log.Error(fmt.Sprintf("Indexing pipeline failed for path %s: %v", path, err))
-			}
-		}()
+	for _, path := range paths {
+		if _, err := s.indexPath(ctx, indexingPipeline, userID, folderID, path, force, crawl, sendProgress); err != nil {
+			sendProgress(&ragv1.IndexResponse{Message: fmt.Sprintf("Failed to index: %s", path), Progress: 100, Path: path, Error: err.Error()})
+		}
+		if sendErr != nil {
+			return sendErr
+		}
+	}
+	return nil
+}
+
+// newIndexingPipeline creates the indexing pipeline.
+func (s *Server) newIndexingPipeline() (*pipeline2.IndexingPipeline, error) {
+	splitter, err := splitters.NewDefaultDocTypeSplitter(1024, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create splitter: %w", err)
+	}
+
+	return pipeline2.NewIndexingPipeline(splitter, s.embedder, s.docStore, s.vectorStore, s.keywordIndex, s.dedup, s.media, s.log), nil
+}
+
+// indexPath indexes a path into the folder, keeping the document registry in sync and passing progress to send.
+// If crawl is set, the path must be a URL and the website is crawled from it; if it is nil, a URL that was crawled
+// before is crawled again with its previous options.
+// When a document is re-indexed, the chunks of its previous version are purged once the new chunks are stored.
+// The error of send is ignored; callers track it themselves.
+func (s *Server) indexPath(ctx context.Context, indexingPipeline *pipeline2.IndexingPipeline, userID, folderID, path string, force bool, crawl *models.RagCrawlOptions, send func(*ragv1.IndexResponse) error) (*pipeline2.IndexResult, error) {
+	doc, err := s.documentDal.GetDocumentBySource(ctx, userID, folderID, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up document %s: %w", path, err)
+	}
+	if doc == nil {
+		doc = &models.RagDocument{UserID: userID, FolderID: folderID, Source: path}
+	}
+	if crawl != nil {
+		doc.Crawl = crawl
+	}
+
+	// Files of an unsupported type are rejected before they are registered.
+	loader, err := s.loaderFor(path, doc.Crawl)
+	if err != nil {
+		return nil, err
+	}
+	previousHash := ""
+	if !force && doc.Status == models.RagDocumentStatusIndexed {
+		previousHash = doc.ContentHash
+	}
+	previousChunkIDs := doc.ChunkIDs
+	previousStatus := doc.Status
+
+	doc.Status = models.RagDocumentStatusIndexing
+	if err := s.documentDal.SaveDocument(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to register document %s: %w", path, err)
+	}
+
+	// Run closes progressChan before it returns, so its results are passed back on done.
+	type runResult struct {
+		result *pipeline2.IndexResult
+		err    error
+	}
+	progressChan := make(chan *ragv1.IndexResponse)
+	done := make(chan runResult, 1)
+	go func() {
+		result, err := indexingPipeline.Run(ctx, loader, path, userID, folderID, previousHash, progressChan)
+		done <- runResult{result: result, err: err}
+	}()
+	for progress := range progressChan {
+		progress.Path = path
+		send(progress)
+	}
+	run := <-done
+	result, runErr := run.result, run.err
 
-		for progress := range progressChan {
-			if err := stream.Send(progress); err != nil {
-				s.log.Error(fmt.Sprintf("Failed to send progress update to client: %v", err))
-				return err
+	switch {
+	case runErr != nil:
+		s.log.Error(fmt.Sprintf("Indexing pipeline failed for path %s: %v", path, runErr))
+		// The chunks of the previous version, if any, are still in place.
+		doc.Status = models.RagDocumentStatusFailed
+		doc.Error = runErr.Error()
+	case result.Skipped:
+		doc.Status = previousStatus
+	default:
+		doc.Status = models.RagDocumentStatusIndexed
+		doc.Error = ""
+		doc.ContentHash = result.ContentHash
+		doc.ChunkIDs = result.ChunkIDs
+		doc.IndexedAt = time.Now()
+	}
+	if err := s.documentDal.SaveDocument(context.Background(), doc); err != nil {
+		s.log.Error(fmt.Sprintf("Failed to update document registry for path %s: %v", path, err))
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+	if !result.Skipped {
+		if err := s.chunkDal.SetDocumentRefs(context.Background(), doc.ID, result.DuplicateOf); err != nil {
+			s.log.Error(fmt.Sprintf("Failed to record duplicate chunks of %s: %v", path, err))
+		}
+	}
+	if !result.Skipped && len(previousChunkIDs) > 0 {
+		if err := s.purgeChunks(context.Background(), userID, previousChunkIDs); err != nil {
+			s.log.Error(fmt.Sprintf("Failed to purge previous chunks of %s: %v", path, err))
+		}
+	}
+	return result, nil
+}
+
+// loaderFor returns the loader for a path: a crawler if the path is a URL to crawl, or else the loader registered
+// for its type.
+func (s *Server) loaderFor(path string, crawl *models.RagCrawlOptions) (interfaces.Loader, error) {
+	if crawl == nil {
+		return s.loaders.LoaderFor(path)
+	}
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return nil, fmt.Errorf("only URLs can be crawled, got %s", path)
+	}
+	return loaders2.NewCrawlerLoader(loaders2.CrawlerConfig{
+		MaxDepth:     crawl.MaxDepth,
+		MaxPages:     crawl.MaxPages,
+		PathPrefix:   crawl.PathPrefix,
+		AllowedHosts: crawl.AllowedHosts,
+	}), nil
+}
+
+// crawlOptions converts the crawl options of an index request, returning nil if crawling is not requested.
+func crawlOptions(in *ragv1.CrawlOptions) (*models.RagCrawlOptions, error) {
+	if in == nil {
+		return nil, nil
+	}
+	if in.GetMaxDepth() < 0 || in.GetMaxDepth() > maxCrawlDepth {
+		return nil, fmt.Errorf("crawl max_depth must be between 0 and %d", maxCrawlDepth)
+	}
+	if in.GetMaxPages() < 0 || in.GetMaxPages() > maxCrawlPages {
+		return nil, fmt.Errorf("crawl max_pages must be between 0 and %d", maxCrawlPages)
+	}
+	if prefix := in.GetPathPrefix(); prefix != "" && !strings.HasPrefix(prefix, "/") {
+		return nil, fmt.Errorf("crawl path_prefix must start with /")
+	}
+	return &models.RagCrawlOptions{
+		MaxDepth:     int(in.GetMaxDepth()),
+		MaxPages:     int(in.GetMaxPages()),
+		PathPrefix:   in.GetPathPrefix(),
+		AllowedHosts: in.GetAllowedHosts(),
+	}, nil
+}
+
+// purgeChunks releases chunks of a document. Chunks that other documents reference as duplicates are handed over
+// to one of them; the rest are removed from the vector store, the keyword index and the doc store.
+func (s *Server) purgeChunks(ctx context.Context, userID string, ids []string) error {
+	ids, err := s.chunkDal.ReleaseChunks(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to release chunks: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.vectorStore.Delete(ctx, ids); err != nil {
+		return err
+	}
+	if s.keywordIndex != nil {
+		if err := s.keywordIndex.Delete(ctx, userID, ids); err != nil {
+			return err
+		}
+	}
+	return s.docStore.Delete(ctx, userID, ids)
+}
+
+// metadataFilters converts the filters of a query request, rejecting fields that cannot be filtered on.
+func metadataFilters(in []*ragv1.MetadataFilter) ([]schema.Filter, error) {
+	filters := make([]schema.Filter, 0, len(in))
+	for _, f := range in {
+		if !slices.Contains(vectorstore.FilterableFields, f.GetField()) {
+			return nil, fmt.Errorf("cannot filter on field %q, must be one of %v", f.GetField(), vectorstore.FilterableFields)
+		}
+
+		var filter schema.Filter
+		switch predicate := f.GetPredicate().(type) {
+		case *ragv1.MetadataFilter_Equals:
+			filter = schema.Eq(f.GetField(), predicate.Equals)
+		case *ragv1.MetadataFilter_In:
+			if len(predicate.In.GetValues()) == 0 {
+				return nil, fmt.Errorf("filter on %q has an empty in list", f.GetField())
+			}
+			filter = schema.In(f.GetField(), predicate.In.GetValues()...)
+		case *ragv1.MetadataFilter_Range:
+			r := predicate.Range
+			if !slices.Contains(vectorstore.NumericFields, f.GetField()) {
+				return nil, fmt.Errorf("cannot filter on a range of field %q, must be one of %v", f.GetField(), vectorstore.NumericFields)
 			}
+			if r.Gt == nil && r.Gte == nil && r.Lt == nil && r.Lte == nil {
+				return nil, fmt.Errorf("filter on %q has a range without bounds", f.GetField())
+			}
+			filter = schema.Filter{
+				Field: f.GetField(), Op: schema.FilterOpRange,
+				Gt: r.Gt, Gte: r.Gte, Lt: r.Lt, Lte: r.Lte,
+			}
+		default:
+			return nil, fmt.Errorf("filter on %q has no predicate", f.GetField())
 		}
+		filter.Numeric = slices.Contains(vectorstore.NumericFields, f.GetField())
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
 	}
+	return filters, nil
+}
 
-	s.log.Info("Finished processing all paths for Index request.")
-	return nil
+// hybridWeights returns the fusion weights requested by the query, defaulting to equal weights when neither is set.
+func hybridWeights(req *ragv1.QueryRequest) (pipeline2.HybridWeights, error) {
+	weights := pipeline2.HybridWeights{Vector: float64(req.GetVectorWeight()), Keyword: float64(req.GetKeywordWeight())}
+	if weights.Vector < 0 || weights.Keyword < 0 {
+		return weights, fmt.Errorf("vector_weight and keyword_weight must not be negative")
+	}
+	if weights.Vector == 0 && weights.Keyword == 0 {
+		return pipeline2.DefaultHybridWeights, nil
+	}
+	return weights, nil
+}
+
+// chatHistory converts the conversation history of a query request.
+func chatHistory(turns []*ragv1.ChatTurn) ([]pipeline2.ChatTurn, error) {
+	history := make([]pipeline2.ChatTurn, 0, len(turns))
+	for _, turn := range turns {
+		if turn.GetRole() != "user" && turn.GetRole() != "assistant" {
+			return nil, fmt.Errorf("history role must be \"user\" or \"assistant\", got %q", turn.GetRole())
+		}
+		history = append(history, pipeline2.ChatTurn{Role: turn.GetRole(), Content: turn.GetContent()})
+	}
+	return history, nil
+}
+
+// queryExpansion reads the query expansion settings of a query request, applying the default number of variants.
+func queryExpansion(req *ragv1.QueryRequest) (pipeline2.QueryExpansion, int, error) {
+	numVariants := int(req.GetNumQueryVariants())
+	if numVariants < 0 || numVariants > maxQueryVariants {
+		return 0, 0, fmt.Errorf("num_query_variants must be between 0 and %d", maxQueryVariants)
+	}
+	if numVariants == 0 {
+		numVariants = defaultQueryVariants
+	}
+
+	switch req.GetExpansion() {
+	case ragv1.QueryExpansion_QUERY_EXPANSION_NONE:
+		return pipeline2.ExpansionNone, numVariants, nil
+	case ragv1.QueryExpansion_QUERY_EXPANSION_MULTI_QUERY:
+		return pipeline2.ExpansionMultiQuery, numVariants, nil
+	case ragv1.QueryExpansion_QUERY_EXPANSION_HYDE:
+		return pipeline2.ExpansionHyDE, numVariants, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown query expansion %v", req.GetExpansion())
+	}
+}
+
+// ListDocuments lists the documents indexed into a folder.
+func (s *Server) ListDocuments(ctx context.Context, req *ragv1.ListDocumentsRequest) (*ragv1.ListDocumentsResponse, error) {
+	s.log.Info(fmt.Sprintf("Received ListDocuments request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	docs, err := s.documentDal.ListDocumentsByFolder(ctx, req.GetUserId(), req.GetFolderId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list documents: %v", err)
+	}
+
+	resp := &ragv1.ListDocumentsResponse{
+		Documents: make([]*ragv1.Document, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		resp.Documents = append(resp.Documents, toProtoDocument(doc))
+	}
+	return resp, nil
+}
+
+// DeleteDocument removes a document from the registry and purges its chunks.
+func (s *Server) DeleteDocument(ctx context.Context, req *ragv1.DeleteDocumentRequest) (*ragv1.DeleteDocumentResponse, error) {
+	s.log.Info(fmt.Sprintf("Received DeleteDocument request for user %s, document %s", req.GetUserId(), req.GetDocumentId()))
+
+	documentID, err := strconv.ParseUint(req.GetDocumentId(), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid document id: %v", err)
+	}
+	doc, err := s.documentDal.GetDocument(ctx, req.GetUserId(), uint(documentID))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get document: %v", err)
+	}
+	if doc == nil {
+		return nil, status.Errorf(codes.NotFound, "document not found")
+	}
+
+	if err := s.deleteDocument(ctx, doc); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete document: %v", err)
+	}
+
+	return &ragv1.DeleteDocumentResponse{DeletedChunks: int32(len(doc.ChunkIDs))}, nil
+}
+
+// deleteDocument drops the document's references to chunks of other documents, purges its chunks and then removes it
+// from the registry.
+func (s *Server) deleteDocument(ctx context.Context, doc *models.RagDocument) error {
+	if err := s.chunkDal.DeleteDocumentRefs(ctx, doc.ID); err != nil {
+		return err
+	}
+	if err := s.purgeChunks(ctx, doc.UserID, doc.ChunkIDs); err != nil {
+		return err
+	}
+	return s.documentDal.DeleteDocument(ctx, doc.UserID, doc.ID)
+}
+
+// DeleteFolder deletes a folder after purging all of its documents and their chunks.
+func (s *Server) DeleteFolder(ctx context.Context, req *ragv1.DeleteFolderRequest) (*ragv1.DeleteFolderResponse, error) {
+	s.log.Info(fmt.Sprintf("Received DeleteFolder request for user %s, folder %s", req.GetUserId(), req.GetFolderId()))
+
+	folderID, err := strconv.ParseUint(req.GetFolderId(), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid folder id: %v", err)
+	}
+
+	docs, err := s.documentDal.ListDocumentsByFolder(ctx, req.GetUserId(), req.GetFolderId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list documents: %v", err)
+	}
+	for _, doc := range docs {
+		if err := s.deleteDocument(ctx, doc); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to delete document %s: %v", doc.Source, err)
+		}
+	}
+
+	if _, err := s.folderSyncDal.DeleteSync(ctx, req.GetUserId(), req.GetFolderId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete folder sync: %v", err)
+	}
+	if err := s.folderDal.DeleteFolder(ctx, req.GetUserId(), uint(folderID)); err != nil {
+		if errors.Is(err, dal.ErrFolderNotFound) {
+			return nil, status.Errorf(codes.NotFound, "folder not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete folder: %v", err)
+	}
+
+	return &ragv1.DeleteFolderResponse{DeletedDocuments: int32(len(docs))}, nil
+}
+
+func toProtoDocument(doc *models.RagDocument) *ragv1.Document {
+	indexedAt := ""
+	if !doc.IndexedAt.IsZero() {
+		indexedAt = doc.IndexedAt.String()
+	}
+	return &ragv1.Document{
+		Id:          strconv.FormatUint(uint64(doc.ID), 10),
+		FolderId:    doc.FolderID,
+		Source:      doc.Source,
+		ContentHash: doc.ContentHash,
+		ChunkCount:  int32(len(doc.ChunkIDs)),
+		Status:      string(doc.Status),
+		Error:       doc.Error,
+		IndexedAt:   indexedAt,
+	}
 }
 
 // CreateFolder creates a new folder for a user.
@@ -177,7 +710,7 @@ func (s *Server) CreateFolder(ctx context.Context, req *ragv1.CreateFolderReques
 	}, nil
 }
 
-// ListFolders lists all folders for a user.
+// ListFolders lists all folders for a user, together with their sync sources.
 func (s *Server) ListFolders(ctx context.Context, req *ragv1.ListFoldersRequest) (*ragv1.ListFoldersResponse, error) {
 	s.log.Info(fmt.Sprintf("Received ListFolders request for user %s", req.GetUserId()))
 
@@ -185,16 +718,26 @@ func (s *Server) ListFolders(ctx context.Context, req *ragv1.ListFoldersRequest)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list folders: %v", err)
 	}
+	syncs, err := s.folderSyncDal.ListSyncsByUser(ctx, req.GetUserId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list folder syncs: %v", err)
+	}
+	syncsByFolder := make(map[string]*ragv1.FolderSync, len(syncs))
+	for _, folderSync := range syncs {
+		syncsByFolder[folderSync.FolderID] = toProtoFolderSync(folderSync)
+	}
 
 	resp := &ragv1.ListFoldersResponse{
 		Folders: make([]*ragv1.Folder, 0, len(folders)),
 	}
 
 	for _, folder := range folders {
+		id := strconv.FormatUint(uint64(folder.ID), 10)
 		resp.Folders = append(resp.Folders, &ragv1.Folder{
-			Id:        strconv.FormatUint(uint64(folder.ID), 10),
+			Id:        id,
 			Name:      folder.Name,
 			CreatedAt: folder.CreatedAt.String(),
+			Sync:      syncsByFolder[id],
 		})
 	}
 
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"Jarvis_2.0/backend/go/internal/database/kafka"
+	"Jarvis_2.0/backend/go/internal/models"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// GetDeadLettersHandler handles requests to list the messages parked in a dead-letter topic.
+// Query parameters: partition (default 0), offset (default: earliest), limit (default 10).
+func (a *API) GetDeadLettersHandler(c *gin.Context) {
+	partition, _ := strconv.Atoi(c.DefaultQuery("partition", "0"))
+	offset, _ := strconv.ParseInt(c.DefaultQuery("offset", "-1"), 10, 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	letters, err := a.deadLetters.List(c.Request.Context(), c.Param("topic"), partition, offset, limit)
+	if err != nil {
+		if errors.Is(err, kafka.ErrNotDeadLetterTopic) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to list dead-letter messages")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve dead-letter messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, letters)
+}
+
+// ReplayDeadLetterHandler handles replaying a dead-letter message to the consumer group that failed it. The response
+// names the topic the message was written to, which is the retry topic when the failing group is known.
+func (a *API) ReplayDeadLetterHandler(c *gin.Context) {
+	partition, err := strconv.Atoi(c.Param("partition"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partition"})
+		return
+	}
+	offset, err := strconv.ParseInt(c.Param("offset"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	target, err := a.deadLetters.Replay(c.Request.Context(), c.Param("topic"), partition, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, kafka.ErrNotDeadLetterTopic):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, kafka.ErrDeadLetterNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Dead-letter message not found"})
+		default:
+			a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to replay dead-letter message")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay dead-letter message"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"replayed_to": target})
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"Jarvis_2.0/backend/go/internal/database/kafka"
 	"Jarvis_2.0/backend/go/internal/models"
 	"Jarvis_2.0/backend/go/internal/task_ingestion_service/service"
 	"Jarvis_2.0/backend/go/pkg/logger"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"net/http"
@@ -12,16 +14,22 @@ import (
 
 // API provides handlers for the task ingestion service.
 type API struct {
-	service *service.TaskService
-	logger  *logger.Logger
-	upgrader websocket.Upgrader
+	service     *service.TaskService
+	scheduler   *service.Scheduler
+	webhooks    *service.WebhookDispatcher
+	deadLetters *kafka.DeadLetterAdmin
+	logger      *logger.Logger
+	upgrader    websocket.Upgrader
 }
 
 // NewAPI creates a new API handler.
-func NewAPI(service *service.TaskService, logger *logger.Logger) *API {
+func NewAPI(service *service.TaskService, scheduler *service.Scheduler, webhooks *service.WebhookDispatcher, deadLetters *kafka.DeadLetterAdmin, logger *logger.Logger) *API {
 	return &API{
-		service: service,
-		logger:  logger,
+		service:     service,
+		scheduler:   scheduler,
+		webhooks:    webhooks,
+		deadLetters: deadLetters,
+		logger:      logger,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true // In production, implement a proper origin check.
@@ -43,12 +51,17 @@ func (a *API) SubmitTaskHandler(c *gin.Context) {
 		return
 	}
 
-	task, err := a.service.SubmitTask(c.Request.Context(), userID.(string), payload)
+	// Retries carrying the same Idempotency-Key get the originally created task back.
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	task, created, err := a.service.SubmitTask(c.Request.Context(), userID.(string), idempotencyKey, payload)
 	if err != nil {
 		// The service layer already logged the detailed error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit task"})
 		return
 	}
+	if !created {
+		c.Header("Idempotent-Replayed", "true")
+	}
 
 	c.JSON(http.StatusAccepted, gin.H{"task_id": task.ID})
 }
@@ -71,6 +84,28 @@ func (a *API) GetTaskHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, task)
 }
 
+// CancelTaskHandler handles requests to cancel a task that has not finished yet.
+func (a *API) CancelTaskHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	taskID := c.Param("id")
+
+	task, cancelled, err := a.service.CancelTask(c.Request.Context(), taskID, userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel task"})
+		return
+	}
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found or not authorized"})
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Task has already finished"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
 // GetTasksHandler handles requests to get a list of tasks for the user.
 func (a *API) GetTasksHandler(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -87,6 +122,29 @@ func (a *API) GetTasksHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, tasks)
 }
 
+// SearchTasksHandler handles requests to search the user's tasks.
+// Query parameters: status (comma separated), from/to (RFC3339, on submission time), q (full-text search),
+// sort (submitted_at|completed_at), order (asc|desc), cursor and limit.
+func (a *API) SearchTasksHandler(c *gin.Context) {
+	filter, err := taskFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tasks, next, err := a.service.SearchTasks(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTaskFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve tasks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks, "next_cursor": next})
+}
+
 // WebSocketHandler handles WebSocket connection upgrades.
 func (a *API) WebSocketHandler(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,8 +17,25 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// AdminMiddleware only lets the given users through. It must run after AuthMiddleware.
+func AdminMiddleware(adminUserIDs []string) gin.HandlerFunc {
+	admins := make(map[string]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		if id, _ := userID.(string); !admins[id] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // RegisterRoutes registers all the routes for the task ingestion service.
-func RegisterRoutes(router *gin.Engine, api *API) {
+// Only the users in adminUserIDs may use the /admin routes.
+func RegisterRoutes(router *gin.Engine, api *API, adminUserIDs []string) {
 	// All routes will be under /api/v1
 	v1 := router.Group("/api/v1")
 	
@@ -26,7 +45,42 @@ func RegisterRoutes(router *gin.Engine, api *API) {
 	{
 		tasks.POST("", api.SubmitTaskHandler)
 		tasks.GET("", api.GetTasksHandler)
+		tasks.GET("/search", api.SearchTasksHandler)
+		tasks.GET("/stats", api.GetTaskStatsHandler)
+		tasks.GET("/export", api.ExportTasksHandler)
 		tasks.GET("/:id", api.GetTaskHandler)
+		tasks.POST("/:id/cancel", api.CancelTaskHandler)
+	}
+
+	// Recurring task schedules and their run history
+	schedules := v1.Group("/schedules")
+	schedules.Use(AuthMiddleware())
+	{
+		schedules.POST("", api.CreateScheduleHandler)
+		schedules.GET("", api.GetSchedulesHandler)
+		schedules.GET("/:id", api.GetScheduleHandler)
+		schedules.PUT("/:id", api.UpdateScheduleHandler)
+		schedules.DELETE("/:id", api.DeleteScheduleHandler)
+		schedules.GET("/:id/runs", api.GetScheduleRunsHandler)
+	}
+
+	// Outbound webhooks for task completion events and their delivery log
+	webhooks := v1.Group("/webhooks")
+	webhooks.Use(AuthMiddleware())
+	{
+		webhooks.POST("", api.CreateWebhookHandler)
+		webhooks.GET("", api.GetWebhooksHandler)
+		webhooks.DELETE("/:id", api.DeleteWebhookHandler)
+		webhooks.GET("/:id/deliveries", api.GetWebhookDeliveriesHandler)
+		webhooks.POST("/deliveries/:id/redeliver", api.RedeliverWebhookHandler)
+	}
+
+	// Dead-letter queue inspection and replay, across all users
+	admin := v1.Group("/admin")
+	admin.Use(AuthMiddleware(), AdminMiddleware(adminUserIDs))
+	{
+		admin.GET("/dlq/:topic", api.GetDeadLettersHandler)
+		admin.POST("/dlq/:topic/:partition/:offset/replay", api.ReplayDeadLetterHandler)
 	}
 
 	// WebSocket route
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// schedulePayload is the request body for creating or updating a schedule.
+type schedulePayload struct {
+	Name            string `json:"name"`
+	Content         string `json:"content"`
+	CronExpr        string `json:"cron_expr"`
+	IntervalSeconds int64  `json:"interval_seconds"`
+	Timezone        string `json:"timezone"`
+	Enabled         *bool  `json:"enabled"`
+}
+
+// toInput converts the payload to a service.ScheduleInput. Schedules are enabled unless stated otherwise.
+func (p schedulePayload) toInput() service.ScheduleInput {
+	enabled := true
+	if p.Enabled != nil {
+		enabled = *p.Enabled
+	}
+	return service.ScheduleInput{
+		Name:            p.Name,
+		Content:         p.Content,
+		CronExpr:        p.CronExpr,
+		IntervalSeconds: p.IntervalSeconds,
+		Timezone:        p.Timezone,
+		Enabled:         enabled,
+	}
+}
+
+// CreateScheduleHandler handles the creation of a new schedule.
+func (a *API) CreateScheduleHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var payload schedulePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Warn("Invalid request payload")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	schedule, err := a.scheduler.CreateSchedule(c.Request.Context(), userID.(string), payload.toInput())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// GetSchedulesHandler handles requests to list the user's schedules.
+func (a *API) GetSchedulesHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	schedules, err := a.scheduler.ListSchedules(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetScheduleHandler handles requests to get a single schedule by its ID.
+func (a *API) GetScheduleHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	schedule, err := a.scheduler.GetSchedule(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule"})
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found or not authorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateScheduleHandler handles replacing the definition of a schedule.
+func (a *API) UpdateScheduleHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var payload schedulePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Warn("Invalid request payload")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	schedule, err := a.scheduler.UpdateSchedule(c.Request.Context(), c.Param("id"), userID.(string), payload.toInput())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schedule"})
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found or not authorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteScheduleHandler handles the deletion of a schedule and its run history.
+func (a *API) DeleteScheduleHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	deleted, err := a.scheduler.DeleteSchedule(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schedule"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found or not authorized"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetScheduleRunsHandler handles requests to get the run history of a schedule.
+// Each run references the TaskRecord it produced, which can be fetched via GET /tasks/:id.
+func (a *API) GetScheduleRunsHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	runs, err := a.scheduler.ListRuns(c.Request.Context(), c.Param("id"), userID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedule runs"})
+		return
+	}
+	if runs == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found or not authorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
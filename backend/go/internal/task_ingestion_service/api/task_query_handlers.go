@@ -0,0 +1,164 @@
+package api
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/service"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/store"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// taskFilterFromQuery builds a task filter for the current user from the query string.
+func taskFilterFromQuery(c *gin.Context) (store.TaskFilter, error) {
+	userID, _ := c.Get("userID")
+	filter := store.TaskFilter{
+		UserID: userID.(string),
+		Text:   c.Query("q"),
+		SortBy: c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+
+	if status := c.Query("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			filter.Statuses = append(filter.Statuses, models.TaskStatus(strings.TrimSpace(s)))
+		}
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, fmt.Errorf("invalid from: %v", err)
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, fmt.Errorf("invalid to: %v", err)
+		}
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		filter.Asc = true
+	case "desc":
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return filter, fmt.Errorf("invalid limit: %v", err)
+		}
+	}
+	return filter, nil
+}
+
+// GetTaskStatsHandler handles requests for aggregate statistics over the user's tasks.
+// It accepts the same filters as SearchTasksHandler.
+func (a *API) GetTaskStatsHandler(c *gin.Context) {
+	filter, err := taskFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := a.service.TaskStats(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTaskFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to aggregate tasks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// taskCSVHeader lists the columns of a CSV export.
+var taskCSVHeader = []string{"id", "status", "submitted_at", "completed_at", "payload", "result", "error"}
+
+// ExportTasksHandler streams all of the user's tasks matching the filters as JSONL (default) or CSV (format=csv).
+func (a *API) ExportTasksHandler(c *gin.Context) {
+	filter, err := taskFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := c.DefaultQuery("format", "jsonl")
+	var write func(*models.TaskRecord) error
+	var flush func() error
+	switch format {
+	case "jsonl":
+		c.Header("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(c.Writer)
+		write = func(task *models.TaskRecord) error { return encoder.Encode(task) }
+		flush = func() error { return nil }
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		w := csv.NewWriter(c.Writer)
+		if err := w.Write(taskCSVHeader); err != nil {
+			return
+		}
+		write = func(task *models.TaskRecord) error { return w.Write(taskCSVRow(task)) }
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be jsonl or csv"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+
+	// Headers are already sent once the first record is written, so later errors can only be logged.
+	err = a.service.ExportTasks(c.Request.Context(), filter, write)
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTaskFilter) && !c.Writer.Written() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to export tasks")
+	}
+}
+
+// taskCSVRow converts a task into a CSV row; payload and result are JSON encoded.
+func taskCSVRow(task *models.TaskRecord) []string {
+	completedAt := ""
+	if !task.CompletedAt.IsZero() {
+		completedAt = task.CompletedAt.Format(time.RFC3339)
+	}
+	return []string{
+		task.ID,
+		string(task.Status),
+		task.SubmittedAt.Format(time.RFC3339),
+		completedAt,
+		jsonString(task.Payload),
+		jsonString(task.Result),
+		task.Error,
+	}
+}
+
+func jsonString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
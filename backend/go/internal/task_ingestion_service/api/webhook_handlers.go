@@ -0,0 +1,128 @@
+package api
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// CreateWebhookHandler handles the registration of a webhook endpoint.
+// If task_id is set the endpoint only receives events for that task, otherwise for all of the user's tasks.
+// The signing secret is only returned in this response.
+func (a *API) CreateWebhookHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var payload struct {
+		URL    string                `json:"url"`
+		Secret string                `json:"secret"`
+		TaskID string                `json:"task_id"`
+		Events []models.WebhookEvent `json:"events"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		a.logger.WithError(models.ErrorInfo{Message: err.Error()}).Warn("Invalid request payload")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	if payload.TaskID != "" {
+		task, err := a.service.GetTaskByID(c.Request.Context(), payload.TaskID, userID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve task"})
+			return
+		}
+		if task == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found or not authorized"})
+			return
+		}
+	}
+
+	endpoint, err := a.webhooks.CreateEndpoint(c.Request.Context(), userID.(string), service.WebhookInput{
+		URL:    payload.URL,
+		Secret: payload.Secret,
+		TaskID: payload.TaskID,
+		Events: payload.Events,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWebhook) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// GetWebhooksHandler handles requests to list the user's webhook endpoints. Secrets are not returned.
+func (a *API) GetWebhooksHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	endpoints, err := a.webhooks.ListEndpoints(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhooks"})
+		return
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// DeleteWebhookHandler handles the removal of a webhook endpoint.
+func (a *API) DeleteWebhookHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	deleted, err := a.webhooks.DeleteEndpoint(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found or not authorized"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveriesHandler handles requests to get the delivery log of a webhook endpoint.
+func (a *API) GetWebhookDeliveriesHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	deliveries, err := a.webhooks.ListDeliveries(c.Request.Context(), c.Param("id"), userID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhook deliveries"})
+		return
+	}
+	if deliveries == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found or not authorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhookHandler handles manual redelivery of a logged webhook delivery.
+func (a *API) RedeliverWebhookHandler(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	delivery, err := a.webhooks.Redeliver(c.Request.Context(), c.Param("id"), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeliver webhook"})
+		return
+	}
+	if delivery == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found or not authorized"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"delivery_id": delivery.ID})
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/store"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// OutboxRelay publishes the entries written to the transactional outbox to Kafka.
+// Entries are claimed with a lease, so several replicas can run a relay side by side.
+// Delivery is at least once: an entry published just before a crash is published again after its lease expires.
+type OutboxRelay struct {
+	store        store.OutboxStore
+	publisher    TaskPublisher
+	pollInterval time.Duration
+	lease        time.Duration
+	logger       *logger.Logger
+}
+
+// NewOutboxRelay creates a new OutboxRelay.
+func NewOutboxRelay(store store.OutboxStore, publisher TaskPublisher, pollInterval, lease time.Duration, logger *logger.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		store:        store,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		lease:        lease,
+		logger:       logger,
+	}
+}
+
+// Run polls the outbox until the context is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping outbox relay...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes claimable entries until none are left or publishing fails.
+func (r *OutboxRelay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		entry, err := r.store.ClaimPending(ctx, time.Now(), r.lease)
+		if err != nil {
+			r.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to claim outbox entry")
+			return
+		}
+		if entry == nil {
+			return
+		}
+
+		if err := r.publisher.Publish(ctx, entry.AggregateID, json.RawMessage(entry.Payload)); err != nil {
+			r.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"outboxID": entry.ID, "attempts": entry.Attempts}).Error("Failed to publish outbox entry")
+			if err := r.store.MarkFailed(ctx, entry.ID, err.Error()); err != nil {
+				r.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"outboxID": entry.ID}).Error("Failed to record outbox publish failure")
+			}
+			// Kafka is likely unavailable; wait for the next tick instead of spinning through the backlog.
+			return
+		}
+
+		if err := r.store.MarkPublished(ctx, entry.ID, time.Now()); err != nil {
+			r.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"outboxID": entry.ID}).Error("Failed to mark outbox entry as published")
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/store"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+// minScheduleInterval is the shortest interval a schedule may fire at.
+const minScheduleInterval = time.Minute
+
+// dueBatchSize bounds how many due schedules are fired per poll.
+const dueBatchSize = 100
+
+// Page sizes of the run history.
+const (
+	defaultRunsLimit = 10
+	maxRunsLimit     = 100
+)
+
+// ErrInvalidSchedule is returned when a schedule definition fails validation.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// LeaderElector defines the interface for the leader election used by the Scheduler.
+type LeaderElector interface {
+	// Campaign blocks until leadership is acquired and returns a channel closed on leadership loss.
+	Campaign(ctx context.Context) (<-chan struct{}, error)
+	Resign(ctx context.Context) error
+}
+
+// ScheduleInput holds the user-editable fields of a schedule.
+type ScheduleInput struct {
+	Name            string
+	Content         string
+	CronExpr        string
+	IntervalSeconds int64
+	Timezone        string
+	Enabled         bool
+}
+
+// Scheduler stores recurring task schedules and submits tasks through the TaskService when they are due.
+// Only the replica holding leadership fires schedules; CRUD operations are served by every replica.
+type Scheduler struct {
+	store        store.ScheduleStore
+	taskService  *TaskService
+	elector      LeaderElector
+	pollInterval time.Duration
+	logger       *logger.Logger
+}
+
+// NewScheduler creates a new Scheduler. If elector is nil, this instance always fires schedules.
+func NewScheduler(store store.ScheduleStore, taskService *TaskService, elector LeaderElector, pollInterval time.Duration, logger *logger.Logger) *Scheduler {
+	return &Scheduler{
+		store:        store,
+		taskService:  taskService,
+		elector:      elector,
+		pollInterval: pollInterval,
+		logger:       logger,
+	}
+}
+
+// CreateSchedule validates and stores a new schedule for a user.
+func (s *Scheduler) CreateSchedule(ctx context.Context, userID string, in ScheduleInput) (*models.Schedule, error) {
+	now := time.Now()
+	schedule := &models.Schedule{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		CreatedAt: now,
+	}
+	if err := s.apply(schedule, in, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Create(ctx, schedule); err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to create schedule in store")
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// GetSchedule retrieves a schedule owned by a user. It returns nil if not found or not owned.
+func (s *Scheduler) GetSchedule(ctx context.Context, scheduleID, userID string) (*models.Schedule, error) {
+	schedule, err := s.store.GetByID(ctx, scheduleID)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": scheduleID}).Error("Failed to get schedule from store")
+		return nil, err
+	}
+	if schedule != nil && schedule.UserID != userID {
+		s.logger.WithPayload(map[string]interface{}{"scheduleID": scheduleID, "requestingUserID": userID}).Warn("User attempted to access unauthorized schedule")
+		return nil, nil
+	}
+	return schedule, nil
+}
+
+// ListSchedules retrieves all schedules of a user.
+func (s *Scheduler) ListSchedules(ctx context.Context, userID string) ([]*models.Schedule, error) {
+	schedules, err := s.store.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"userID": userID}).Error("Failed to list schedules from store")
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// UpdateSchedule replaces the definition of a schedule owned by a user. It returns nil if not found or not owned.
+func (s *Scheduler) UpdateSchedule(ctx context.Context, scheduleID, userID string, in ScheduleInput) (*models.Schedule, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID, userID)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+	if err := s.apply(schedule, in, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Update(ctx, schedule); err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": scheduleID}).Error("Failed to update schedule in store")
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule removes a schedule owned by a user. It reports whether the schedule existed.
+func (s *Scheduler) DeleteSchedule(ctx context.Context, scheduleID, userID string) (bool, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID, userID)
+	if err != nil || schedule == nil {
+		return false, err
+	}
+
+	if err := s.store.Delete(ctx, scheduleID); err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": scheduleID}).Error("Failed to delete schedule from store")
+		return false, err
+	}
+	return true, nil
+}
+
+// ListRuns retrieves the run history of a schedule owned by a user. It returns nil if not found or not owned.
+func (s *Scheduler) ListRuns(ctx context.Context, scheduleID, userID string, page, limit int) ([]*models.ScheduleRun, error) {
+	schedule, err := s.GetSchedule(ctx, scheduleID, userID)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+
+	runs, err := s.store.GetRuns(ctx, scheduleID, page, limit)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": scheduleID}).Error("Failed to get schedule runs from store")
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Run campaigns for leadership and fires due schedules while leading, until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		// A nil channel never fires, so without an elector this instance leads forever.
+		var lost <-chan struct{}
+		if s.elector != nil {
+			var err error
+			lost, err = s.elector.Campaign(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Scheduler leader election failed")
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(s.pollInterval):
+				}
+				continue
+			}
+		}
+
+		s.logger.Info("Scheduler acquired leadership, firing due schedules")
+		if s.lead(ctx, lost) {
+			return
+		}
+		s.logger.Warn("Scheduler lost leadership")
+	}
+}
+
+// lead fires due schedules on every poll until leadership is lost or ctx is cancelled.
+// It reports whether the scheduler should stop.
+func (s *Scheduler) lead(ctx context.Context, lost <-chan struct{}) bool {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if s.elector != nil {
+				resignCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				_ = s.elector.Resign(resignCtx)
+				cancel()
+			}
+			s.logger.Info("Stopping scheduler...")
+			return true
+		case <-lost:
+			return false
+		case <-ticker.C:
+			s.fireDue(ctx)
+		}
+	}
+}
+
+// fireDue submits a task for every schedule that is due.
+func (s *Scheduler) fireDue(ctx context.Context) {
+	now := time.Now()
+	schedules, err := s.store.GetDue(ctx, now, dueBatchSize)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to get due schedules from store")
+		return
+	}
+
+	for _, schedule := range schedules {
+		s.fire(ctx, schedule, now)
+	}
+}
+
+// fire submits the task for one schedule, records the run and advances the next run time.
+// Runs missed while no replica was leading are collapsed into a single run.
+func (s *Scheduler) fire(ctx context.Context, schedule *models.Schedule, now time.Time) {
+	run := &models.ScheduleRun{
+		ID:         uuid.New().String(),
+		ScheduleID: schedule.ID,
+		UserID:     schedule.UserID,
+		FiredAt:    now,
+	}
+
+	// The idempotency key ties the task to this occurrence, so a leader change mid-fire cannot submit it twice.
+	idempotencyKey := fmt.Sprintf("schedule:%s:%d", schedule.ID, schedule.NextRunAt.Unix())
+	task, _, err := s.taskService.SubmitTask(ctx, schedule.UserID, idempotencyKey, map[string]interface{}{"content": schedule.Content})
+	if err != nil {
+		run.Error = err.Error()
+	} else {
+		run.TaskID = task.ID
+	}
+
+	if err := s.store.AddRun(ctx, run); err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": schedule.ID}).Error("Failed to record schedule run")
+	}
+
+	next, err := nextRun(schedule, now)
+	disable := err != nil
+	if disable {
+		// The definition was validated on write, so this only happens for corrupted records.
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": schedule.ID}).Error("Disabling schedule with invalid definition")
+	}
+
+	advanced, err := s.store.Advance(ctx, schedule.ID, schedule.NextRunAt, next, now, disable)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"scheduleID": schedule.ID}).Error("Failed to advance schedule")
+		return
+	}
+	if !advanced {
+		s.logger.WithPayload(map[string]interface{}{"scheduleID": schedule.ID}).Info("Schedule was changed while firing, keeping the new definition")
+	}
+}
+
+// apply validates the input and copies it onto the schedule, recomputing the next run time.
+func (s *Scheduler) apply(schedule *models.Schedule, in ScheduleInput, now time.Time) error {
+	if in.Content == "" {
+		return fmt.Errorf("%w: content is required", ErrInvalidSchedule)
+	}
+	if (in.CronExpr == "") == (in.IntervalSeconds == 0) {
+		return fmt.Errorf("%w: exactly one of cron_expr or interval_seconds is required", ErrInvalidSchedule)
+	}
+	if in.IntervalSeconds != 0 && time.Duration(in.IntervalSeconds)*time.Second < minScheduleInterval {
+		return fmt.Errorf("%w: interval_seconds must be at least %d", ErrInvalidSchedule, int64(minScheduleInterval/time.Second))
+	}
+	// The timezone is validated for interval schedules too, although only cron schedules use it.
+	if _, err := scheduleLocation(in.Timezone); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if in.CronExpr != "" {
+		spec, err := cron.ParseStandard(in.CronExpr)
+		if err != nil {
+			return fmt.Errorf("%w: invalid cron expression: %v", ErrInvalidSchedule, err)
+		}
+		// Standard cron fields fire at most once a minute, but descriptors such as "@every 1s" can fire faster.
+		if first := spec.Next(now); spec.Next(first).Sub(first) < minScheduleInterval {
+			return fmt.Errorf("%w: cron_expr must not fire more often than every %s", ErrInvalidSchedule, minScheduleInterval)
+		}
+	}
+
+	schedule.Name = in.Name
+	schedule.Content = in.Content
+	schedule.CronExpr = in.CronExpr
+	schedule.IntervalSeconds = in.IntervalSeconds
+	schedule.Timezone = in.Timezone
+	schedule.Enabled = in.Enabled
+	schedule.UpdatedAt = now
+
+	next, err := nextRun(schedule, now)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	schedule.NextRunAt = next
+	return nil
+}
+
+// nextRun computes the first fire time of a schedule strictly after from.
+func nextRun(schedule *models.Schedule, from time.Time) (time.Time, error) {
+	if schedule.CronExpr == "" {
+		return from.Add(time.Duration(schedule.IntervalSeconds) * time.Second), nil
+	}
+
+	loc, err := scheduleLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	spec, err := cron.ParseStandard(schedule.CronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression: %v", err)
+	}
+	return spec.Next(from.In(loc)).UTC(), nil
+}
+
+// scheduleLocation returns the location cron expressions of a schedule are evaluated in; an empty timezone is UTC.
+func scheduleLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return loc, nil
+}
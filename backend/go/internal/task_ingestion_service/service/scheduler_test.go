@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"Jarvis_2.0/backend/go/internal/models"
+)
+
+func TestSchedulerApply(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 30, 0, time.UTC)
+	tests := []struct {
+		name     string
+		in       ScheduleInput
+		wantNext time.Time
+		wantErr  bool
+	}{
+		{"interval", ScheduleInput{Content: "c", IntervalSeconds: 3600}, now.Add(time.Hour), false},
+		{"minimum interval", ScheduleInput{Content: "c", IntervalSeconds: 60}, now.Add(time.Minute), false},
+		{"interval too short", ScheduleInput{Content: "c", IntervalSeconds: 59}, time.Time{}, true},
+		{"cron", ScheduleInput{Content: "c", CronExpr: "0 9 * * *"}, time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), false},
+		{"cron every minute", ScheduleInput{Content: "c", CronExpr: "* * * * *"}, time.Date(2024, 3, 10, 12, 1, 0, 0, time.UTC), false},
+		{"cron every second", ScheduleInput{Content: "c", CronExpr: "@every 1s"}, time.Time{}, true},
+		{"cron every 30 seconds", ScheduleInput{Content: "c", CronExpr: "@every 30s"}, time.Time{}, true},
+		{"cron every 5 minutes", ScheduleInput{Content: "c", CronExpr: "@every 5m"}, now.Add(5 * time.Minute), false},
+		{"cron in timezone", ScheduleInput{Content: "c", CronExpr: "0 9 * * *", Timezone: "Asia/Shanghai"}, time.Date(2024, 3, 11, 1, 0, 0, 0, time.UTC), false},
+		{"invalid cron", ScheduleInput{Content: "c", CronExpr: "every day"}, time.Time{}, true},
+		{"unknown timezone", ScheduleInput{Content: "c", CronExpr: "0 9 * * *", Timezone: "Mars/Olympus"}, time.Time{}, true},
+		{"unknown timezone on interval", ScheduleInput{Content: "c", IntervalSeconds: 3600, Timezone: "Mars/Olympus"}, time.Time{}, true},
+		{"cron and interval", ScheduleInput{Content: "c", CronExpr: "0 9 * * *", IntervalSeconds: 3600}, time.Time{}, true},
+		{"neither cron nor interval", ScheduleInput{Content: "c"}, time.Time{}, true},
+		{"no content", ScheduleInput{IntervalSeconds: 3600}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &models.Schedule{}
+			err := (&Scheduler{}).apply(schedule, tt.in, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSchedule) {
+					t.Errorf("apply() error = %v, want ErrInvalidSchedule", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+			if !schedule.NextRunAt.Equal(tt.wantNext) {
+				t.Errorf("NextRunAt = %v, want %v", schedule.NextRunAt, tt.wantNext)
+			}
+			if schedule.CronExpr != tt.in.CronExpr || schedule.IntervalSeconds != tt.in.IntervalSeconds || !schedule.UpdatedAt.Equal(now) {
+				t.Errorf("apply() stored %+v", schedule)
+			}
+		})
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	from := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule models.Schedule
+		want     time.Time
+		wantErr  bool
+	}{
+		{"interval", models.Schedule{IntervalSeconds: 90}, from.Add(90 * time.Second), false},
+		{"cron is strictly after from", models.Schedule{CronExpr: "0 9 * * *"}, from.AddDate(0, 0, 1), false},
+		{"cron in timezone", models.Schedule{CronExpr: "30 17 * * *", Timezone: "Asia/Shanghai"}, time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC), false},
+		// New York switches to daylight saving time on 2024-03-10, so 9:00 local is 13:00 UTC instead of 14:00.
+		{"cron across daylight saving", models.Schedule{CronExpr: "0 9 * * *", Timezone: "America/New_York"}, time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC), false},
+		{"unknown timezone", models.Schedule{CronExpr: "0 9 * * *", Timezone: "Mars/Olympus"}, time.Time{}, true},
+		{"invalid cron", models.Schedule{CronExpr: "61 * * * *"}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextRun(&tt.schedule, from)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nextRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("nextRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
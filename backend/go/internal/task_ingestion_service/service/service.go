@@ -1,11 +1,14 @@
 package service
 
 import (
+	jkafka "Jarvis_2.0/backend/go/internal/database/kafka"
 	"Jarvis_2.0/backend/go/internal/models"
 	"Jarvis_2.0/backend/go/internal/task_ingestion_service/store"
 	"Jarvis_2.0/backend/go/pkg/logger"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/segmentio/kafka-go"
@@ -16,7 +19,7 @@ import (
 type TaskService struct {
 	store       store.TaskStore
 	connManager *ConnectionManager
-	publisher   TaskPublisher
+	notifier    TaskNotifier
 	logger      *logger.Logger
 }
 
@@ -27,11 +30,12 @@ type TaskPublisher interface {
 }
 
 // NewTaskService creates a new TaskService.
-func NewTaskService(store store.TaskStore, connManager *ConnectionManager, publisher TaskPublisher, logger *logger.Logger) *TaskService {
+// The notifier is optional and can be nil.
+func NewTaskService(store store.TaskStore, connManager *ConnectionManager, notifier TaskNotifier, logger *logger.Logger) *TaskService {
 	return &TaskService{
 		store:       store,
 		connManager: connManager,
-		publisher:   publisher,
+		notifier:    notifier,
 		logger:      logger,
 	}
 }
@@ -48,31 +52,56 @@ func (s *TaskService) RemoveConnection(userID string) {
 	s.logger.Info("WebSocket connection removed for user: " + userID)
 }
 
-// SubmitTask creates a new task, stores it, and publishes it to Kafka.
-func (s *TaskService) SubmitTask(ctx context.Context, userID string, payload interface{}) (*models.TaskRecord, error) {
+// SubmitTask creates a new task and stores it together with an outbox entry; the OutboxRelay publishes it to Kafka.
+// If idempotencyKey is set and the user already submitted a task with that key, the existing task is returned
+// and the returned bool is false.
+func (s *TaskService) SubmitTask(ctx context.Context, userID, idempotencyKey string, payload interface{}) (*models.TaskRecord, bool, error) {
+	if idempotencyKey != "" {
+		existing, err := s.store.GetByIdempotencyKey(ctx, userID, idempotencyKey)
+		if err != nil {
+			s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to look up task by idempotency key")
+			return nil, false, err
+		}
+		if existing != nil {
+			return existing, false, nil
+		}
+	}
+
 	task := &models.TaskRecord{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		Status:      models.TaskStatusPending,
-		Payload:     payload,
-		SubmittedAt: time.Now(),
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Status:         models.TaskStatusPending,
+		Payload:        payload,
+		IdempotencyKey: idempotencyKey,
+		SubmittedAt:    time.Now(),
 	}
 
-	if err := s.store.Create(ctx, task); err != nil {
-		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to create task in store")
-		return nil, err
+	msg, err := json.Marshal(task)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to marshal task for outbox")
+		return nil, false, err
+	}
+	entry := &models.OutboxEntry{
+		ID:          uuid.New().String(),
+		AggregateID: task.ID,
+		Payload:     string(msg),
+		Status:      models.OutboxStatusPending,
+		CreatedAt:   task.SubmittedAt,
 	}
 
-	if err := s.publisher.Publish(ctx, task.ID, task); err != nil {
-		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to publish task to Kafka")
-		task.Status = models.TaskStatusFailed
-		task.Error = "Failed to publish to Kafka"
-		task.CompletedAt = time.Now()
-		_ = s.store.Update(ctx, task)
-		return nil, err
+	if err := s.store.CreateWithOutbox(ctx, task, entry); err != nil {
+		if errors.Is(err, store.ErrDuplicateIdempotencyKey) {
+			// A concurrent request with the same key won the race.
+			existing, getErr := s.store.GetByIdempotencyKey(ctx, userID, idempotencyKey)
+			if getErr == nil && existing != nil {
+				return existing, false, nil
+			}
+		}
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to create task in store")
+		return nil, false, err
 	}
 
-	return task, nil
+	return task, true, nil
 }
 
 // HandleResult processes a task result received from Kafka.
@@ -80,7 +109,7 @@ func (s *TaskService) HandleResult(msg kafka.Message) error {
 	var resultTask models.TaskRecord
 	if err := json.Unmarshal(msg.Value, &resultTask); err != nil {
 		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to unmarshal task result from Kafka")
-		return err
+		return fmt.Errorf("%w: %v", jkafka.ErrNonRetryable, err)
 	}
 
 	task, err := s.store.GetByID(context.Background(), resultTask.ID)
@@ -93,20 +122,73 @@ func (s *TaskService) HandleResult(msg kafka.Message) error {
 		return nil
 	}
 
+	// Results are delivered at least once, so duplicates and late results for a task
+	// that is already terminal (e.g. cancelled) must not overwrite it.
+	if task.Status.IsTerminal() {
+		s.logger.WithPayload(map[string]interface{}{"taskID": task.ID, "status": task.Status, "resultStatus": resultTask.Status}).Info("Ignoring result for task that is already terminal")
+		return nil
+	}
+
 	task.Status = resultTask.Status
 	task.Result = resultTask.Result
 	task.Error = resultTask.Error
-	task.CompletedAt = time.Now()
+	if task.Status.IsTerminal() {
+		task.CompletedAt = time.Now()
+	}
 
-	if err := s.store.Update(context.Background(), task); err != nil {
+	updated, err := s.store.UpdateIfNotTerminal(context.Background(), task)
+	if err != nil {
 		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"taskID": task.ID}).Error("Failed to update task in store")
 		return err
 	}
+	if !updated {
+		s.logger.WithPayload(map[string]interface{}{"taskID": task.ID, "resultStatus": resultTask.Status}).Info("Ignoring result for task that is already terminal")
+		return nil
+	}
 
 	s.connManager.SendMessage(task.UserID, msg.Value)
+	s.notify(task)
 	return nil
 }
 
+// CancelTask marks a task that has not finished yet as cancelled.
+// It returns nil if the task is not found or not owned, and reports whether the task was cancelled.
+func (s *TaskService) CancelTask(ctx context.Context, taskID, userID string) (*models.TaskRecord, bool, error) {
+	task, err := s.GetTaskByID(ctx, taskID, userID)
+	if err != nil || task == nil {
+		return nil, false, err
+	}
+	if task.Status.IsTerminal() {
+		return task, false, nil
+	}
+
+	task.Status = models.TaskStatusCancelled
+	task.CompletedAt = time.Now()
+	updated, err := s.store.UpdateIfNotTerminal(ctx, task)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"taskID": task.ID}).Error("Failed to update task in store")
+		return nil, false, err
+	}
+	if !updated {
+		// A result arrived between the read and the update.
+		task, err = s.GetTaskByID(ctx, taskID, userID)
+		return task, false, err
+	}
+
+	if msg, err := json.Marshal(task); err == nil {
+		s.connManager.SendMessage(task.UserID, msg)
+	}
+	s.notify(task)
+	return task, true, nil
+}
+
+// notify hands a task that reached a terminal status to the notifier, if one is configured.
+func (s *TaskService) notify(task *models.TaskRecord) {
+	if s.notifier != nil && task.Status.IsTerminal() {
+		s.notifier.Notify(context.Background(), task)
+	}
+}
+
 // GetTaskByID retrieves a single task by its ID for a specific user.
 func (s *TaskService) GetTaskByID(ctx context.Context, taskID, userID string) (*models.TaskRecord, error) {
 	task, err := s.store.GetByID(ctx, taskID)
@@ -130,3 +212,79 @@ func (s *TaskService) GetUserTasks(ctx context.Context, userID string, page, lim
 	}
 	return tasks, nil
 }
+
+// ErrInvalidTaskFilter is returned when a task search request is malformed.
+var ErrInvalidTaskFilter = errors.New("invalid task filter")
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchTasks returns one page of the user's tasks matching the filter and the cursor of the next page.
+func (s *TaskService) SearchTasks(ctx context.Context, filter store.TaskFilter) ([]*models.TaskRecord, string, error) {
+	if err := normalizeFilter(&filter); err != nil {
+		return nil, "", err
+	}
+	tasks, next, err := s.store.Search(ctx, filter)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidCursor) {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidTaskFilter, err)
+		}
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"userID": filter.UserID}).Error("Failed to search user tasks in store")
+		return nil, "", err
+	}
+	return tasks, next, nil
+}
+
+// TaskStats aggregates the user's tasks matching the filter.
+func (s *TaskService) TaskStats(ctx context.Context, filter store.TaskFilter) (*models.TaskStats, error) {
+	if err := normalizeFilter(&filter); err != nil {
+		return nil, err
+	}
+	stats, err := s.store.Stats(ctx, filter)
+	if err != nil {
+		s.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"userID": filter.UserID}).Error("Failed to aggregate user tasks")
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ExportTasks streams all of the user's tasks matching the filter to fn.
+func (s *TaskService) ExportTasks(ctx context.Context, filter store.TaskFilter, fn func(*models.TaskRecord) error) error {
+	if err := normalizeFilter(&filter); err != nil {
+		return err
+	}
+	return s.store.Each(ctx, filter, fn)
+}
+
+// normalizeFilter validates a task filter and applies defaults.
+func normalizeFilter(filter *store.TaskFilter) error {
+	if filter.UserID == "" {
+		return fmt.Errorf("%w: user is required", ErrInvalidTaskFilter)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidTaskFilter)
+	}
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = store.SortBySubmittedAt
+	case store.SortBySubmittedAt, store.SortByCompletedAt:
+	default:
+		return fmt.Errorf("%w: unsupported sort field %q", ErrInvalidTaskFilter, filter.SortBy)
+	}
+	for _, status := range filter.Statuses {
+		switch status {
+		case models.TaskStatusPending, models.TaskStatusRunning, models.TaskStatusSuccess, models.TaskStatusFailed, models.TaskStatusCancelled:
+		default:
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidTaskFilter, status)
+		}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	if filter.Limit > maxSearchLimit {
+		filter.Limit = maxSearchLimit
+	}
+	return nil
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/models"
+	"Jarvis_2.0/backend/go/internal/task_ingestion_service/store"
+	pkghttp "Jarvis_2.0/backend/go/pkg/http"
+	"Jarvis_2.0/backend/go/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader carries "t=<unix>,v1=<hex hmac-sha256 of '<unix>.<body>'>".
+	WebhookSignatureHeader = "X-Jarvis-Signature"
+	// WebhookEventHeader carries the event name, e.g. "task.succeeded".
+	WebhookEventHeader = "X-Jarvis-Event"
+	// WebhookDeliveryHeader carries the delivery ID, stable across retries and redeliveries.
+	WebhookDeliveryHeader = "X-Jarvis-Delivery"
+)
+
+// ErrInvalidWebhook is returned when a webhook endpoint definition fails validation.
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// TaskNotifier is notified when a task reaches a terminal status.
+type TaskNotifier interface {
+	Notify(ctx context.Context, task *models.TaskRecord)
+}
+
+// WebhookInput holds the user-provided fields of a webhook endpoint.
+type WebhookInput struct {
+	URL    string
+	Secret string
+	TaskID string
+	Events []models.WebhookEvent
+}
+
+// webhookPayload is the JSON body POSTed to webhook endpoints.
+type webhookPayload struct {
+	DeliveryID string              `json:"delivery_id"`
+	Event      models.WebhookEvent `json:"event"`
+	Task       webhookTask         `json:"task"`
+}
+
+type webhookTask struct {
+	ID          string            `json:"id"`
+	UserID      string            `json:"user_id"`
+	Status      models.TaskStatus `json:"status"`
+	Result      interface{}       `json:"result,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	SubmittedAt time.Time         `json:"submitted_at"`
+	CompletedAt time.Time         `json:"completed_at"`
+}
+
+// WebhookDispatcher manages webhook endpoints and delivers signed task events to them.
+// Each endpoint gets its own circuit-breaking client so one failing receiver cannot block the others.
+// Clients only connect to public addresses, so endpoints cannot be used to reach services inside the cluster.
+type WebhookDispatcher struct {
+	store          store.WebhookStore
+	breakerCfg     config.CircuitBreakerConfig
+	maxAttempts    int
+	initialBackoff time.Duration
+	logger         *logger.Logger
+
+	// Deliveries run in the background until they finish or Shutdown aborts them through ctx.
+	ctx        context.Context
+	cancel     context.CancelFunc
+	deliveries sync.WaitGroup
+
+	mu      sync.Mutex
+	clients map[string]*pkghttp.Client
+	closed  bool
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher.
+func NewWebhookDispatcher(store store.WebhookStore, breakerCfg config.CircuitBreakerConfig, maxAttempts int, initialBackoff time.Duration, logger *logger.Logger) *WebhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookDispatcher{
+		store:          store,
+		breakerCfg:     breakerCfg,
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		logger:         logger,
+		ctx:            ctx,
+		cancel:         cancel,
+		clients:        make(map[string]*pkghttp.Client),
+	}
+}
+
+// Shutdown stops accepting deliveries and waits for the in-flight ones to finish. If ctx is done first, they are
+// aborted and stay pending, so they can be redelivered later.
+func (d *WebhookDispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// CreateEndpoint validates and stores a new webhook endpoint. A secret is generated if none is given.
+func (d *WebhookDispatcher) CreateEndpoint(ctx context.Context, userID string, in WebhookInput) (*models.WebhookEndpoint, error) {
+	u, err := url.Parse(in.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	// Host names are checked when connecting, since they may resolve differently by then.
+	if ip := net.ParseIP(u.Hostname()); (ip != nil && !pkghttp.IsPublicIP(ip)) || strings.EqualFold(u.Hostname(), "localhost") {
+		return nil, fmt.Errorf("%w: url must point to a public address", ErrInvalidWebhook)
+	}
+	for _, event := range in.Events {
+		if event != models.WebhookEventTaskSucceeded && event != models.WebhookEventTaskFailed && event != models.WebhookEventTaskCancelled {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+
+	secret := in.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		URL:       in.URL,
+		Secret:    secret,
+		TaskID:    in.TaskID,
+		Events:    in.Events,
+		Enabled:   true,
+		CreatedAt: time.Now(),
+	}
+	if err := d.store.CreateEndpoint(ctx, endpoint); err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to create webhook endpoint in store")
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// GetEndpoint retrieves an endpoint owned by a user. It returns nil if not found or not owned.
+func (d *WebhookDispatcher) GetEndpoint(ctx context.Context, endpointID, userID string) (*models.WebhookEndpoint, error) {
+	endpoint, err := d.store.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"endpointID": endpointID}).Error("Failed to get webhook endpoint from store")
+		return nil, err
+	}
+	if endpoint != nil && endpoint.UserID != userID {
+		d.logger.WithPayload(map[string]interface{}{"endpointID": endpointID, "requestingUserID": userID}).Warn("User attempted to access unauthorized webhook endpoint")
+		return nil, nil
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints retrieves all webhook endpoints of a user.
+func (d *WebhookDispatcher) ListEndpoints(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+	endpoints, err := d.store.GetEndpointsByUserID(ctx, userID)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"userID": userID}).Error("Failed to list webhook endpoints from store")
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes an endpoint owned by a user. It reports whether the endpoint existed.
+func (d *WebhookDispatcher) DeleteEndpoint(ctx context.Context, endpointID, userID string) (bool, error) {
+	endpoint, err := d.GetEndpoint(ctx, endpointID, userID)
+	if err != nil || endpoint == nil {
+		return false, err
+	}
+
+	if err := d.store.DeleteEndpoint(ctx, endpointID); err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"endpointID": endpointID}).Error("Failed to delete webhook endpoint from store")
+		return false, err
+	}
+
+	d.mu.Lock()
+	delete(d.clients, endpointID)
+	d.mu.Unlock()
+	return true, nil
+}
+
+// ListDeliveries retrieves the delivery log of an endpoint owned by a user. It returns nil if not found or not owned.
+func (d *WebhookDispatcher) ListDeliveries(ctx context.Context, endpointID, userID string, page, limit int) ([]*models.WebhookDelivery, error) {
+	endpoint, err := d.GetEndpoint(ctx, endpointID, userID)
+	if err != nil || endpoint == nil {
+		return nil, err
+	}
+
+	deliveries, err := d.store.GetDeliveries(ctx, endpointID, page, limit)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"endpointID": endpointID}).Error("Failed to get webhook deliveries from store")
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver re-sends a logged delivery with its original payload. It returns nil if not found or not owned.
+func (d *WebhookDispatcher) Redeliver(ctx context.Context, deliveryID, userID string) (*models.WebhookDelivery, error) {
+	delivery, err := d.store.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"deliveryID": deliveryID}).Error("Failed to get webhook delivery from store")
+		return nil, err
+	}
+	if delivery == nil || delivery.UserID != userID {
+		return nil, nil
+	}
+
+	endpoint, err := d.GetEndpoint(ctx, delivery.EndpointID, userID)
+	if err != nil || endpoint == nil {
+		return nil, err
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	d.dispatch(endpoint, delivery)
+	return delivery, nil
+}
+
+// Notify creates and asynchronously sends a delivery to every endpoint subscribed to the task's terminal event.
+func (d *WebhookDispatcher) Notify(ctx context.Context, task *models.TaskRecord) {
+	event, ok := eventForStatus(task.Status)
+	if !ok {
+		return
+	}
+
+	endpoints, err := d.store.GetEndpointsForTask(ctx, task.UserID, task.ID)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"taskID": task.ID}).Error("Failed to get webhook endpoints for task")
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !subscribes(endpoint, event) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:         uuid.New().String(),
+			EndpointID: endpoint.ID,
+			UserID:     task.UserID,
+			TaskID:     task.ID,
+			Event:      event,
+			Status:     models.WebhookDeliveryPending,
+			CreatedAt:  time.Now(),
+		}
+		body, err := json.Marshal(webhookPayload{
+			DeliveryID: delivery.ID,
+			Event:      event,
+			Task: webhookTask{
+				ID:          task.ID,
+				UserID:      task.UserID,
+				Status:      task.Status,
+				Result:      task.Result,
+				Error:       task.Error,
+				SubmittedAt: task.SubmittedAt,
+				CompletedAt: task.CompletedAt,
+			},
+		})
+		if err != nil {
+			d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"taskID": task.ID}).Error("Failed to marshal webhook payload")
+			continue
+		}
+		delivery.Payload = string(body)
+
+		if err := d.store.CreateDelivery(ctx, delivery); err != nil {
+			d.logger.WithError(models.ErrorInfo{Message: err.Error()}).WithPayload(map[string]interface{}{"endpointID": endpoint.ID, "taskID": task.ID}).Error("Failed to create webhook delivery in store")
+			continue
+		}
+
+		d.dispatch(endpoint, delivery)
+	}
+}
+
+// dispatch delivers in the background, tracked for Shutdown. After Shutdown the delivery is left pending.
+func (d *WebhookDispatcher) dispatch(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		d.logger.WithPayload(map[string]interface{}{"deliveryID": delivery.ID}).Warn("Webhook dispatcher is shut down, leaving delivery pending")
+		return
+	}
+
+	d.deliveries.Add(1)
+	go func() {
+		defer d.deliveries.Done()
+		d.deliver(d.ctx, endpoint, delivery)
+	}()
+}
+
+// deliver attempts the delivery with exponential backoff until it succeeds, fails permanently or runs out of attempts.
+func (d *WebhookDispatcher) deliver(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) {
+	client, err := d.clientFor(endpoint.ID)
+	if err != nil {
+		d.logger.WithError(models.ErrorInfo{Message: err.Error()}).Error("Failed to create webhook HTTP client")
+		return
+	}
+
+	backoff := d.initialBackoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		code, retryable, err := d.send(ctx, client, endpoint, delivery)
+		if ctx.Err() != nil {
+			// Aborted by Shutdown; the attempt is not counted and the delivery stays pending.
+			return
+		}
+
+		delivery.Attempts++
+		delivery.ResponseCode = code
+		delivery.LastAttemptAt = time.Now()
+		if err == nil {
+			delivery.Status = models.WebhookDeliverySucceeded
+			delivery.Error = ""
+		} else {
+			delivery.Error = err.Error()
+			if !retryable || attempt == d.maxAttempts {
+				delivery.Status = models.WebhookDeliveryFailed
+			}
+		}
+		if updateErr := d.store.UpdateDelivery(ctx, delivery); updateErr != nil {
+			d.logger.WithError(models.ErrorInfo{Message: updateErr.Error()}).WithPayload(map[string]interface{}{"deliveryID": delivery.ID}).Error("Failed to update webhook delivery in store")
+		}
+
+		if delivery.Status != models.WebhookDeliveryPending {
+			if delivery.Status == models.WebhookDeliveryFailed {
+				d.logger.WithError(models.ErrorInfo{Message: delivery.Error}).WithPayload(map[string]interface{}{"deliveryID": delivery.ID, "endpointID": endpoint.ID, "attempts": delivery.Attempts}).Warn("Webhook delivery failed")
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send performs a single signed POST. It returns the response status code,
+// whether a failure is worth retrying, and an error if the receiver did not accept the event.
+func (d *WebhookDispatcher) send(ctx context.Context, client *pkghttp.Client, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, bool, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(endpoint.Secret, time.Now(), body))
+
+	resp, err := client.Do(req)
+	if errors.Is(err, pkghttp.ErrNonPublicAddress) {
+		// The endpoint's host resolves to a non-public address, which no retry will change.
+		return 0, false, err
+	}
+	if err != nil {
+		// Network errors, 5xx responses and an open circuit are all transient.
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+	return resp.StatusCode, retryable, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+}
+
+// clientFor returns the circuit-breaking HTTP client dedicated to an endpoint.
+func (d *WebhookDispatcher) clientFor(endpointID string) (*pkghttp.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if client, ok := d.clients[endpointID]; ok {
+		return client, nil
+	}
+	client, err := pkghttp.NewPublicClient(d.breakerCfg)
+	if err != nil {
+		return nil, err
+	}
+	d.clients[endpointID] = client
+	return client, nil
+}
+
+// SignWebhook computes the signature header value for a webhook body.
+// Receivers recompute HMAC-SHA256(secret, "<t>.<body>") and compare it with v1.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// eventForStatus maps a terminal task status to its webhook event.
+func eventForStatus(status models.TaskStatus) (models.WebhookEvent, bool) {
+	switch status {
+	case models.TaskStatusSuccess:
+		return models.WebhookEventTaskSucceeded, true
+	case models.TaskStatusFailed:
+		return models.WebhookEventTaskFailed, true
+	case models.TaskStatusCancelled:
+		return models.WebhookEventTaskCancelled, true
+	default:
+		return "", false
+	}
+}
+
+// subscribes reports whether an endpoint wants an event. An empty event list subscribes to all events.
+func subscribes(endpoint *models.WebhookEndpoint, event models.WebhookEvent) bool {
+	if len(endpoint.Events) == 0 {
+		return true
+	}
+	for _, e := range endpoint.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Jarvis_2.0/backend/go/internal/config"
+	"Jarvis_2.0/backend/go/internal/models"
+	pkghttp "Jarvis_2.0/backend/go/pkg/http"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	endpoint := &models.WebhookEndpoint{ID: "e1", URL: srv.URL, Secret: "secret"}
+	delivery := &models.WebhookDelivery{ID: "d1", Event: models.WebhookEventTaskSucceeded, Payload: `{}`}
+	// The test server listens on loopback, which only a client without the public address guard may reach.
+	client, err := pkghttp.NewClient(config.CircuitBreakerConfig{})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	tests := []struct {
+		status        int
+		wantRetryable bool
+		wantErr       bool
+	}{
+		{http.StatusNoContent, false, false},
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusRequestTimeout, true, true},
+		{http.StatusBadRequest, false, true},
+		{http.StatusGone, false, true},
+	}
+	for _, tt := range tests {
+		status = tt.status
+		code, retryable, err := (&WebhookDispatcher{}).send(context.Background(), client, endpoint, delivery)
+		if code != tt.status || retryable != tt.wantRetryable || (err != nil) != tt.wantErr {
+			t.Errorf("send() with status %d = %d, %v, %v; want retryable %v, error %v", tt.status, code, retryable, err, tt.wantRetryable, tt.wantErr)
+		}
+	}
+
+	publicClient, err := pkghttp.NewPublicClient(config.CircuitBreakerConfig{})
+	if err != nil {
+		t.Fatalf("NewPublicClient() error = %v", err)
+	}
+	_, retryable, err := (&WebhookDispatcher{}).send(context.Background(), publicClient, endpoint, delivery)
+	if !errors.Is(err, pkghttp.ErrNonPublicAddress) || retryable {
+		t.Errorf("send() to a loopback address = retryable %v, %v; want a permanent ErrNonPublicAddress", retryable, err)
+	}
+}
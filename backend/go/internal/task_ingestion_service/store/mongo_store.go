@@ -3,37 +3,101 @@ package store
 import (
 	"Jarvis_2.0/backend/go/internal/models"
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrDuplicateIdempotencyKey is returned when a task with the same idempotency key already exists for the user.
+var ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
+
+// terminalStatuses lists the task statuses that are never overwritten.
+var terminalStatuses = []models.TaskStatus{models.TaskStatusSuccess, models.TaskStatusFailed, models.TaskStatusCancelled}
+
 // TaskStore defines the interface for task persistence.
 type TaskStore interface {
 	Create(ctx context.Context, task *models.TaskRecord) error
+	CreateWithOutbox(ctx context.Context, task *models.TaskRecord, entry *models.OutboxEntry) error
 	GetByID(ctx context.Context, id string) (*models.TaskRecord, error)
+	GetByIdempotencyKey(ctx context.Context, userID, key string) (*models.TaskRecord, error)
 	GetByUserID(ctx context.Context, userID string, page, limit int) ([]*models.TaskRecord, error)
 	Update(ctx context.Context, task *models.TaskRecord) error
+	UpdateIfNotTerminal(ctx context.Context, task *models.TaskRecord) (bool, error)
+	Search(ctx context.Context, filter TaskFilter) ([]*models.TaskRecord, string, error)
+	Each(ctx context.Context, filter TaskFilter, fn func(*models.TaskRecord) error) error
+	Stats(ctx context.Context, filter TaskFilter) (*models.TaskStats, error)
 }
 
 // MongoTaskStore is an implementation of TaskStore using MongoDB.
 type MongoTaskStore struct {
+	client     *mongo.Client
 	collection *mongo.Collection
+	outbox     *mongo.Collection
 }
 
 // NewMongoTaskStore creates a new MongoTaskStore.
-func NewMongoTaskStore(db *mongo.Database, collectionName string) *MongoTaskStore {
+// The outbox collection must live in the same database so that tasks and outbox entries can be written in one transaction.
+func NewMongoTaskStore(db *mongo.Database, collectionName, outboxCollection string) *MongoTaskStore {
 	return &MongoTaskStore{
+		client:     db.Client(),
 		collection: db.Collection(collectionName),
+		outbox:     db.Collection(outboxCollection),
 	}
 }
 
+// EnsureIndexes creates the unique index that backs idempotency key deduplication,
+// the indexes used by task search and the text index over payload content, result and error.
+func (s *MongoTaskStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "submitted_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "completed_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{
+			// A text index only covers string values, so results that are objects are indexed through result_text.
+			Keys:    bson.D{{Key: "payload.content", Value: "text"}, {Key: "result", Value: "text"}, {Key: "result_text", Value: "text"}, {Key: "error", Value: "text"}},
+			Options: options.Index().SetName("task_text"),
+		},
+	})
+	return err
+}
+
 // Create inserts a new task record into the database.
 func (s *MongoTaskStore) Create(ctx context.Context, task *models.TaskRecord) error {
 	_, err := s.collection.InsertOne(ctx, task)
 	return err
 }
 
+// CreateWithOutbox inserts a task record and the outbox entry that publishes it in a single transaction.
+// Transactions require MongoDB to run as a replica set.
+// It returns ErrDuplicateIdempotencyKey if the user already submitted a task with the same idempotency key.
+func (s *MongoTaskStore) CreateWithOutbox(ctx context.Context, task *models.TaskRecord, entry *models.OutboxEntry) error {
+	session, err := s.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if _, err := s.collection.InsertOne(sessCtx, task); err != nil {
+			return nil, err
+		}
+		if _, err := s.outbox.InsertOne(sessCtx, entry); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if mongo.IsDuplicateKeyError(err) && task.IdempotencyKey != "" {
+		return ErrDuplicateIdempotencyKey
+	}
+	return err
+}
+
 // GetByID retrieves a task by its ID.
 func (s *MongoTaskStore) GetByID(ctx context.Context, id string) (*models.TaskRecord, error) {
 	var task models.TaskRecord
@@ -47,6 +111,19 @@ func (s *MongoTaskStore) GetByID(ctx context.Context, id string) (*models.TaskRe
 	return &task, nil
 }
 
+// GetByIdempotencyKey retrieves the task a user submitted with the given idempotency key.
+func (s *MongoTaskStore) GetByIdempotencyKey(ctx context.Context, userID, key string) (*models.TaskRecord, error) {
+	var task models.TaskRecord
+	err := s.collection.FindOne(ctx, bson.M{"user_id": userID, "idempotency_key": key}).Decode(&task)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
 // GetByUserID retrieves a paginated list of tasks for a specific user.
 func (s *MongoTaskStore) GetByUserID(ctx context.Context, userID string, page, limit int) ([]*models.TaskRecord, error) {
 	var tasks []*models.TaskRecord
@@ -74,6 +151,7 @@ func (s *MongoTaskStore) Update(ctx context.Context, task *models.TaskRecord) er
 		"$set": bson.M{
 			"status":       task.Status,
 			"result":       task.Result,
+			"result_text":  resultText(task.Result),
 			"error":        task.Error,
 			"completed_at": task.CompletedAt,
 		},
@@ -81,3 +159,23 @@ func (s *MongoTaskStore) Update(ctx context.Context, task *models.TaskRecord) er
 	_, err := s.collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// UpdateIfNotTerminal updates a task record unless it has already reached a terminal status.
+// It reports whether the record was updated.
+func (s *MongoTaskStore) UpdateIfNotTerminal(ctx context.Context, task *models.TaskRecord) (bool, error) {
+	filter := bson.M{"_id": task.ID, "status": bson.M{"$nin": terminalStatuses}}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       task.Status,
+			"result":       task.Result,
+			"result_text":  resultText(task.Result),
+			"error":        task.Error,
+			"completed_at": task.CompletedAt,
+		},
+	}
+	res, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxStore defines the interface used by the outbox relay to read and acknowledge outbox entries.
+type OutboxStore interface {
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxEntry, error)
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id string, errMsg string) error
+}
+
+// MongoOutboxStore is an implementation of OutboxStore using MongoDB.
+type MongoOutboxStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOutboxStore creates a new MongoOutboxStore.
+func NewMongoOutboxStore(db *mongo.Database, collectionName string) *MongoOutboxStore {
+	return &MongoOutboxStore{
+		collection: db.Collection(collectionName),
+	}
+}
+
+// ClaimPending atomically claims the oldest pending entry whose lease has expired, locking it for the lease duration.
+// It returns nil if there is nothing to publish.
+func (s *MongoOutboxStore) ClaimPending(ctx context.Context, now time.Time, lease time.Duration) (*models.OutboxEntry, error) {
+	filter := bson.M{
+		"status":       models.OutboxStatusPending,
+		"locked_until": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"locked_until": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var entry models.OutboxEntry
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// MarkPublished marks an entry as published.
+func (s *MongoOutboxStore) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.OutboxStatusPublished,
+			"published_at": publishedAt,
+			"last_error":   "",
+		},
+	}
+	_, err := s.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// MarkFailed records a failed publish attempt. The entry stays pending and is retried once its lease expires.
+func (s *MongoOutboxStore) MarkFailed(ctx context.Context, id string, errMsg string) error {
+	_, err := s.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_error": errMsg}})
+	return err
+}
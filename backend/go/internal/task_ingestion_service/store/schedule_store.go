@@ -0,0 +1,169 @@
+package store
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// ScheduleStore defines the interface for schedule and run history persistence.
+type ScheduleStore interface {
+	Create(ctx context.Context, schedule *models.Schedule) error
+	GetByID(ctx context.Context, id string) (*models.Schedule, error)
+	GetByUserID(ctx context.Context, userID string) ([]*models.Schedule, error)
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error)
+	Update(ctx context.Context, schedule *models.Schedule) error
+	Advance(ctx context.Context, id string, dueAt, nextRunAt, firedAt time.Time, disable bool) (bool, error)
+	Delete(ctx context.Context, id string) error
+	AddRun(ctx context.Context, run *models.ScheduleRun) error
+	GetRuns(ctx context.Context, scheduleID string, page, limit int) ([]*models.ScheduleRun, error)
+}
+
+// MongoScheduleStore is an implementation of ScheduleStore using MongoDB.
+type MongoScheduleStore struct {
+	schedules *mongo.Collection
+	runs      *mongo.Collection
+}
+
+// NewMongoScheduleStore creates a new MongoScheduleStore.
+func NewMongoScheduleStore(db *mongo.Database, scheduleCollection, runCollection string) *MongoScheduleStore {
+	return &MongoScheduleStore{
+		schedules: db.Collection(scheduleCollection),
+		runs:      db.Collection(runCollection),
+	}
+}
+
+// Create inserts a new schedule into the database.
+func (s *MongoScheduleStore) Create(ctx context.Context, schedule *models.Schedule) error {
+	_, err := s.schedules.InsertOne(ctx, schedule)
+	return err
+}
+
+// GetByID retrieves a schedule by its ID.
+func (s *MongoScheduleStore) GetByID(ctx context.Context, id string) (*models.Schedule, error) {
+	var schedule models.Schedule
+	err := s.schedules.FindOne(ctx, bson.M{"_id": id}).Decode(&schedule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetByUserID retrieves all schedules of a specific user.
+func (s *MongoScheduleStore) GetByUserID(ctx context.Context, userID string) ([]*models.Schedule, error) {
+	schedules := []*models.Schedule{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.schedules.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// GetDue retrieves enabled schedules whose next run time is not after now.
+func (s *MongoScheduleStore) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.Schedule, error) {
+	var schedules []*models.Schedule
+	filter := bson.M{
+		"enabled":     true,
+		"next_run_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "next_run_at", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := s.schedules.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Update replaces the mutable fields of an existing schedule.
+func (s *MongoScheduleStore) Update(ctx context.Context, schedule *models.Schedule) error {
+	filter := bson.M{"_id": schedule.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"name":             schedule.Name,
+			"content":          schedule.Content,
+			"cron_expr":        schedule.CronExpr,
+			"interval_seconds": schedule.IntervalSeconds,
+			"timezone":         schedule.Timezone,
+			"enabled":          schedule.Enabled,
+			"next_run_at":      schedule.NextRunAt,
+			"last_run_at":      schedule.LastRunAt,
+			"updated_at":       schedule.UpdatedAt,
+		},
+	}
+	_, err := s.schedules.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// Advance records a run of a schedule that was due at dueAt and moves it to nextRunAt, disabling it if requested.
+// Only the run times are written, and only if next_run_at still equals dueAt, so that an edit made after the schedule
+// was read is kept. It reports whether the schedule was advanced.
+func (s *MongoScheduleStore) Advance(ctx context.Context, id string, dueAt, nextRunAt, firedAt time.Time, disable bool) (bool, error) {
+	filter := bson.M{"_id": id, "next_run_at": dueAt}
+	set := bson.M{
+		"next_run_at": nextRunAt,
+		"last_run_at": firedAt,
+		"updated_at":  firedAt,
+	}
+	if disable {
+		set["enabled"] = false
+	}
+	result, err := s.schedules.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// Delete removes a schedule and its run history.
+func (s *MongoScheduleStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.schedules.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return err
+	}
+	_, err := s.runs.DeleteMany(ctx, bson.M{"schedule_id": id})
+	return err
+}
+
+// AddRun inserts a run history entry for a schedule.
+func (s *MongoScheduleStore) AddRun(ctx context.Context, run *models.ScheduleRun) error {
+	_, err := s.runs.InsertOne(ctx, run)
+	return err
+}
+
+// GetRuns retrieves a paginated run history for a schedule, newest first.
+func (s *MongoScheduleStore) GetRuns(ctx context.Context, scheduleID string, page, limit int) ([]*models.ScheduleRun, error) {
+	runs := []*models.ScheduleRun{}
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "fired_at", Value: -1}})
+	opts.SetSkip(int64((page - 1) * limit))
+	opts.SetLimit(int64(limit))
+
+	cursor, err := s.runs.Find(ctx, bson.M{"schedule_id": scheduleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,253 @@
+package store
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sortable task fields.
+const (
+	SortBySubmittedAt = "submitted_at"
+	SortByCompletedAt = "completed_at"
+)
+
+// TaskFilter describes a search over a user's tasks.
+type TaskFilter struct {
+	UserID   string
+	Statuses []models.TaskStatus
+	From     time.Time // inclusive lower bound on submitted_at; zero means unbounded
+	To       time.Time // exclusive upper bound on submitted_at; zero means unbounded
+	Text     string    // full-text search over payload content, result and error
+	SortBy   string    // SortBySubmittedAt or SortByCompletedAt
+	Asc      bool
+	Cursor   string // opaque cursor returned by the previous page
+	Limit    int
+}
+
+// taskCursor is the position after the last task of a page: its sort key and ID as a tie-breaker.
+type taskCursor struct {
+	Value time.Time `json:"v"`
+	ID    string    `json:"id"`
+}
+
+func encodeCursor(value time.Time, id string) string {
+	b, _ := json.Marshal(taskCursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*taskCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c taskCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// bsonFilter translates the filter (without the cursor) into a MongoDB query.
+func (f TaskFilter) bsonFilter() bson.M {
+	filter := bson.M{"user_id": f.UserID}
+	if len(f.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": f.Statuses}
+	}
+	submitted := bson.M{}
+	if !f.From.IsZero() {
+		submitted["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		submitted["$lt"] = f.To
+	}
+	if len(submitted) > 0 {
+		filter["submitted_at"] = submitted
+	}
+	if f.Text != "" {
+		filter["$text"] = bson.M{"$search": f.Text}
+	}
+	return filter
+}
+
+func (f TaskFilter) sortField() string {
+	if f.SortBy == SortByCompletedAt {
+		return SortByCompletedAt
+	}
+	return SortBySubmittedAt
+}
+
+func (f TaskFilter) findOptions() *options.FindOptions {
+	order := -1
+	if f.Asc {
+		order = 1
+	}
+	return options.Find().SetSort(bson.D{{Key: f.sortField(), Value: order}, {Key: "_id", Value: order}})
+}
+
+// sortValue returns the value of the sort field of a task.
+func (f TaskFilter) sortValue(task *models.TaskRecord) time.Time {
+	if f.sortField() == SortByCompletedAt {
+		return task.CompletedAt
+	}
+	return task.SubmittedAt
+}
+
+// Search returns one page of tasks matching the filter and the cursor of the next page.
+// The next cursor is empty when there are no more results.
+func (s *MongoTaskStore) Search(ctx context.Context, f TaskFilter) ([]*models.TaskRecord, string, error) {
+	filter := f.bsonFilter()
+	if f.Cursor != "" {
+		cursor, err := decodeCursor(f.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		op := "$lt"
+		if f.Asc {
+			op = "$gt"
+		}
+		field := f.sortField()
+		filter["$or"] = bson.A{
+			bson.M{field: bson.M{op: cursor.Value}},
+			bson.M{field: cursor.Value, "_id": bson.M{op: cursor.ID}},
+		}
+	}
+
+	// Fetch one extra record to know whether there is a next page.
+	opts := f.findOptions().SetLimit(int64(f.Limit + 1))
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*models.TaskRecord{}
+	if err = cursor.All(ctx, &tasks); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if len(tasks) > f.Limit {
+		tasks = tasks[:f.Limit]
+		last := tasks[len(tasks)-1]
+		next = encodeCursor(f.sortValue(last), last.ID)
+	}
+	return tasks, next, nil
+}
+
+// Each streams every task matching the filter (ignoring cursor and limit) to fn, stopping at the first error.
+func (s *MongoTaskStore) Each(ctx context.Context, f TaskFilter, fn func(*models.TaskRecord) error) error {
+	cursor, err := s.collection.Find(ctx, f.bsonFilter(), f.findOptions())
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var task models.TaskRecord
+		if err := cursor.Decode(&task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Stats aggregates the tasks matching the filter: counts by status and the average duration of terminal tasks.
+func (s *MongoTaskStore) Stats(ctx context.Context, f TaskFilter) (*models.TaskStats, error) {
+	completed := bson.M{"$gt": bson.A{"$completed_at", "$submitted_at"}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: f.bsonFilter()}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$status",
+			"count": bson.M{"$sum": 1},
+			"timed": bson.M{"$sum": bson.M{"$cond": bson.A{completed, 1, 0}}},
+			"duration_ms": bson.M{"$sum": bson.M{"$cond": bson.A{
+				completed,
+				bson.M{"$subtract": bson.A{"$completed_at", "$submitted_at"}},
+				0,
+			}}},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status     models.TaskStatus `bson:"_id"`
+		Count      int64             `bson:"count"`
+		Timed      int64             `bson:"timed"`
+		DurationMs float64           `bson:"duration_ms"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	stats := &models.TaskStats{ByStatus: map[models.TaskStatus]int64{}}
+	var durationMs float64
+	var timed int64
+	for _, g := range groups {
+		stats.Total += g.Count
+		stats.ByStatus[g.Status] = g.Count
+		if g.Status.IsTerminal() {
+			durationMs += g.DurationMs
+			timed += g.Timed
+		}
+	}
+	if timed > 0 {
+		stats.AvgDurationSeconds = durationMs / float64(timed) / 1000
+	}
+	return stats, nil
+}
+
+// resultText joins the strings nested in a structured task result for the text index, which skips non-string values.
+// String results are indexed as they are, so it returns "" for them.
+func resultText(result interface{}) string {
+	if _, ok := result.(string); ok || result == nil {
+		return ""
+	}
+	// Round-trip through JSON to walk results of any Go type as maps, slices and scalars.
+	b, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return ""
+	}
+
+	var parts []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			parts = append(parts, v)
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return strings.Join(parts, " ")
+}
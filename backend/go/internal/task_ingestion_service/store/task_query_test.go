@@ -0,0 +1,77 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	value := time.Date(2024, 5, 1, 12, 30, 0, 123000000, time.UTC)
+	c, err := decodeCursor(encodeCursor(value, "task-1"))
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if !c.Value.Equal(value) || c.ID != "task-1" {
+		t.Errorf("decodeCursor() = %+v, want {%v task-1}", c, value)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	valid := encodeCursor(time.Now(), "task-1")
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.StdEncoding.EncodeToString([]byte(`{"v":"2024-05-01T00:00:00Z","id":"a"}`)) + "="},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("task-1"))},
+		{"missing id", base64.RawURLEncoding.EncodeToString([]byte(`{"v":"2024-05-01T00:00:00Z"}`))},
+		{"wrong value type", base64.RawURLEncoding.EncodeToString([]byte(`{"v":42,"id":"a"}`))},
+		{"truncated", valid[:len(valid)/2]},
+		{"tampered", strings.Replace(valid, valid[:4], "AAAA", 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestResultText(t *testing.T) {
+	type report struct {
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+		Count int      `json:"count"`
+	}
+	tests := []struct {
+		name   string
+		result interface{}
+		want   []string // Words expected in the text, in any order since map keys are unordered
+	}{
+		{"nil", nil, nil},
+		{"string results are indexed as they are", "plain answer", nil},
+		{"map", map[string]interface{}{"summary": "weekly report", "score": 0.9}, []string{"weekly", "report"}},
+		{"nested", map[string]interface{}{"items": []interface{}{"alpha", map[string]interface{}{"name": "beta"}}}, []string{"alpha", "beta"}},
+		{"struct", report{Title: "quarterly", Tags: []string{"sales", "europe"}, Count: 3}, []string{"quarterly", "sales", "europe"}},
+		{"no strings", []interface{}{1, 2.5, true}, nil},
+		{"not encodable", map[string]interface{}{"f": func() {}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Fields(resultText(tt.result))
+			if len(got) != len(tt.want) {
+				t.Fatalf("resultText() = %q, want the words %q", got, tt.want)
+			}
+			for _, word := range tt.want {
+				if !strings.Contains(" "+strings.Join(got, " ")+" ", " "+word+" ") {
+					t.Errorf("resultText() = %q, missing %q", got, word)
+				}
+			}
+		})
+	}
+}
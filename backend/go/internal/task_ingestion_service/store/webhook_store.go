@@ -0,0 +1,147 @@
+package store
+
+import (
+	"Jarvis_2.0/backend/go/internal/models"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookStore defines the interface for webhook endpoint and delivery log persistence.
+type WebhookStore interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error)
+	GetEndpointsByUserID(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error)
+	GetEndpointsForTask(ctx context.Context, userID, taskID string) ([]*models.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, endpointID string, page, limit int) ([]*models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// MongoWebhookStore is an implementation of WebhookStore using MongoDB.
+type MongoWebhookStore struct {
+	endpoints  *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// NewMongoWebhookStore creates a new MongoWebhookStore.
+func NewMongoWebhookStore(db *mongo.Database, endpointCollection, deliveryCollection string) *MongoWebhookStore {
+	return &MongoWebhookStore{
+		endpoints:  db.Collection(endpointCollection),
+		deliveries: db.Collection(deliveryCollection),
+	}
+}
+
+// CreateEndpoint inserts a new webhook endpoint into the database.
+func (s *MongoWebhookStore) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	_, err := s.endpoints.InsertOne(ctx, endpoint)
+	return err
+}
+
+// GetEndpoint retrieves a webhook endpoint by its ID.
+func (s *MongoWebhookStore) GetEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := s.endpoints.FindOne(ctx, bson.M{"_id": id}).Decode(&endpoint)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// GetEndpointsByUserID retrieves all webhook endpoints of a user.
+func (s *MongoWebhookStore) GetEndpointsByUserID(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+	return s.findEndpoints(ctx, bson.M{"user_id": userID})
+}
+
+// GetEndpointsForTask retrieves the enabled endpoints of a user that apply to a task:
+// global endpoints (no task ID) and endpoints registered for that specific task.
+func (s *MongoWebhookStore) GetEndpointsForTask(ctx context.Context, userID, taskID string) ([]*models.WebhookEndpoint, error) {
+	return s.findEndpoints(ctx, bson.M{
+		"user_id": userID,
+		"enabled": true,
+		"task_id": bson.M{"$in": []string{"", taskID}},
+	})
+}
+
+func (s *MongoWebhookStore) findEndpoints(ctx context.Context, filter bson.M) ([]*models.WebhookEndpoint, error) {
+	endpoints := []*models.WebhookEndpoint{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := s.endpoints.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes a webhook endpoint. Its delivery log is kept for auditing.
+func (s *MongoWebhookStore) DeleteEndpoint(ctx context.Context, id string) error {
+	_, err := s.endpoints.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// CreateDelivery inserts a new delivery log entry.
+func (s *MongoWebhookStore) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := s.deliveries.InsertOne(ctx, delivery)
+	return err
+}
+
+// GetDelivery retrieves a delivery log entry by its ID.
+func (s *MongoWebhookStore) GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := s.deliveries.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetDeliveries retrieves a paginated delivery log for an endpoint, newest first.
+func (s *MongoWebhookStore) GetDeliveries(ctx context.Context, endpointID string, page, limit int) ([]*models.WebhookDelivery, error) {
+	deliveries := []*models.WebhookDelivery{}
+	opts := options.Find()
+	opts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	opts.SetSkip(int64((page - 1) * limit))
+	opts.SetLimit(int64(limit))
+
+	cursor, err := s.deliveries.Find(ctx, bson.M{"endpoint_id": endpointID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of the latest delivery attempt.
+func (s *MongoWebhookStore) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	filter := bson.M{"_id": delivery.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"error":           delivery.Error,
+			"last_attempt_at": delivery.LastAttemptAt,
+		},
+	}
+	_, err := s.deliveries.UpdateOne(ctx, filter, update)
+	return err
+}
@@ -35,6 +35,20 @@ func NewClient(cfg config.CircuitBreakerConfig) (*Client, error) {
 	}, nil
 }
 
+// NewPublicClient creates a new Client like NewClient that only connects to public addresses. It is meant for
+// requests to user-supplied URLs, which must not reach services inside the cluster.
+func NewPublicClient(cfg config.CircuitBreakerConfig) (*Client, error) {
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.httpClient = &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: NewPublicTransport(),
+	}
+	return client, nil
+}
+
 // Do executes an HTTP request with circuit breaker protection.
 // It considers status codes >= 500 as failures.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
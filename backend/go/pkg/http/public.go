@@ -0,0 +1,76 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress is returned when a connection to a loopback, private, link-local or otherwise non-public
+// address is refused.
+var ErrNonPublicAddress = errors.New("refusing to connect to a non-public address")
+
+// nonPublicNetworks are the reserved ranges not covered by the net.IP classification methods.
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved, including broadcast
+	"64:ff9b::/96",  // NAT64, which can embed any IPv4 address
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicOnlyControl is a net.Dialer Control function refusing non-public addresses. It runs on the resolved address
+// of every connection attempt, so the check cannot be bypassed with a host name, a redirect or DNS rebinding.
+func publicOnlyControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+	}
+	return nil
+}
+
+// NewPublicTransport creates a transport like http.DefaultTransport that only connects to public addresses, for
+// requests to user-supplied URLs. Proxies are not used, since a proxy would connect on the client's behalf.
+func NewPublicTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnlyControl,
+	}).DialContext
+	return transport
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"Jarvis_2.0/backend/go/internal/config"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestNewPublicClient_RefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+
+	client, err := NewPublicClient(config.CircuitBreakerConfig{})
+	if err != nil {
+		t.Fatalf("NewPublicClient() error = %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("Do() error = %v, want ErrNonPublicAddress", err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 // DocxConverter handles loading and converting DOC and DOCX files to markdown.
 type DocxConverter struct {
 	BaseConverter
+	keepDataURIs bool // Embed images as data URIs instead of writing them to the working directory
 }
 
 // NewDocxConverter creates a new DOC converter with appropriate MIME types and extensions.
@@ -36,9 +37,17 @@ func NewDocxConverter() Converter {
 	}
 }
 
+// NewDocxConverterWithDataURIs creates a DOC converter that embeds images in the markdown as data URIs,
+// like the PPTX converter, instead of writing them to the working directory.
+func NewDocxConverterWithDataURIs() Converter {
+	c := NewDocxConverter().(*DocxConverter)
+	c.keepDataURIs = true
+	return c
+}
+
 // Load reads a DOC or DOCX file and converts it to markdown.
-func (*DocxConverter) Load(filePath string) (string, error) {
-	content, err := convertDocxToMarkdown(filePath)
+func (c *DocxConverter) Load(filePath string) (string, error) {
+	content, err := convertDocxToMarkdown(filePath, c.keepDataURIs)
 	if err != nil {
 		return "", fmt.Errorf("failed to convert document: %w", err)
 	}
@@ -174,9 +183,11 @@ func escape(s, set string) string {
 }
 
 func (zf *file) extract(rel *Relationship, w io.Writer) error {
-	err := os.MkdirAll(filepath.Dir(rel.Target), 0o755)
-	if err != nil {
-		return err
+	if !zf.embed {
+		err := os.MkdirAll(filepath.Dir(rel.Target), 0o755)
+		if err != nil {
+			return err
+		}
 	}
 	for _, f := range zf.r.File {
 		if f.Name != "word/"+rel.Target {
@@ -189,7 +200,7 @@ func (zf *file) extract(rel *Relationship, w io.Writer) error {
 		defer rc.Close()
 
 		b := make([]byte, f.UncompressedSize64)
-		n, err := rc.Read(b)
+		n, err := io.ReadFull(rc, b)
 		if err != nil && !errors.Is(err, io.EOF) {
 			return err
 		}
@@ -647,7 +658,7 @@ func findFile(files []*zip.File, target string) *zip.File {
 	return nil
 }
 
-func convertDocxToMarkdown(filePath string) (string, error) {
+func convertDocxToMarkdown(filePath string, embed bool) (string, error) {
 	r, err := zip.OpenReader(filePath)
 	if err != nil {
 		return "", err
@@ -699,10 +710,11 @@ func convertDocxToMarkdown(filePath string) (string, error) {
 
 	var buf bytes.Buffer
 	zf := &file{
-		r:    r,
-		rels: rels,
-		num:  num,
-		list: make(map[string]int),
+		r:     r,
+		rels:  rels,
+		num:   num,
+		embed: embed,
+		list:  make(map[string]int),
 	}
 	err = zf.walk(node, &buf)
 	if err != nil {
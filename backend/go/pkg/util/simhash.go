@@ -0,0 +1,49 @@
+package util
+
+import (
+	"hash/fnv"
+	"math/bits"
+)
+
+// SimHash 计算特征集合的 64 位 SimHash 指纹。
+// 内容相近的文本得到的指纹只有少数几位不同，可用汉明距离衡量两段文本的相似程度。
+// 每个特征权重相同，重复出现的特征按出现次数计权。
+func SimHash(features []string) uint64 {
+	var weights [64]int
+	for _, feature := range features {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for i, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+	return fingerprint
+}
+
+// HammingDistance 返回两个指纹中不同位的数量。
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashBands 将指纹切分为 4 个 16 位的分段。
+// 汉明距离不超过 3 的两个指纹至少有一个分段完全相同，因此可以按分段精确查找候选指纹。
+func SimHashBands(fingerprint uint64) [4]uint16 {
+	return [4]uint16{
+		uint16(fingerprint),
+		uint16(fingerprint >> 16),
+		uint16(fingerprint >> 32),
+		uint16(fingerprint >> 48),
+	}
+}
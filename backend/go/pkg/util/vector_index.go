@@ -0,0 +1,196 @@
+package util
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// VectorEntry 是索引中的一条向量及其标量字段。
+// 字段已设为可导出，以便 gob 序列化。
+type VectorEntry struct {
+	Vector []float32
+	Fields map[string]string
+}
+
+// VectorHit 是一次搜索的命中结果。Distance 为 L2 距离的平方，与 Milvus 的 L2 度量一致，越小越相似。
+type VectorHit struct {
+	ID       string
+	Distance float32
+	Fields   map[string]string
+}
+
+// FlatIndex 是一个纯内存、线程安全且可持久化的精确向量索引。
+// 搜索时逐条计算距离（暴力搜索），结果精确，适合本地开发、测试和中小规模的离线部署。
+type FlatIndex struct {
+	entries map[string]VectorEntry
+	lock    sync.RWMutex
+}
+
+// NewFlatIndex 创建一个空的向量索引。
+func NewFlatIndex() *FlatIndex {
+	return &FlatIndex{entries: make(map[string]VectorEntry)}
+}
+
+// Upsert 插入一条向量；若 ID 已存在则覆盖。
+func (idx *FlatIndex) Upsert(id string, vector []float32, fields map[string]string) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+	idx.entries[id] = VectorEntry{Vector: vector, Fields: fields}
+}
+
+// Delete 删除指定 ID 的向量，返回实际删除的数量。
+func (idx *FlatIndex) Delete(ids ...string) int {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	deleted := 0
+	for _, id := range ids {
+		if _, ok := idx.entries[id]; ok {
+			delete(idx.entries, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Get 返回指定 ID 的向量条目。
+func (idx *FlatIndex) Get(id string) (VectorEntry, bool) {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+	entry, ok := idx.entries[id]
+	return entry, ok
+}
+
+// Len 返回索引中的向量数量。
+func (idx *FlatIndex) Len() int {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+	return len(idx.entries)
+}
+
+// Search 返回与 query 距离最近的 topK 条向量，按距离升序排列。
+// match 为可选的过滤函数，只有满足条件的向量才会参与排序；维度与 query 不同的向量会被跳过。
+func (idx *FlatIndex) Search(query []float32, topK int, match func(fields map[string]string) bool) []VectorHit {
+	if topK <= 0 {
+		return nil
+	}
+
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	// 用大小为 topK 的最大堆保存当前最近的结果，堆顶是其中最远的一条
+	h := make(hitHeap, 0, min(topK, len(idx.entries)))
+	for id, entry := range idx.entries {
+		if len(entry.Vector) != len(query) {
+			continue
+		}
+		if match != nil && !match(entry.Fields) {
+			continue
+		}
+		dist := squaredL2(query, entry.Vector)
+		if len(h) < topK {
+			heap.Push(&h, VectorHit{ID: id, Distance: dist, Fields: entry.Fields})
+		} else if closerHit(VectorHit{ID: id, Distance: dist}, h[0]) {
+			h[0] = VectorHit{ID: id, Distance: dist, Fields: entry.Fields}
+			heap.Fix(&h, 0)
+		}
+	}
+
+	hits := make([]VectorHit, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		hits[i] = heap.Pop(&h).(VectorHit)
+	}
+	return hits
+}
+
+// --- 持久化功能 ---
+
+// WriteToFile 将索引序列化并写入文件。
+// 只在编码时持有读锁，写文件期间不阻塞写入；先写入同目录下的临时文件再重命名，因此进程崩溃不会留下被截断的快照。
+func (idx *FlatIndex) WriteToFile(filePath string) error {
+	var buf bytes.Buffer
+	idx.lock.RLock()
+	err := gob.NewEncoder(&buf).Encode(idx.entries)
+	idx.lock.RUnlock()
+	if err != nil {
+		return fmt.Errorf("gob编码失败: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("替换文件失败: %w", err)
+	}
+	return nil
+}
+
+// NewFlatIndexFromFile 从文件加载并创建一个新的向量索引。
+// 文件不存在时返回的错误满足 errors.Is(err, os.ErrNotExist)。
+func NewFlatIndexFromFile(filePath string) (*FlatIndex, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	idx := NewFlatIndex()
+	if err := gob.NewDecoder(file).Decode(&idx.entries); err != nil {
+		return nil, fmt.Errorf("gob解码失败: %w", err)
+	}
+	if idx.entries == nil {
+		idx.entries = make(map[string]VectorEntry)
+	}
+	return idx, nil
+}
+
+// squaredL2 计算两个等长向量之间 L2 距离的平方。
+func squaredL2(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// closerHit 判断命中 a 是否比 b 更相似。距离相同时按 ID 排序，保证结果稳定。
+func closerHit(a, b VectorHit) bool {
+	if a.Distance != b.Distance {
+		return a.Distance < b.Distance
+	}
+	return a.ID < b.ID
+}
+
+// hitHeap 是按相似度排序的最大堆，堆顶是最不相似的命中。
+type hitHeap []VectorHit
+
+func (h hitHeap) Len() int           { return len(h) }
+func (h hitHeap) Less(i, j int) bool { return closerHit(h[j], h[i]) }
+func (h hitHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *hitHeap) Push(x any)        { *h = append(*h, x.(VectorHit)) }
+func (h *hitHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
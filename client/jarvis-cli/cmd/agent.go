@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"github.com/gorilla/websocket"
 	"github.com/spf13/cobra"
@@ -38,10 +41,149 @@ var watchCmd = &cobra.Command{
 	},
 }
 
+var (
+	listStatus string
+	listFrom   string
+	listTo     string
+	listQuery  string
+	listSort   string
+	listOrder  string
+	listLimit  int
+	listCursor string
+
+	exportFormat string
+	exportOutput string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List and search your tasks",
+	Run: func(cmd *cobra.Command, args []string) {
+		listTasks()
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your tasks as JSONL or CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		exportTasks()
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(agentCmd)
 	agentCmd.AddCommand(submitCmd)
 	agentCmd.AddCommand(watchCmd)
+	agentCmd.AddCommand(listCmd)
+	agentCmd.AddCommand(exportCmd)
+
+	for _, c := range []*cobra.Command{listCmd, exportCmd} {
+		c.Flags().StringVar(&listStatus, "status", "", "Comma separated statuses to include, e.g. success,failed")
+		c.Flags().StringVar(&listFrom, "from", "", "Only tasks submitted at or after this time (RFC3339)")
+		c.Flags().StringVar(&listTo, "to", "", "Only tasks submitted before this time (RFC3339)")
+		c.Flags().StringVarP(&listQuery, "query", "q", "", "Full-text search over payload, result and error")
+		c.Flags().StringVar(&listSort, "sort", "submitted_at", "Sort field: submitted_at or completed_at")
+		c.Flags().StringVar(&listOrder, "order", "desc", "Sort order: asc or desc")
+	}
+	listCmd.Flags().IntVar(&listLimit, "limit", 20, "Maximum number of tasks to return")
+	listCmd.Flags().StringVar(&listCursor, "cursor", "", "Cursor returned by a previous list call")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "jsonl", "Export format: jsonl or csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file (default: stdout)")
+}
+
+// taskQuery builds the query string shared by the list and export commands.
+func taskQuery() url.Values {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"status": listStatus,
+		"from":   listFrom,
+		"to":     listTo,
+		"q":      listQuery,
+		"sort":   listSort,
+		"order":  listOrder,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+func listTasks() {
+	query := taskQuery()
+	query.Set("limit", strconv.Itoa(listLimit))
+	if listCursor != "" {
+		query.Set("cursor", listCursor)
+	}
+
+	resp, err := http.Get("http://localhost:8081/api/v1/tasks/search?" + query.Encode())
+	if err != nil {
+		log.Fatalf("Error listing tasks: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Failed to list tasks, status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		Tasks []struct {
+			ID          string
+			Status      string
+			SubmittedAt string
+			Error       string
+		} `json:"tasks"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Fatalf("Error decoding response: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK ID\tSTATUS\tSUBMITTED AT\tERROR")
+	for _, task := range result.Tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", task.ID, task.Status, task.SubmittedAt, task.Error)
+	}
+	w.Flush()
+
+	if result.NextCursor != "" {
+		fmt.Printf("\nMore tasks available, run again with: --cursor %s\n", result.NextCursor)
+	}
+}
+
+func exportTasks() {
+	query := taskQuery()
+	query.Set("format", exportFormat)
+
+	resp, err := http.Get("http://localhost:8081/api/v1/tasks/export?" + query.Encode())
+	if err != nil {
+		log.Fatalf("Error exporting tasks: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Failed to export tasks, status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	out := os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			log.Fatalf("Error creating output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		log.Fatalf("Error writing export: %v", err)
+	}
+	if exportOutput != "" {
+		fmt.Printf("Tasks exported to %s\n", exportOutput)
+	}
 }
 
 func submitTask(description string) {
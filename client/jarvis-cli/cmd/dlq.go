@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dlqPartition int
+	dlqOffset    int64
+	dlqLimit     int
+)
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect and replay dead-letter Kafka messages",
+}
+
+var dlqListCmd = &cobra.Command{
+	Use:   "list [dlq-topic]",
+	Short: "List messages in a dead-letter topic, e.g. agent_tasks.dlq",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		listDeadLetters(args[0])
+	},
+}
+
+var dlqReplayCmd = &cobra.Command{
+	Use:   "replay [dlq-topic] [partition] [offset]",
+	Short: "Replay a dead-letter message to the consumer group that failed it",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		replayDeadLetter(args[0], args[1], args[2])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dlqCmd)
+	dlqCmd.AddCommand(dlqListCmd)
+	dlqCmd.AddCommand(dlqReplayCmd)
+
+	dlqListCmd.Flags().IntVar(&dlqPartition, "partition", 0, "Partition to read from")
+	dlqListCmd.Flags().Int64Var(&dlqOffset, "offset", -1, "Offset to start from (default: earliest)")
+	dlqListCmd.Flags().IntVar(&dlqLimit, "limit", 10, "Maximum number of messages to list")
+}
+
+func listDeadLetters(topic string) {
+	query := url.Values{}
+	query.Set("partition", strconv.Itoa(dlqPartition))
+	query.Set("offset", strconv.FormatInt(dlqOffset, 10))
+	query.Set("limit", strconv.Itoa(dlqLimit))
+	apiURL := "http://localhost:8081/api/v1/admin/dlq/" + url.PathEscape(topic) + "?" + query.Encode()
+
+	resp, err := http.Get(apiURL)
+	if err != nil {
+		log.Fatalf("Error listing dead-letter messages: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatalf("Error reading response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("Failed to list dead-letter messages, status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, body, "", "  "); err != nil {
+		fmt.Println(string(body))
+		return
+	}
+	fmt.Println(prettyJSON.String())
+}
+
+func replayDeadLetter(topic, partition, offset string) {
+	apiURL := fmt.Sprintf("http://localhost:8081/api/v1/admin/dlq/%s/%s/%s/replay", url.PathEscape(topic), partition, offset)
+
+	resp, err := http.Post(apiURL, "application/json", nil)
+	if err != nil {
+		log.Fatalf("Error replaying dead-letter message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Failed to replay dead-letter message, status code: %d, body: %s", resp.StatusCode, body)
+	}
+
+	var result map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		log.Fatalf("Error decoding response: %v", err)
+	}
+
+	fmt.Printf("Message %s/%s/%s replayed onto %s\n", topic, partition, offset, result["replayed_to"])
+}